@@ -28,6 +28,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	k8sclientset "k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -42,6 +43,7 @@ import (
 	"github.com/numaproj/numaplane/internal/util/kubernetes"
 	"github.com/numaproj/numaplane/internal/util/logger"
 	"github.com/numaproj/numaplane/internal/util/metrics"
+	numaplanewebhook "github.com/numaproj/numaplane/internal/webhook"
 	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
 )
 
@@ -67,6 +69,7 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var enableWebhooks bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -76,6 +79,8 @@ func main() {
 		"If set the metrics endpoint is served securely")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"If set, the optional webhooks which default and validate Rollouts are served (this requires the config/webhook manifests and a serving certificate for the webhook server); the controller enforces the same rules without them")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -148,6 +153,19 @@ func main() {
 		numaLogger.Fatal(err, "Failed to set dynamic client")
 	}
 
+	// rebuild the pause requests from before any restart, before any Rollout is reconciled
+	k8sClientSet, err := k8sclientset.NewForConfig(newRawConfig)
+	if err != nil {
+		numaLogger.Fatal(err, "Failed to create kubernetes client")
+	}
+	numaplaneNamespace, err := kubernetes.GetNumaplaneNamespace()
+	if err != nil {
+		numaLogger.Fatal(err, "Failed to get Numaplane namespace")
+	}
+	if err := controller.LoadPauseModule(ctx, k8sClientSet, mgr.GetAPIReader(), numaplaneNamespace); err != nil {
+		numaLogger.Fatal(err, "Failed to load pause requests")
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	pipelineRolloutReconciler := controller.NewPipelineRolloutReconciler(
@@ -201,6 +219,12 @@ func main() {
 		numaLogger.Fatal(err, "Unable to set up MonoVertexRollout controller")
 	}
 
+	if enableWebhooks {
+		if err = numaplanewebhook.SetupWebhooksWithManager(mgr); err != nil {
+			numaLogger.Fatal(err, "Unable to set up webhooks")
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
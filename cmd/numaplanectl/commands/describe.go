@@ -0,0 +1,198 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func (cli *cli) newDescribeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe KIND NAME",
+		Short: "Show the details of a Rollout: its conditions, its children and what's pausing them",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, err := parseRolloutKind(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := cli.getClients()
+			if err != nil {
+				return err
+			}
+			r, err := getRollout(cmd.Context(), c, kind, args[1])
+			if err != nil {
+				return err
+			}
+			var children []unstructured.Unstructured
+			if r.childResource != "" {
+				if children, err = listChildren(cmd.Context(), c, r); err != nil {
+					return err
+				}
+			}
+			return describeRollout(cmd.OutOrStdout(), r, children)
+		},
+	}
+}
+
+// listChildren lists the Numaflow children of the Rollout, sorted by name
+func listChildren(ctx context.Context, c *clients, r *rollout) ([]unstructured.Unstructured, error) {
+	children, err := c.dynamic.Resource(r.childGroupVersionResource()).Namespace(c.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", common.LabelKeyParentRollout, r.objectMeta.Name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s of %s %s/%s: %w", r.childResource, r.kind, c.namespace, r.objectMeta.Name, err)
+	}
+	sort.Slice(children.Items, func(i, j int) bool { return children.Items[i].GetName() < children.Items[j].GetName() })
+	return children.Items, nil
+}
+
+// describeRollout prints the details of the Rollout and its children
+func describeRollout(out io.Writer, r *rollout, children []unstructured.Unstructured) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", r.objectMeta.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", r.objectMeta.Namespace)
+	fmt.Fprintf(w, "Kind:\t%s\n", r.kind)
+	fmt.Fprintf(w, "Phase:\t%s\n", valueOrNone(string(r.status.Phase)))
+	if r.status.Message != "" {
+		fmt.Fprintf(w, "Message:\t%s\n", r.status.Message)
+	}
+	if r.status.WaitingFor != "" {
+		fmt.Fprintf(w, "Waiting For:\t%s\n", r.status.WaitingFor)
+	}
+	fmt.Fprintf(w, "Strategy:\t%s\n", valueOrNone(string(r.userStrategy)))
+	fmt.Fprintf(w, "Upgrade In Progress:\t%s\n", r.upgradeInProgressSummary())
+	if r.progressiveUpgradeInProgress() {
+		fmt.Fprintf(w, "Promoted Child:\t%s\n", valueOrNone(r.progressiveStatus.PromotedChildName))
+		fmt.Fprintf(w, "Upgrading Child:\t%s\n", valueOrNone(r.progressiveStatus.UpgradingChildName))
+		if analysis := r.progressiveStatus.Analysis; analysis != nil && analysis.ChildName == r.progressiveStatus.UpgradingChildName {
+			fmt.Fprintf(w, "Analysis:\t%s %s\n", valueOrNone(string(analysis.Phase)), analysis.Message)
+		}
+		if canary := r.progressiveStatus.Canary; canary != nil && canary.ChildName == r.progressiveStatus.UpgradingChildName {
+			fmt.Fprintf(w, "Canary:\tstep %d, weight %d%%\n", canary.CurrentStepIndex+1, canary.Weight)
+		}
+		if since := r.progressiveStatus.AwaitingPromotionSince; since != nil {
+			fmt.Fprintf(w, "Awaiting Promotion:\tfor %s\n", age(*since))
+		}
+	}
+	for _, annotation := range []string{common.AnnotationKeyPromote, common.AnnotationKeyAbort, common.AnnotationKeyRollbackTo} {
+		if value, found := r.objectMeta.Annotations[annotation]; found {
+			fmt.Fprintf(w, "Requested:\t%s=%s\n", annotation, value)
+		}
+	}
+	if r.pausable {
+		fmt.Fprintf(w, "Paused:\t%t (spec.paused: %t)\n", r.isPausingOrPaused(), r.paused)
+	}
+	if r.pausingProgress != "" {
+		fmt.Fprintf(w, "Pausing Pipelines:\t%s\n", r.pausingProgress)
+	}
+	if controllerRollout, ok := r.object.(*apiv1.NumaflowControllerRollout); ok {
+		describeNumaflowController(w, controllerRollout)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Conditions:")
+	if len(r.status.Conditions) == 0 {
+		fmt.Fprintln(out, "  <none>")
+	} else {
+		w = tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tAGE\tMESSAGE")
+		for _, condition := range r.status.Conditions {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", condition.Type, condition.Status, valueOrNone(condition.Reason),
+				age(condition.LastTransitionTime), condition.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if r.childResource != "" {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Children:")
+		if len(children) == 0 {
+			fmt.Fprintln(out, "  <none>")
+		} else {
+			w = tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "  NAME\tUPGRADE STATE\tPHASE\tAGE")
+			for _, child := range children {
+				phase, _, _ := unstructured.NestedString(child.Object, "status", "phase")
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", child.GetName(), valueOrNone(child.GetLabels()[common.LabelKeyUpgradeState]),
+					valueOrNone(phase), age(child.GetCreationTimestamp()))
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.pausable {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Pause Requests:")
+		if len(r.pauseRequests) == 0 {
+			fmt.Fprintln(out, "  <none>")
+		} else {
+			w = tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "  REQUESTER\tSINCE")
+			for _, pauseRequest := range r.pauseRequests {
+				fmt.Fprintf(w, "  %s\t%s\n", pauseRequest.Requester, age(pauseRequest.Since))
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// describeNumaflowController prints the details specific to a NumaflowControllerRollout
+func describeNumaflowController(w io.Writer, controllerRollout *apiv1.NumaflowControllerRollout) {
+	fmt.Fprintf(w, "Version:\t%s\n", controllerRollout.Spec.Controller.Version)
+	fmt.Fprintf(w, "Instance ID:\t%s\n", valueOrNone(controllerRollout.Spec.Controller.InstanceID))
+	fmt.Fprintf(w, "Cluster Scoped:\t%t\n", controllerRollout.Spec.ClusterScoped)
+	if len(controllerRollout.Status.ManagedNamespaces) > 0 {
+		fmt.Fprintf(w, "Managed Namespaces:\t%s\n", strings.Join(controllerRollout.Status.ManagedNamespaces, ", "))
+	}
+	progressiveStatus := controllerRollout.Status.ProgressiveStatus
+	if progressiveStatus.UpgradingInstanceID == "" {
+		return
+	}
+	fmt.Fprintf(w, "Promoted Controller:\tversion %s, instance ID %q\n", progressiveStatus.PromotedVersion, controllerRollout.GetPromotedInstanceID())
+	fmt.Fprintf(w, "Upgrading Controller:\tversion %s, instance ID %q\n", controllerRollout.Spec.Controller.Version, progressiveStatus.UpgradingInstanceID)
+	migratedChildren := make([]string, len(progressiveStatus.MigratedChildren))
+	for i, child := range progressiveStatus.MigratedChildren {
+		migratedChildren[i] = fmt.Sprintf("%s/%s", child.Kind, child.Name)
+		if !child.Healthy {
+			migratedChildren[i] += " (not yet healthy)"
+		}
+	}
+	fmt.Fprintf(w, "Migrated Children:\t%s\n", valueOrNone(strings.Join(migratedChildren, ", ")))
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_describeRollout(t *testing.T) {
+	pipelineRollout := makePipelineRollout(defaultPipelineRolloutName, map[string]interface{}{})
+	pipelineRollout.Spec.Paused = true
+	pipelineRollout.Annotations = map[string]string{common.AnnotationKeyPromote: "true"}
+	pipelineRollout.Status.Phase = apiv1.PhasePending
+	pipelineRollout.Status.WaitingFor = "upgrading child my-pipeline-1 to be assessed as healthy"
+	pipelineRollout.Status.UpgradeInProgress = apiv1.UpgradeStrategyProgressive
+	pipelineRollout.Status.ProgressiveStatus = apiv1.ProgressiveStatus{
+		State:              apiv1.ProgressiveStateAssessing,
+		PromotedChildName:  "my-pipeline-0",
+		UpgradingChildName: "my-pipeline-1",
+	}
+	pipelineRollout.Status.Conditions = []metav1.Condition{{
+		Type:               string(apiv1.ConditionPipelinePausingOrPaused),
+		Status:             metav1.ConditionTrue,
+		Reason:             "Paused",
+		Message:            "Pipeline paused",
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Minute)),
+	}}
+	pipelineRollout.Status.PauseRequests = []apiv1.PauseRequest{
+		{Requester: userPauseRequester, Since: metav1.NewTime(time.Now().Add(-time.Minute))},
+		{Requester: "ISBServiceRollout/my-isbsvc", Since: metav1.NewTime(time.Now().Add(-2 * time.Minute))},
+	}
+
+	c := newFakeClients([]runtime.Object{pipelineRollout}, nil, []runtime.Object{
+		makeChildPipeline("my-pipeline-0", defaultPipelineRolloutName, common.LabelValueUpgradePromoted, map[string]interface{}{}),
+		makeChildPipeline("my-pipeline-1", defaultPipelineRolloutName, common.LabelValueUpgradeInProgress, map[string]interface{}{}),
+		makeChildPipeline("other-pipeline-0", "other-pipeline", common.LabelValueUpgradePromoted, map[string]interface{}{}),
+	})
+
+	output, err := runCommand(t, c, "describe", "pipelinerollout", defaultPipelineRolloutName)
+	assert.NoError(t, err)
+	assert.Regexp(t, `Phase:\s+Pending\n`, output)
+	assert.Regexp(t, `Waiting For:\s+upgrading child my-pipeline-1 to be assessed as healthy\n`, output)
+	assert.Regexp(t, `Upgrade In Progress:\s+Progressive \(Assessing\)\n`, output)
+	assert.Regexp(t, `Promoted Child:\s+my-pipeline-0\n`, output)
+	assert.Regexp(t, `Upgrading Child:\s+my-pipeline-1\n`, output)
+	assert.Regexp(t, `Requested:\s+numaplane.numaproj.io/promote=true\n`, output)
+	assert.Regexp(t, `Paused:\s+true \(spec.paused: true\)\n`, output)
+	assert.Regexp(t, `PipelinePausingOrPaused\s+True\s+Paused\s+60s\s+Pipeline paused\n`, output)
+	assert.Regexp(t, `my-pipeline-0\s+promoted\s+Running\s+`, output)
+	assert.Regexp(t, `my-pipeline-1\s+in-progress\s+Running\s+`, output)
+	assert.NotContains(t, output, "other-pipeline-0")
+	assert.NotContains(t, output, "Awaiting Promotion:")
+	assert.Regexp(t, `user\s+60s\n\s+ISBServiceRollout/my-isbsvc\s+2m\n`, output)
+
+	_, err = runCommand(t, c, "describe", "pipelinerollout", "missing")
+	assert.ErrorContains(t, err, "failed to get PipelineRollout default/missing")
+}
+
+func Test_describeRollout_AwaitingPromotion(t *testing.T) {
+	pipelineRollout := makePipelineRollout(defaultPipelineRolloutName, map[string]interface{}{})
+	pipelineRollout.Status.Phase = apiv1.PhasePending
+	pipelineRollout.Status.UpgradeInProgress = apiv1.UpgradeStrategyProgressive
+	awaitingPromotionSince := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+	pipelineRollout.Status.ProgressiveStatus = apiv1.ProgressiveStatus{
+		State:                  apiv1.ProgressiveStateAwaitingPromotion,
+		PromotedChildName:      "my-pipeline-0",
+		UpgradingChildName:     "my-pipeline-1",
+		AwaitingPromotionSince: &awaitingPromotionSince,
+	}
+	c := newFakeClients([]runtime.Object{pipelineRollout}, nil, nil)
+
+	output, err := runCommand(t, c, "describe", "pipelinerollout", defaultPipelineRolloutName)
+	assert.NoError(t, err)
+	assert.Regexp(t, `Upgrade In Progress:\s+Progressive \(AwaitingPromotion\)\n`, output)
+	assert.Regexp(t, `Awaiting Promotion:\s+for 5m\d*s?\n`, output)
+}
+
+func Test_describeNumaflowControllerRollout(t *testing.T) {
+	controllerRollout := &apiv1.NumaflowControllerRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "numaflow-controller"},
+		Spec:       apiv1.NumaflowControllerRolloutSpec{Controller: apiv1.Controller{Version: "1.4.0"}},
+		Status: apiv1.NumaflowControllerRolloutStatus{
+			Status:             apiv1.Status{Phase: apiv1.PhasePending},
+			PauseRequestStatus: apiv1.PauseStatus{Progress: "1/2 pipelines paused"},
+			ProgressiveStatus: apiv1.ControllerProgressiveStatus{
+				PromotedVersion:     "1.3.3",
+				UpgradingInstanceID: "1",
+				MigratedChildren:    []apiv1.MigratedChild{{Kind: "PipelineRollout", Name: "my-pipeline", Healthy: true}, {Kind: "PipelineRollout", Name: "other-pipeline"}},
+			},
+		},
+	}
+	c := newFakeClients([]runtime.Object{controllerRollout}, nil, nil)
+
+	output, err := runCommand(t, c, "describe", "numaflowcontroller", "numaflow-controller")
+	assert.NoError(t, err)
+	assert.Regexp(t, `Upgrade In Progress:\s+Progressive\n`, output)
+	assert.Regexp(t, `Pausing Pipelines:\s+1/2 pipelines paused\n`, output)
+	assert.Regexp(t, `Promoted Controller:\s+version 1.3.3, instance ID ""\n`, output)
+	assert.Regexp(t, `Upgrading Controller:\s+version 1.4.0, instance ID "1"\n`, output)
+	assert.Regexp(t, `Migrated Children:\s+PipelineRollout/my-pipeline, PipelineRollout/other-pipeline \(not yet healthy\)\n`, output)
+	assert.NotContains(t, output, "Children:\n")
+	assert.NotContains(t, output, "Pause Requests:")
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/numaproj/numaplane/internal/common"
+)
+
+func (cli *cli) newDiffCommand() *cobra.Command {
+	var upgrading bool
+
+	command := &cobra.Command{
+		Use:   "diff KIND NAME",
+		Short: "Show a unified diff from the spec of a Rollout's live child to the child spec defined by the Rollout",
+		Long: `Show a unified diff from the spec of a Rollout's live child to the child spec defined by the Rollout.
+The live child is the promoted one, unless --upgrading is given. Its spec also has anything which Numaplane or Numaflow sets
+on the child, such as defaults or the name of the promoted InterStepBufferService, so those show up in the diff too.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, err := parseRolloutKind(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := cli.getClients()
+			if err != nil {
+				return err
+			}
+			r, err := getRollout(cmd.Context(), c, kind, args[1])
+			if err != nil {
+				return err
+			}
+			if r.childSpec == nil {
+				return fmt.Errorf("diff isn't supported for %ss, whose children aren't defined by a single spec", r.kind)
+			}
+
+			children, err := listChildren(cmd.Context(), c, r)
+			if err != nil {
+				return err
+			}
+			var liveChild *unstructured.Unstructured
+			for i, child := range children {
+				if upgrading {
+					if r.progressiveUpgradeInProgress() && child.GetName() == r.progressiveStatus.UpgradingChildName {
+						liveChild = &children[i]
+					}
+				} else if child.GetLabels()[common.LabelKeyUpgradeState] == string(common.LabelValueUpgradePromoted) {
+					liveChild = &children[i]
+				}
+			}
+			if liveChild == nil {
+				if upgrading {
+					return fmt.Errorf("%s %s/%s has no upgrading child", r.kind, c.namespace, r.objectMeta.Name)
+				}
+				return fmt.Errorf("%s %s/%s has no promoted child", r.kind, c.namespace, r.objectMeta.Name)
+			}
+
+			diff, err := specDiff(liveChild, r.childSpec.Raw)
+			if err != nil {
+				return err
+			}
+			if diff == "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "The spec of %s matches the spec defined by %s %s/%s\n", liveChild.GetName(), r.kind, c.namespace,
+					r.objectMeta.Name)
+				return nil
+			}
+			fmt.Fprint(cmd.OutOrStdout(), diff)
+			return nil
+		},
+	}
+	command.Flags().BoolVar(&upgrading, "upgrading", false, "compare with the upgrading child of a Progressive upgrade in progress")
+	return command
+}
+
+// specDiff returns a unified diff from the spec of the live child to the desired spec
+func specDiff(liveChild *unstructured.Unstructured, desiredSpec []byte) (string, error) {
+	liveSpec, err := indentedJSON(liveChild.Object["spec"])
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal spec of %s: %w", liveChild.GetName(), err)
+	}
+	var desiredSpecMap map[string]interface{}
+	if err := json.Unmarshal(desiredSpec, &desiredSpecMap); err != nil {
+		return "", fmt.Errorf("failed to unmarshal desired spec: %w", err)
+	}
+	desired, err := indentedJSON(desiredSpecMap)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(liveSpec),
+		B:        difflib.SplitLines(desired),
+		FromFile: "live/" + liveChild.GetName(),
+		ToFile:   "desired",
+		Context:  3,
+	})
+}
+
+// indentedJSON marshals the value with its map keys in sorted order, so that the output is stable
+func indentedJSON(value interface{}) (string, error) {
+	indented, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(indented) + "\n", nil
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_diff(t *testing.T) {
+	desiredSpec := map[string]interface{}{
+		"interStepBufferServiceName": "my-isbsvc",
+		"vertices":                   []interface{}{map[string]interface{}{"name": "in"}, map[string]interface{}{"name": "out"}},
+	}
+	promotedSpec := map[string]interface{}{
+		"interStepBufferServiceName": "my-isbsvc",
+		"vertices":                   []interface{}{map[string]interface{}{"name": "in"}, map[string]interface{}{"name": "cat"}, map[string]interface{}{"name": "out"}},
+	}
+
+	pipelineRollout := makePipelineRollout(defaultPipelineRolloutName, desiredSpec)
+	pipelineRollout.Status.ProgressiveStatus = apiv1.ProgressiveStatus{State: apiv1.ProgressiveStateAssessing, UpgradingChildName: "my-pipeline-1"}
+	c := newFakeClients([]runtime.Object{pipelineRollout, makePipelineRollout("new-pipeline", desiredSpec)}, nil, []runtime.Object{
+		makeChildPipeline("my-pipeline-0", defaultPipelineRolloutName, common.LabelValueUpgradePromoted, promotedSpec),
+		makeChildPipeline("my-pipeline-1", defaultPipelineRolloutName, common.LabelValueUpgradeInProgress, desiredSpec),
+	})
+
+	output, err := runCommand(t, c, "diff", "pipelinerollout", defaultPipelineRolloutName)
+	assert.NoError(t, err)
+	assert.Equal(t, `--- live/my-pipeline-0
++++ desired
+@@ -5,9 +5,6 @@
+       "name": "in"
+     },
+     {
+-      "name": "cat"
+-    },
+-    {
+       "name": "out"
+     }
+   ]
+`, output)
+
+	output, err = runCommand(t, c, "diff", "pipelinerollout", defaultPipelineRolloutName, "--upgrading")
+	assert.NoError(t, err)
+	assert.Equal(t, "The spec of my-pipeline-1 matches the spec defined by PipelineRollout default/my-pipeline\n", output)
+
+	_, err = runCommand(t, c, "diff", "pipelinerollout", "new-pipeline")
+	assert.ErrorContains(t, err, "PipelineRollout default/new-pipeline has no promoted child")
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+func (cli *cli) newGetCommand() *cobra.Command {
+	var allNamespaces bool
+
+	command := &cobra.Command{
+		Use:   "get (rollouts | KIND)",
+		Short: "List Rollouts with their phase, upgrade strategy and whether they're paused",
+		Long: `List Rollouts with their phase, upgrade strategy and whether they're paused.
+"rollouts" lists Rollouts of every kind; otherwise only Rollouts of the given kind are listed, e.g. "pipelinerollouts".`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kinds := rolloutKinds
+			if args[0] != "rollouts" && args[0] != "rollout" {
+				kind, err := parseRolloutKind(args[0])
+				if err != nil {
+					return err
+				}
+				kinds = []rolloutKind{kind}
+			}
+
+			c, err := cli.getClients()
+			if err != nil {
+				return err
+			}
+			namespace := c.namespace
+			if allNamespaces {
+				namespace = metav1.NamespaceAll
+			}
+			rollouts, err := listRollouts(cmd.Context(), c, kinds, namespace)
+			if err != nil {
+				return err
+			}
+			if len(rollouts) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No Rollouts found")
+				return nil
+			}
+			return printRollouts(cmd.OutOrStdout(), rollouts, allNamespaces)
+		},
+	}
+	command.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "list Rollouts in all namespaces")
+	return command
+}
+
+// printRollouts prints a table of the Rollouts
+func printRollouts(out io.Writer, rollouts []*rollout, withNamespace bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if withNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "KIND\tNAME\tPHASE\tSTRATEGY\tUPGRADE IN PROGRESS\tPAUSED\tAGE")
+	for _, r := range rollouts {
+		if withNamespace {
+			fmt.Fprintf(w, "%s\t", r.objectMeta.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.kind, r.objectMeta.Name, valueOrNone(string(r.status.Phase)),
+			valueOrNone(string(r.userStrategy)), r.upgradeInProgressSummary(), r.pausedSummary(), age(r.objectMeta.CreationTimestamp))
+	}
+	return w.Flush()
+}
+
+// upgradeInProgressSummary describes the upgrade in progress, if any, including the step of a Progressive upgrade
+func (r *rollout) upgradeInProgressSummary() string {
+	if r.progressiveUpgradeInProgress() {
+		return fmt.Sprintf("%s (%s)", r.upgradeInProgress, r.progressiveStatus.State)
+	}
+	return valueOrNone(string(r.upgradeInProgress))
+}
+
+// pausedSummary describes whether the child is pausing or paused, for the kinds which can be paused
+func (r *rollout) pausedSummary() string {
+	if !r.pausable {
+		return "-"
+	}
+	return strconv.FormatBool(r.isPausingOrPaused())
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// age formats the time since the timestamp as kubectl does
+func age(timestamp metav1.Time) string {
+	if timestamp.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(timestamp.Time))
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_getRollouts(t *testing.T) {
+	pausedPipelineRollout := makePipelineRollout("paused-pipeline", map[string]interface{}{})
+	pausedPipelineRollout.Spec.Paused = true
+	pausedPipelineRollout.Status.Conditions = []metav1.Condition{{Type: string(apiv1.ConditionPipelinePausingOrPaused), Status: metav1.ConditionTrue}}
+
+	upgradingPipelineRollout := makePipelineRollout("upgrading-pipeline", map[string]interface{}{})
+	upgradingPipelineRollout.Status.Phase = apiv1.PhasePending
+	upgradingPipelineRollout.Status.UserStrategy = apiv1.UserUpgradeStrategyProgressive
+	upgradingPipelineRollout.Status.UpgradeInProgress = apiv1.UpgradeStrategyProgressive
+	upgradingPipelineRollout.Status.ProgressiveStatus.State = apiv1.ProgressiveStateAssessing
+
+	isbServiceRollout := &apiv1.ISBServiceRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "my-isbsvc"},
+		Status:     apiv1.ISBServiceRolloutStatus{Status: apiv1.Status{Phase: apiv1.PhaseDeployed}},
+	}
+	otherNamespaceRollout := makePipelineRollout("other-pipeline", map[string]interface{}{})
+	otherNamespaceRollout.Namespace = "other"
+
+	c := newFakeClients([]runtime.Object{pausedPipelineRollout, upgradingPipelineRollout, isbServiceRollout, otherNamespaceRollout}, nil, nil)
+
+	output, err := runCommand(t, c, "get", "rollouts")
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if assert.Len(t, lines, 4) {
+		assert.Equal(t, []string{"KIND", "NAME", "PHASE", "STRATEGY", "UPGRADE", "IN", "PROGRESS", "PAUSED", "AGE"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{"PipelineRollout", "paused-pipeline", "Deployed", "-", "-", "true", "60m"}, strings.Fields(lines[1]))
+		assert.Equal(t, []string{"PipelineRollout", "upgrading-pipeline", "Pending", "progressive", "Progressive", "(Assessing)", "false", "60m"},
+			strings.Fields(lines[2]))
+		assert.Equal(t, []string{"ISBServiceRollout", "my-isbsvc", "Deployed", "-", "-", "-", "<unknown>"}, strings.Fields(lines[3]))
+	}
+
+	// only the given kind, across all namespaces
+	output, err = runCommand(t, c, "get", "pipelinerollouts", "-A")
+	assert.NoError(t, err)
+	lines = strings.Split(strings.TrimSpace(output), "\n")
+	if assert.Len(t, lines, 4) {
+		assert.Equal(t, "NAMESPACE", strings.Fields(lines[0])[0])
+		assert.Equal(t, []string{"default", "PipelineRollout", "paused-pipeline"}, strings.Fields(lines[1])[:3])
+		assert.Equal(t, []string{"default", "PipelineRollout", "upgrading-pipeline"}, strings.Fields(lines[2])[:3])
+		assert.Equal(t, []string{"other", "PipelineRollout", "other-pipeline"}, strings.Fields(lines[3])[:3])
+	}
+
+	output, err = runCommand(t, c, "get", "monovertexrollouts")
+	assert.NoError(t, err)
+	assert.Equal(t, "No Rollouts found\n", output)
+
+	_, err = runCommand(t, c, "get", "pods")
+	assert.ErrorContains(t, err, `unknown kind of Rollout "pods"`)
+}
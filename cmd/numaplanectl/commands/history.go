@@ -0,0 +1,172 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func (cli *cli) newHistoryCommand() *cobra.Command {
+	var revisionNumber int64
+
+	command := &cobra.Command{
+		Use:   "history KIND NAME",
+		Short: "List the revisions in a Rollout's revision history, or show the child definition of one of them",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, err := parseRolloutKind(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := cli.getClients()
+			if err != nil {
+				return err
+			}
+			revisions, err := loadRevisionHistory(cmd.Context(), c, kind, args[1])
+			if err != nil {
+				return err
+			}
+
+			if revisionNumber == 0 {
+				if len(revisions) == 0 {
+					fmt.Fprintf(cmd.OutOrStdout(), "No revisions found for %s %s/%s\n", kind, c.namespace, args[1])
+					return nil
+				}
+				return printRevisions(cmd.OutOrStdout(), revisions)
+			}
+			revision := findRevision(revisions, revisionNumber)
+			if revision == nil {
+				return fmt.Errorf("revision %d not found in the revision history of %s %s/%s", revisionNumber, kind, c.namespace, args[1])
+			}
+			return printRevisionDefinition(cmd.OutOrStdout(), revision)
+		},
+	}
+	command.Flags().Int64Var(&revisionNumber, "revision", 0, "show the child definition of this revision")
+	return command
+}
+
+func (cli *cli) newRollbackCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rollback KIND NAME REVISION",
+		Short: "Roll a Rollout back to a revision in its revision history",
+		Long: `Roll a Rollout back to a revision in its revision history.
+The request is made with the "` + common.AnnotationKeyRollbackTo + `" annotation: the controller writes the revision's child
+definition back into the Rollout's spec, from which it's applied like any other change, and removes the annotation.`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, err := parseRolloutKind(args[0])
+			if err != nil {
+				return err
+			}
+			revisionNumber, err := strconv.ParseInt(args[2], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid revision %q: %w", args[2], err)
+			}
+			c, err := cli.getClients()
+			if err != nil {
+				return err
+			}
+			r, err := getRollout(cmd.Context(), c, kind, args[1])
+			if err != nil {
+				return err
+			}
+			revisions, err := loadRevisionHistory(cmd.Context(), c, kind, args[1])
+			if err != nil {
+				return err
+			}
+			if findRevision(revisions, revisionNumber) == nil {
+				return fmt.Errorf("revision %d not found in the revision history of %s %s/%s", revisionNumber, kind, c.namespace, args[1])
+			}
+
+			if err := annotateRollout(cmd.Context(), c, r, common.AnnotationKeyRollbackTo, strconv.FormatInt(revisionNumber, 10)); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Requested rollback of %s %s/%s to revision %d\n", kind, c.namespace, r.objectMeta.Name, revisionNumber)
+			return nil
+		},
+	}
+}
+
+// loadRevisionHistory loads the revisions of the Rollout from the ConfigMap in which the controller keeps them, in order of revision
+func loadRevisionHistory(ctx context.Context, c *clients, kind rolloutKind, name string) ([]apiv1.RolloutRevision, error) {
+	configMapName := common.RevisionHistoryConfigMapName(string(kind), name)
+	configMap, err := c.kubernetes.CoreV1().ConfigMaps(c.namespace).Get(ctx, configMapName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get ConfigMap %s/%s: %w", c.namespace, configMapName, err)
+	}
+
+	revisions := make([]apiv1.RolloutRevision, 0, len(configMap.Data))
+	for key, data := range configMap.Data {
+		var revision apiv1.RolloutRevision
+		if err := json.Unmarshal([]byte(data), &revision); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal revision %s from ConfigMap %s/%s: %w", key, c.namespace, configMapName, err)
+		}
+		revisions = append(revisions, revision)
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+	return revisions, nil
+}
+
+func findRevision(revisions []apiv1.RolloutRevision, revisionNumber int64) *apiv1.RolloutRevision {
+	for i := range revisions {
+		if revisions[i].Revision == revisionNumber {
+			return &revisions[i]
+		}
+	}
+	return nil
+}
+
+// printRevisions prints a table of the revisions
+func printRevisions(out io.Writer, revisions []apiv1.RolloutRevision) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tGENERATION\tAPPLIED\tSTRATEGY\tOUTCOME\tMESSAGE")
+	for _, revision := range revisions {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\n", revision.Revision, revision.Generation, age(revision.AppliedTime),
+			valueOrNone(string(revision.Strategy)), revision.Outcome, revision.Message)
+	}
+	return w.Flush()
+}
+
+// printRevisionDefinition prints the child definition kept in the revision
+func printRevisionDefinition(out io.Writer, revision *apiv1.RolloutRevision) error {
+	var definition interface{}
+	if err := json.Unmarshal(revision.Definition.Raw, &definition); err != nil {
+		return fmt.Errorf("failed to unmarshal definition of revision %d: %w", revision.Revision, err)
+	}
+	indented, err := json.MarshalIndent(definition, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, string(indented))
+	return nil
+}
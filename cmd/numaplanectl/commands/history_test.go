@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_historyAndRollback(t *testing.T) {
+	ctx := context.Background()
+
+	definition := func(isbServiceName string) runtime.RawExtension {
+		raw, _ := json.Marshal(apiv1.Pipeline{Spec: runtime.RawExtension{Raw: []byte(`{"interStepBufferServiceName":"` + isbServiceName + `"}`)}})
+		return runtime.RawExtension{Raw: raw}
+	}
+	appliedTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	configMap := makeRevisionHistoryConfigMap(pipelineRolloutKind, defaultPipelineRolloutName,
+		apiv1.RolloutRevision{Revision: 2, Generation: 3, AppliedTime: appliedTime, Definition: definition("isbsvc-b"),
+			Strategy: apiv1.UpgradeStrategyProgressive, Outcome: apiv1.RevisionOutcomeFailed, Message: "analysis failed"},
+		apiv1.RolloutRevision{Revision: 1, Generation: 1, AppliedTime: appliedTime, Definition: definition("isbsvc-a"), Outcome: apiv1.RevisionOutcomeSucceeded},
+	)
+	c := newFakeClients([]runtime.Object{
+		makePipelineRollout(defaultPipelineRolloutName, map[string]interface{}{}),
+		makePipelineRollout("new-pipeline", map[string]interface{}{}),
+	}, []runtime.Object{configMap}, nil)
+
+	output, err := runCommand(t, c, "history", "pipelinerollout", defaultPipelineRolloutName)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, []string{"REVISION", "GENERATION", "APPLIED", "STRATEGY", "OUTCOME", "MESSAGE"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{"1", "1", "60m", "-", "Succeeded"}, strings.Fields(lines[1]))
+		assert.Equal(t, []string{"2", "3", "60m", "Progressive", "Failed", "analysis", "failed"}, strings.Fields(lines[2]))
+	}
+
+	output, err = runCommand(t, c, "history", "pipelinerollout", defaultPipelineRolloutName, "--revision", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"metadata\": {},\n  \"spec\": {\n    \"interStepBufferServiceName\": \"isbsvc-a\"\n  }\n}\n", output)
+
+	output, err = runCommand(t, c, "history", "pipelinerollout", "new-pipeline")
+	assert.NoError(t, err)
+	assert.Equal(t, "No revisions found for PipelineRollout default/new-pipeline\n", output)
+
+	output, err = runCommand(t, c, "rollback", "pipelinerollout", defaultPipelineRolloutName, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Requested rollback of PipelineRollout default/my-pipeline to revision 1\n", output)
+	pipelineRollout, err := c.numaplane.NumaplaneV1alpha1().PipelineRollouts(defaultNamespace).Get(ctx, defaultPipelineRolloutName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", pipelineRollout.Annotations[common.AnnotationKeyRollbackTo])
+
+	_, err = runCommand(t, c, "rollback", "pipelinerollout", defaultPipelineRolloutName, "3")
+	assert.ErrorContains(t, err, "revision 3 not found in the revision history of PipelineRollout default/my-pipeline")
+	_, err = runCommand(t, c, "rollback", "pipelinerollout", defaultPipelineRolloutName, "latest")
+	assert.ErrorContains(t, err, `invalid revision "latest"`)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// userPauseRequester is the requester of a pause requested by the Rollout's own spec.paused (see apiv1.PauseRequest)
+const userPauseRequester = "user"
+
+// newPauseCommand returns the "pause" command, or the "resume" command if pause is false
+func (cli *cli) newPauseCommand(pause bool) *cobra.Command {
+	use, short := "pause", "Pause the child of a PipelineRollout or MonoVertexRollout by setting its spec.paused"
+	long := short
+	if !pause {
+		use, short = "resume", "Resume the child of a PipelineRollout or MonoVertexRollout by clearing its spec.paused"
+		long = short + `.
+The child only resumes once nothing else, such as an ISBServiceRollout or NumaflowControllerRollout update, requires it
+to be paused.`
+	}
+
+	return &cobra.Command{
+		Use:   use + " KIND NAME",
+		Short: short,
+		Long:  long,
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, err := parseRolloutKind(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := cli.getClients()
+			if err != nil {
+				return err
+			}
+			r, err := getRollout(cmd.Context(), c, kind, args[1])
+			if err != nil {
+				return err
+			}
+			if !r.pausable {
+				return fmt.Errorf("%ss can't be paused: only PipelineRollouts and MonoVertexRollouts have spec.paused", r.kind)
+			}
+
+			if err := patchRollout(cmd.Context(), c, r, map[string]interface{}{"spec": map[string]interface{}{"paused": pause}}); err != nil {
+				return err
+			}
+			action := "resumed"
+			if pause {
+				action = "paused"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s/%s %s\n", r.kind, c.namespace, r.objectMeta.Name, action)
+
+			// the child only resumes once nothing else requests it to be paused
+			if !pause {
+				otherRequesters := []string{}
+				for _, pauseRequest := range r.pauseRequests {
+					if pauseRequest.Requester != userPauseRequester {
+						otherRequesters = append(otherRequesters, pauseRequest.Requester)
+					}
+				}
+				if len(otherRequesters) > 0 {
+					fmt.Fprintf(cmd.OutOrStdout(), "Its child remains paused as requested by %s\n", strings.Join(otherRequesters, ", "))
+				}
+			}
+			return nil
+		},
+	}
+}
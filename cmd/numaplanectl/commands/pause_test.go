@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_pauseAndResume(t *testing.T) {
+	ctx := context.Background()
+	pipelineRollout := makePipelineRollout(defaultPipelineRolloutName, map[string]interface{}{})
+	isbServiceRollout := &apiv1.ISBServiceRollout{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "my-isbsvc"}}
+	c := newFakeClients([]runtime.Object{pipelineRollout, isbServiceRollout}, nil, nil)
+
+	output, err := runCommand(t, c, "pause", "pipelinerollout", defaultPipelineRolloutName)
+	assert.NoError(t, err)
+	assert.Equal(t, "PipelineRollout default/my-pipeline paused\n", output)
+	pipelineRollout, err = c.numaplane.NumaplaneV1alpha1().PipelineRollouts(defaultNamespace).Get(ctx, defaultPipelineRolloutName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, pipelineRollout.Spec.Paused)
+
+	// the controller would record the pause requests
+	pipelineRollout.Status.PauseRequests = []apiv1.PauseRequest{{Requester: userPauseRequester}, {Requester: "NumaflowControllerRollout"}}
+	_, err = c.numaplane.NumaplaneV1alpha1().PipelineRollouts(defaultNamespace).UpdateStatus(ctx, pipelineRollout, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	output, err = runCommand(t, c, "resume", "pipelinerollout", defaultPipelineRolloutName)
+	assert.NoError(t, err)
+	assert.Equal(t, "PipelineRollout default/my-pipeline resumed\nIts child remains paused as requested by NumaflowControllerRollout\n", output)
+	pipelineRollout, err = c.numaplane.NumaplaneV1alpha1().PipelineRollouts(defaultNamespace).Get(ctx, defaultPipelineRolloutName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.False(t, pipelineRollout.Spec.Paused)
+
+	_, err = runCommand(t, c, "pause", "isbservicerollout", "my-isbsvc")
+	assert.ErrorContains(t, err, "ISBServiceRollouts can't be paused")
+}
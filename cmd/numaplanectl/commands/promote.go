@@ -0,0 +1,81 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// newProgressiveCommand returns the "promote" command, or the "abort" command if promote is false
+func (cli *cli) newProgressiveCommand(promote bool) *cobra.Command {
+	use, annotation := "promote", common.AnnotationKeyPromote
+	short := "Promote the upgrading child of a Progressive upgrade which is awaiting manual promotion"
+	if !promote {
+		use, annotation = "abort", common.AnnotationKeyAbort
+		short = "Abort a Progressive upgrade, removing the upgrading child and leaving the promoted child in place"
+	}
+
+	return &cobra.Command{
+		Use:   use + " KIND NAME",
+		Short: short,
+		Long: short + `.
+The request is made with the "` + annotation + `" annotation, which the controller removes once the upgrade is done.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, err := parseRolloutKind(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := cli.getClients()
+			if err != nil {
+				return err
+			}
+			r, err := getRollout(cmd.Context(), c, kind, args[1])
+			if err != nil {
+				return err
+			}
+			if r.kind == numaflowControllerRolloutKind {
+				controllerRollout := r.object.(*apiv1.NumaflowControllerRollout)
+				if !promote && controllerRollout.Status.ProgressiveStatus.UpgradingInstanceID != "" {
+					return fmt.Errorf("a NumaflowControllerRollout's Progressive upgrade is aborted by setting spec.controller.version back to %s",
+						controllerRollout.Status.ProgressiveStatus.PromotedVersion)
+				}
+				return fmt.Errorf("%s isn't supported for NumaflowControllerRollouts", use)
+			}
+			if !r.progressiveUpgradeInProgress() {
+				return fmt.Errorf("no Progressive upgrade is in progress for %s %s/%s", r.kind, c.namespace, r.objectMeta.Name)
+			}
+			// (promoting doesn't skip any canary steps or Analysis: it only releases a child which has passed them)
+			if promote && r.progressiveStatus.State != apiv1.ProgressiveStateAwaitingPromotion {
+				return fmt.Errorf("upgrading child %s of %s %s/%s isn't awaiting promotion (its state is %s): only an upgrade using manualPromotion can be promoted, once its upgrading child has passed assessment",
+					r.progressiveStatus.UpgradingChildName, r.kind, c.namespace, r.objectMeta.Name, r.progressiveStatus.State)
+			}
+
+			if err := annotateRollout(cmd.Context(), c, r, annotation, "true"); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Requested to %s upgrading child %s of %s %s/%s\n", use, r.progressiveStatus.UpgradingChildName, r.kind,
+				c.namespace, r.objectMeta.Name)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_promoteAndAbort(t *testing.T) {
+	ctx := context.Background()
+
+	upgradingPipelineRollout := makePipelineRollout(defaultPipelineRolloutName, map[string]interface{}{})
+	upgradingPipelineRollout.Status.ProgressiveStatus = apiv1.ProgressiveStatus{State: apiv1.ProgressiveStateAwaitingPromotion, UpgradingChildName: "my-pipeline-1"}
+	assessingPipelineRollout := makePipelineRollout("other-pipeline", map[string]interface{}{})
+	assessingPipelineRollout.Status.ProgressiveStatus = apiv1.ProgressiveStatus{State: apiv1.ProgressiveStateAssessing, UpgradingChildName: "other-pipeline-1"}
+	doneMonoVertexRollout := &apiv1.MonoVertexRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "my-monovertex"},
+		Status:     apiv1.MonoVertexRolloutStatus{ProgressiveStatus: apiv1.ProgressiveStatus{State: apiv1.ProgressiveStateDone}},
+	}
+	controllerRollout := &apiv1.NumaflowControllerRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "numaflow-controller"},
+		Spec:       apiv1.NumaflowControllerRolloutSpec{Controller: apiv1.Controller{Version: "1.4.0"}},
+		Status: apiv1.NumaflowControllerRolloutStatus{
+			ProgressiveStatus: apiv1.ControllerProgressiveStatus{PromotedVersion: "1.3.3", UpgradingInstanceID: "1"},
+		},
+	}
+	c := newFakeClients([]runtime.Object{upgradingPipelineRollout, assessingPipelineRollout, doneMonoVertexRollout, controllerRollout}, nil, nil)
+
+	tests := []struct {
+		command            string
+		expectedAnnotation string
+	}{
+		{command: "promote", expectedAnnotation: common.AnnotationKeyPromote},
+		{command: "abort", expectedAnnotation: common.AnnotationKeyAbort},
+	}
+	for _, tc := range tests {
+		t.Run(tc.command, func(t *testing.T) {
+			output, err := runCommand(t, c, tc.command, "pipelinerollout", defaultPipelineRolloutName)
+			assert.NoError(t, err)
+			assert.Equal(t, "Requested to "+tc.command+" upgrading child my-pipeline-1 of PipelineRollout default/my-pipeline\n", output)
+			pipelineRollout, err := c.numaplane.NumaplaneV1alpha1().PipelineRollouts(defaultNamespace).Get(ctx, defaultPipelineRolloutName, metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, "true", pipelineRollout.Annotations[tc.expectedAnnotation])
+
+			_, err = runCommand(t, c, tc.command, "monovertexrollout", "my-monovertex")
+			assert.ErrorContains(t, err, "no Progressive upgrade is in progress for MonoVertexRollout default/my-monovertex")
+		})
+	}
+
+	// an upgrading child which is still being assessed can only be aborted
+	_, err := runCommand(t, c, "promote", "pipelinerollout", "other-pipeline")
+	assert.ErrorContains(t, err, "upgrading child other-pipeline-1 of PipelineRollout default/other-pipeline isn't awaiting promotion (its state is Assessing)")
+	_, err = runCommand(t, c, "abort", "pipelinerollout", "other-pipeline")
+	assert.NoError(t, err)
+
+	_, err = runCommand(t, c, "abort", "numaflowcontrollerrollout", "numaflow-controller")
+	assert.ErrorContains(t, err, "aborted by setting spec.controller.version back to 1.3.3")
+	_, err = runCommand(t, c, "promote", "numaflowcontrollerrollout", "numaflow-controller")
+	assert.ErrorContains(t, err, "promote isn't supported for NumaflowControllerRollouts")
+}
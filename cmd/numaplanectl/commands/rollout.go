@@ -0,0 +1,306 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// rolloutKind is a kind of Rollout which the commands can operate on, e.g. "PipelineRollout"
+type rolloutKind string
+
+const (
+	pipelineRolloutKind           rolloutKind = "PipelineRollout"
+	monoVertexRolloutKind         rolloutKind = "MonoVertexRollout"
+	isbServiceRolloutKind         rolloutKind = "ISBServiceRollout"
+	numaflowControllerRolloutKind rolloutKind = "NumaflowControllerRollout"
+)
+
+var (
+	rolloutKinds = []rolloutKind{pipelineRolloutKind, monoVertexRolloutKind, isbServiceRolloutKind, numaflowControllerRolloutKind}
+
+	// the names which a kind may be given on the command line, besides its lowercase kind and plural
+	rolloutKindAliases = map[rolloutKind][]string{
+		pipelineRolloutKind:           {"pipeline", "pipelines"},
+		monoVertexRolloutKind:         {"monovertex", "monovertices"},
+		isbServiceRolloutKind:         {"isbsvc", "isbservice", "isbservices"},
+		numaflowControllerRolloutKind: {"numaflowcontroller", "controller"},
+	}
+)
+
+// parseRolloutKind finds the kind of Rollout given on the command line, ignoring case
+func parseRolloutKind(name string) (rolloutKind, error) {
+	name = strings.ToLower(name)
+	kindNames := make([]string, len(rolloutKinds))
+	for i, kind := range rolloutKinds {
+		kindNames[i] = strings.ToLower(string(kind))
+		if name == kindNames[i] || name == kindNames[i]+"s" || slices.Contains(rolloutKindAliases[kind], name) {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("unknown kind of Rollout %q: expected one of %s", name, strings.Join(kindNames, ", "))
+}
+
+// rollout holds what the commands need from a Rollout of any kind
+type rollout struct {
+	kind       rolloutKind
+	objectMeta metav1.ObjectMeta
+	status     apiv1.Status
+
+	// userStrategy is the strategy in effect for updates which risk data loss
+	userStrategy apiv1.UserUpgradeStrategy
+	// upgradeInProgress is the upgrade strategy currently being used, if any
+	upgradeInProgress apiv1.UpgradeStrategy
+	// progressiveStatus is the state of the Progressive upgrade, for the kinds using the shared Progressive state machine
+	progressiveStatus *apiv1.ProgressiveStatus
+
+	// pausable indicates that the Rollout has spec.paused
+	pausable bool
+	// paused is the Rollout's spec.paused
+	paused bool
+	// pausingOrPausedCondition is the Condition indicating that the child is pausing or paused, for the kinds which have one
+	pausingOrPausedCondition apiv1.ConditionType
+	// pauseRequests are the active requests to pause the child
+	pauseRequests []apiv1.PauseRequest
+	// pausingProgress is the progress of pausing Pipelines for an update of the child, for the kinds which pause Pipelines
+	pausingProgress string
+
+	// childResource is the plural resource name of the Numaflow child, for the kinds which have one
+	childResource string
+	// childSpec is the spec of the child as defined by the Rollout, for the kinds which have a Numaflow child
+	childSpec *runtime.RawExtension
+
+	// object is the Rollout itself, as returned by the clientset
+	object runtime.Object
+}
+
+func newPipelineRollout(pipelineRollout *apiv1.PipelineRollout) *rollout {
+	return &rollout{
+		kind:                     pipelineRolloutKind,
+		objectMeta:               pipelineRollout.ObjectMeta,
+		status:                   pipelineRollout.Status.Status,
+		userStrategy:             pipelineRollout.Status.UserStrategy,
+		upgradeInProgress:        pipelineRollout.Status.UpgradeInProgress,
+		progressiveStatus:        &pipelineRollout.Status.ProgressiveStatus,
+		pausable:                 true,
+		paused:                   pipelineRollout.Spec.Paused,
+		pausingOrPausedCondition: apiv1.ConditionPipelinePausingOrPaused,
+		pauseRequests:            pipelineRollout.Status.PauseRequests,
+		childResource:            pipelineRollout.GetChildPluralName(),
+		childSpec:                &pipelineRollout.Spec.Pipeline.Spec,
+		object:                   pipelineRollout,
+	}
+}
+
+func newMonoVertexRollout(monoVertexRollout *apiv1.MonoVertexRollout) *rollout {
+	return &rollout{
+		kind:                     monoVertexRolloutKind,
+		objectMeta:               monoVertexRollout.ObjectMeta,
+		status:                   monoVertexRollout.Status.Status,
+		userStrategy:             monoVertexRollout.Status.UserStrategy,
+		upgradeInProgress:        monoVertexRollout.Status.UpgradeInProgress,
+		progressiveStatus:        &monoVertexRollout.Status.ProgressiveStatus,
+		pausable:                 true,
+		paused:                   monoVertexRollout.Spec.Paused,
+		pausingOrPausedCondition: apiv1.ConditionMonoVertexPausingOrPaused,
+		pauseRequests:            monoVertexRollout.Status.PauseRequests,
+		childResource:            monoVertexRollout.GetChildPluralName(),
+		childSpec:                &monoVertexRollout.Spec.MonoVertex.Spec,
+		object:                   monoVertexRollout,
+	}
+}
+
+func newISBServiceRollout(isbServiceRollout *apiv1.ISBServiceRollout) *rollout {
+	return &rollout{
+		kind:              isbServiceRolloutKind,
+		objectMeta:        isbServiceRollout.ObjectMeta,
+		status:            isbServiceRollout.Status.Status,
+		userStrategy:      isbServiceRollout.Status.UserStrategy,
+		upgradeInProgress: isbServiceRollout.Status.UpgradeInProgress,
+		progressiveStatus: &isbServiceRollout.Status.ProgressiveStatus,
+		pausingProgress:   isbServiceRollout.Status.PauseRequestStatus.Progress,
+		childResource:     isbServiceRollout.GetChildPluralName(),
+		childSpec:         &isbServiceRollout.Spec.InterStepBufferService.Spec,
+		object:            isbServiceRollout,
+	}
+}
+
+func newNumaflowControllerRollout(controllerRollout *apiv1.NumaflowControllerRollout) *rollout {
+	// (a NumaflowControllerRollout has its own Progressive upgrade, which moves children between Numaflow Controllers)
+	upgradeInProgress := apiv1.UpgradeStrategyNoOp
+	if controllerRollout.Status.ProgressiveStatus.UpgradingInstanceID != "" {
+		upgradeInProgress = apiv1.UpgradeStrategyProgressive
+	} else if condition := controllerRollout.Status.GetCondition(apiv1.ConditionPausingPipelines); condition != nil && condition.Status == metav1.ConditionTrue {
+		upgradeInProgress = apiv1.UpgradeStrategyPPND
+	}
+	return &rollout{
+		kind:              numaflowControllerRolloutKind,
+		objectMeta:        controllerRollout.ObjectMeta,
+		status:            controllerRollout.Status.Status,
+		upgradeInProgress: upgradeInProgress,
+		pausingProgress:   controllerRollout.Status.PauseRequestStatus.Progress,
+		object:            controllerRollout,
+	}
+}
+
+// getRollout gets the Rollout of the given kind and name from the clients' namespace
+func getRollout(ctx context.Context, c *clients, kind rolloutKind, name string) (*rollout, error) {
+	var result *rollout
+	var err error
+	switch kind {
+	case pipelineRolloutKind:
+		var pipelineRollout *apiv1.PipelineRollout
+		if pipelineRollout, err = c.numaplane.NumaplaneV1alpha1().PipelineRollouts(c.namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+			result = newPipelineRollout(pipelineRollout)
+		}
+	case monoVertexRolloutKind:
+		var monoVertexRollout *apiv1.MonoVertexRollout
+		if monoVertexRollout, err = c.numaplane.NumaplaneV1alpha1().MonoVertexRollouts(c.namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+			result = newMonoVertexRollout(monoVertexRollout)
+		}
+	case isbServiceRolloutKind:
+		var isbServiceRollout *apiv1.ISBServiceRollout
+		if isbServiceRollout, err = c.numaplane.NumaplaneV1alpha1().ISBServiceRollouts(c.namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+			result = newISBServiceRollout(isbServiceRollout)
+		}
+	case numaflowControllerRolloutKind:
+		var controllerRollout *apiv1.NumaflowControllerRollout
+		if controllerRollout, err = c.numaplane.NumaplaneV1alpha1().NumaflowControllerRollouts(c.namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+			result = newNumaflowControllerRollout(controllerRollout)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected kind of Rollout %q", kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", kind, c.namespace, name, err)
+	}
+	return result, nil
+}
+
+// listRollouts lists the Rollouts of the given kinds in the namespace ("" for all namespaces), sorted by kind, namespace and name
+func listRollouts(ctx context.Context, c *clients, kinds []rolloutKind, namespace string) ([]*rollout, error) {
+	rollouts := []*rollout{}
+	for _, kind := range kinds {
+		var err error
+		switch kind {
+		case pipelineRolloutKind:
+			var list *apiv1.PipelineRolloutList
+			if list, err = c.numaplane.NumaplaneV1alpha1().PipelineRollouts(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+				for i := range list.Items {
+					rollouts = append(rollouts, newPipelineRollout(&list.Items[i]))
+				}
+			}
+		case monoVertexRolloutKind:
+			var list *apiv1.MonoVertexRolloutList
+			if list, err = c.numaplane.NumaplaneV1alpha1().MonoVertexRollouts(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+				for i := range list.Items {
+					rollouts = append(rollouts, newMonoVertexRollout(&list.Items[i]))
+				}
+			}
+		case isbServiceRolloutKind:
+			var list *apiv1.ISBServiceRolloutList
+			if list, err = c.numaplane.NumaplaneV1alpha1().ISBServiceRollouts(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+				for i := range list.Items {
+					rollouts = append(rollouts, newISBServiceRollout(&list.Items[i]))
+				}
+			}
+		case numaflowControllerRolloutKind:
+			var list *apiv1.NumaflowControllerRolloutList
+			if list, err = c.numaplane.NumaplaneV1alpha1().NumaflowControllerRollouts(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+				for i := range list.Items {
+					rollouts = append(rollouts, newNumaflowControllerRollout(&list.Items[i]))
+				}
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %ss: %w", kind, err)
+		}
+	}
+
+	sort.SliceStable(rollouts, func(i, j int) bool {
+		a, b := rollouts[i], rollouts[j]
+		if a.kind != b.kind {
+			return slices.Index(rolloutKinds, a.kind) < slices.Index(rolloutKinds, b.kind)
+		}
+		if a.objectMeta.Namespace != b.objectMeta.Namespace {
+			return a.objectMeta.Namespace < b.objectMeta.Namespace
+		}
+		return a.objectMeta.Name < b.objectMeta.Name
+	})
+	return rollouts, nil
+}
+
+// patchRollout applies a JSON merge patch to the Rollout
+func patchRollout(ctx context.Context, c *clients, r *rollout, patch map[string]interface{}) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	name := r.objectMeta.Name
+	switch r.kind {
+	case pipelineRolloutKind:
+		_, err = c.numaplane.NumaplaneV1alpha1().PipelineRollouts(c.namespace).Patch(ctx, name, k8stypes.MergePatchType, data, metav1.PatchOptions{})
+	case monoVertexRolloutKind:
+		_, err = c.numaplane.NumaplaneV1alpha1().MonoVertexRollouts(c.namespace).Patch(ctx, name, k8stypes.MergePatchType, data, metav1.PatchOptions{})
+	case isbServiceRolloutKind:
+		_, err = c.numaplane.NumaplaneV1alpha1().ISBServiceRollouts(c.namespace).Patch(ctx, name, k8stypes.MergePatchType, data, metav1.PatchOptions{})
+	case numaflowControllerRolloutKind:
+		_, err = c.numaplane.NumaplaneV1alpha1().NumaflowControllerRollouts(c.namespace).Patch(ctx, name, k8stypes.MergePatchType, data, metav1.PatchOptions{})
+	default:
+		return fmt.Errorf("unexpected kind of Rollout %q", r.kind)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to patch %s %s/%s: %w", r.kind, c.namespace, name, err)
+	}
+	return nil
+}
+
+// annotateRollout sets an annotation on the Rollout
+func annotateRollout(ctx context.Context, c *clients, r *rollout, key string, value string) error {
+	return patchRollout(ctx, c, r, map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]string{key: value}},
+	})
+}
+
+// isPausingOrPaused indicates whether the Rollout's child is pausing or paused, for the kinds which can be paused
+func (r *rollout) isPausingOrPaused() bool {
+	condition := r.status.GetCondition(r.pausingOrPausedCondition)
+	return condition != nil && condition.Status == metav1.ConditionTrue
+}
+
+// progressiveUpgradeInProgress indicates whether a Progressive upgrade driven by the shared state machine is in progress
+func (r *rollout) progressiveUpgradeInProgress() bool {
+	return r.progressiveStatus != nil && r.progressiveStatus.State != "" && r.progressiveStatus.State != apiv1.ProgressiveStateDone
+}
+
+// childGroupVersionResource is the resource of the Rollout's Numaflow children
+func (r *rollout) childGroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: common.NumaflowAPIGroup, Version: common.NumaflowAPIVersion, Resource: r.childResource}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package commands implements numaplanectl, a CLI for operating Numaplane Rollouts.
+// Every command works through the Rollouts themselves, as the controller would see them: pausing sets spec.paused, and
+// promoting, aborting and rolling back set the annotations which the controller acts on.
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/numaproj/numaplane/pkg/client/clientset/versioned"
+)
+
+// clients are what the commands use to reach Kubernetes
+type clients struct {
+	// numaplane reads and updates Rollouts
+	numaplane versioned.Interface
+	// kubernetes reads the ConfigMaps holding revision histories
+	kubernetes kubernetes.Interface
+	// dynamic reads the Numaflow children of Rollouts
+	dynamic dynamic.Interface
+	// namespace is the namespace of the Rollouts
+	namespace string
+}
+
+// globalFlags are the flags which apply to every command
+type globalFlags struct {
+	kubeconfig string
+	context    string
+	namespace  string
+}
+
+// clientsFactory creates the clients given the global flags (tests replace it to use fake clientsets)
+type clientsFactory func(flags *globalFlags) (*clients, error)
+
+type cli struct {
+	flags      globalFlags
+	newClients clientsFactory
+	clients    *clients
+}
+
+// NewCommand returns the numaplanectl root command
+func NewCommand() *cobra.Command {
+	return newCommand(loadClients)
+}
+
+func newCommand(newClients clientsFactory) *cobra.Command {
+	cli := &cli{newClients: newClients}
+
+	command := &cobra.Command{
+		Use:          "numaplanectl",
+		Short:        "numaplanectl operates Numaplane Rollouts",
+		SilenceUsage: true,
+	}
+	command.PersistentFlags().StringVar(&cli.flags.kubeconfig, "kubeconfig", "", "path to the kubeconfig file (defaults to $KUBECONFIG or ~/.kube/config)")
+	command.PersistentFlags().StringVar(&cli.flags.context, "context", "", "kubeconfig context to use")
+	command.PersistentFlags().StringVarP(&cli.flags.namespace, "namespace", "n", "", "namespace of the Rollouts (defaults to the kubeconfig context's namespace)")
+
+	command.AddCommand(
+		cli.newGetCommand(),
+		cli.newDescribeCommand(),
+		cli.newPauseCommand(true),
+		cli.newPauseCommand(false),
+		cli.newProgressiveCommand(true),
+		cli.newProgressiveCommand(false),
+		cli.newHistoryCommand(),
+		cli.newRollbackCommand(),
+		cli.newDiffCommand(),
+	)
+	return command
+}
+
+// getClients creates the clients the first time they're needed, so that commands like "help" don't need a kubeconfig
+func (cli *cli) getClients() (*clients, error) {
+	if cli.clients == nil {
+		c, err := cli.newClients(&cli.flags)
+		if err != nil {
+			return nil, err
+		}
+		cli.clients = c
+	}
+	return cli.clients, nil
+}
+
+// loadClients creates the clients from the kubeconfig, as kubectl would
+func loadClients(flags *globalFlags) (*clients, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = flags.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: flags.context})
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	namespace := flags.namespace
+	if namespace == "" {
+		if namespace, _, err = clientConfig.Namespace(); err != nil {
+			return nil, fmt.Errorf("failed to get namespace from kubeconfig: %w", err)
+		}
+	}
+
+	numaplaneClient, err := versioned.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Numaplane client: %w", err)
+	}
+	kubernetesClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	return &clients{numaplane: numaplaneClient, kubernetes: kubernetesClient, dynamic: dynamicClient, namespace: namespace}, nil
+}
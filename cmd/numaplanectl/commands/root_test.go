@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	numaplanefake "github.com/numaproj/numaplane/pkg/client/clientset/versioned/fake"
+)
+
+const (
+	defaultNamespace           = "default"
+	defaultPipelineRolloutName = "my-pipeline"
+)
+
+// newFakeClients creates clients backed by fake clientsets holding the given objects: Rollouts, ConfigMaps and Numaflow children
+func newFakeClients(rollouts []runtime.Object, configMaps []runtime.Object, children []runtime.Object) *clients {
+	childListKinds := map[schema.GroupVersionResource]string{
+		{Group: common.NumaflowAPIGroup, Version: common.NumaflowAPIVersion, Resource: "pipelines"}:               "PipelineList",
+		{Group: common.NumaflowAPIGroup, Version: common.NumaflowAPIVersion, Resource: "monovertices"}:            "MonoVertexList",
+		{Group: common.NumaflowAPIGroup, Version: common.NumaflowAPIVersion, Resource: "interstepbufferservices"}: "InterStepBufferServiceList",
+	}
+	return &clients{
+		numaplane:  numaplanefake.NewSimpleClientset(rollouts...),
+		kubernetes: k8sfake.NewSimpleClientset(configMaps...),
+		dynamic:    dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), childListKinds, children...),
+		namespace:  defaultNamespace,
+	}
+}
+
+// runCommand runs numaplanectl with the arguments against the clients, returning its output
+func runCommand(t *testing.T, c *clients, args ...string) (string, error) {
+	t.Helper()
+	command := newCommand(func(flags *globalFlags) (*clients, error) {
+		if flags.namespace != "" {
+			c.namespace = flags.namespace
+		}
+		return c, nil
+	})
+	out := &bytes.Buffer{}
+	command.SetOut(out)
+	command.SetErr(out)
+	command.SetArgs(args)
+	err := command.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+func makePipelineRollout(name string, pipelineSpec map[string]interface{}) *apiv1.PipelineRollout {
+	pipelineSpecRaw, _ := json.Marshal(pipelineSpec)
+	return &apiv1.PipelineRollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         defaultNamespace,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+		Spec: apiv1.PipelineRolloutSpec{
+			Pipeline: apiv1.Pipeline{Spec: runtime.RawExtension{Raw: pipelineSpecRaw}},
+		},
+		Status: apiv1.PipelineRolloutStatus{
+			Status: apiv1.Status{Phase: apiv1.PhaseDeployed},
+		},
+	}
+}
+
+func makeChildPipeline(name string, rolloutName string, upgradeState common.UpgradeState, pipelineSpec map[string]interface{}) *unstructured.Unstructured {
+	pipeline := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   pipelineSpec,
+		"status": map[string]interface{}{"phase": "Running"},
+	}}
+	pipeline.SetAPIVersion(common.NumaflowGroupVersion)
+	pipeline.SetKind(common.NumaflowPipelineKind)
+	pipeline.SetNamespace(defaultNamespace)
+	pipeline.SetName(name)
+	pipeline.SetLabels(map[string]string{
+		common.LabelKeyParentRollout: rolloutName,
+		common.LabelKeyUpgradeState:  string(upgradeState),
+	})
+	return pipeline
+}
+
+func makeRevisionHistoryConfigMap(rolloutKind rolloutKind, rolloutName string, revisions ...apiv1.RolloutRevision) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: defaultNamespace,
+			Name:      common.RevisionHistoryConfigMapName(string(rolloutKind), rolloutName),
+		},
+		Data: map[string]string{},
+	}
+	for _, revision := range revisions {
+		data, _ := json.Marshal(revision)
+		configMap.Data[fmt.Sprint(revision.Revision)] = string(data)
+	}
+	return configMap
+}
+
+func Test_parseRolloutKind(t *testing.T) {
+	for name, expectedKind := range map[string]rolloutKind{
+		"PipelineRollout":            pipelineRolloutKind,
+		"pipelinerollouts":           pipelineRolloutKind,
+		"pipeline":                   pipelineRolloutKind,
+		"monovertices":               monoVertexRolloutKind,
+		"isbsvc":                     isbServiceRolloutKind,
+		"NumaflowControllerRollouts": numaflowControllerRolloutKind,
+	} {
+		kind, err := parseRolloutKind(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, expectedKind, kind, name)
+	}
+
+	_, err := parseRolloutKind("deployment")
+	assert.ErrorContains(t, err, `unknown kind of Rollout "deployment"`)
+}
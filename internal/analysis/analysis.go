@@ -0,0 +1,103 @@
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"text/template"
+	"time"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// TemplateArgs are the values which may be referenced from the Query of an AnalysisMetric
+type TemplateArgs struct {
+	// Namespace of the child being analyzed
+	Namespace string
+	// Name of the child being analyzed
+	Name string
+	// Window of the Analysis, formatted as a Prometheus duration
+	Window string
+}
+
+func NewTemplateArgs(namespace, name string, window time.Duration) TemplateArgs {
+	return TemplateArgs{
+		Namespace: namespace,
+		Name:      name,
+		Window:    fmt.Sprintf("%ds", int64(window.Seconds())),
+	}
+}
+
+// Evaluate runs the query of each metric and compares the result with its threshold.
+// It returns the result of each metric and whether they all passed.
+// A metric which is misconfigured fails; an error is only returned if the provider could not be queried, in which case
+// the evaluation should be retried.
+func Evaluate(ctx context.Context, provider MetricsProvider, metrics []apiv1.AnalysisMetric, args TemplateArgs) ([]apiv1.AnalysisMetricResult, bool, error) {
+	results := make([]apiv1.AnalysisMetricResult, 0, len(metrics))
+	allPassed := true
+	for _, metric := range metrics {
+		result := apiv1.AnalysisMetricResult{Name: metric.Name}
+
+		query, threshold, err := parseMetric(metric, args)
+		if err != nil {
+			result.Message = err.Error()
+			results = append(results, result)
+			allPassed = false
+			continue
+		}
+
+		value, err := provider.Query(ctx, query)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to evaluate metric %q: %v", metric.Name, err)
+		}
+		result.Value = strconv.FormatFloat(value, 'f', -1, 64)
+
+		result.Passed, err = compare(value, metric.Operator, threshold)
+		if err != nil {
+			result.Message = err.Error()
+		}
+		if !result.Passed {
+			allPassed = false
+		}
+		results = append(results, result)
+	}
+	return results, allPassed, nil
+}
+
+// parseMetric returns the query with the template arguments substituted, and the threshold
+func parseMetric(metric apiv1.AnalysisMetric, args TemplateArgs) (string, float64, error) {
+	tmpl, err := template.New(metric.Name).Option("missingkey=error").Parse(metric.Query)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid query: %v", err)
+	}
+	var query bytes.Buffer
+	if err = tmpl.Execute(&query, args); err != nil {
+		return "", 0, fmt.Errorf("invalid query: %v", err)
+	}
+
+	threshold, err := strconv.ParseFloat(metric.Threshold, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid threshold %q: %v", metric.Threshold, err)
+	}
+	return query.String(), threshold, nil
+}
+
+func compare(value float64, operator apiv1.AnalysisOperator, threshold float64) (bool, error) {
+	switch operator {
+	case apiv1.AnalysisOperatorLessThan:
+		return value < threshold, nil
+	case apiv1.AnalysisOperatorLessThanOrEqual:
+		return value <= threshold, nil
+	case apiv1.AnalysisOperatorGreaterThan:
+		return value > threshold, nil
+	case apiv1.AnalysisOperatorGreaterThanOrEqual:
+		return value >= threshold, nil
+	case apiv1.AnalysisOperatorEqual:
+		return value == threshold, nil
+	case apiv1.AnalysisOperatorNotEqual:
+		return value != threshold, nil
+	default:
+		return false, fmt.Errorf("invalid operator %q", operator)
+	}
+}
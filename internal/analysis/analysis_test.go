@@ -0,0 +1,139 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func TestEvaluate(t *testing.T) {
+	args := NewTemplateArgs("my-ns", "my-pipeline-1", 5*time.Minute)
+
+	errorRateMetric := apiv1.AnalysisMetric{
+		Name:      "error-rate",
+		Query:     `sum(rate(errors_total{namespace="{{.Namespace}}",pipeline="{{.Name}}"}[{{.Window}}]))`,
+		Operator:  apiv1.AnalysisOperatorLessThan,
+		Threshold: "0.05",
+	}
+	errorRateQuery := `sum(rate(errors_total{namespace="my-ns",pipeline="my-pipeline-1"}[300s]))`
+
+	backlogMetric := apiv1.AnalysisMetric{
+		Name:      "backlog",
+		Query:     `sum(pending{pipeline="{{.Name}}"})`,
+		Operator:  apiv1.AnalysisOperatorLessThanOrEqual,
+		Threshold: "100",
+	}
+	backlogQuery := `sum(pending{pipeline="my-pipeline-1"})`
+
+	testCases := []struct {
+		name            string
+		metrics         []apiv1.AnalysisMetric
+		provider        *FakeMetricsProvider
+		expectedResults []apiv1.AnalysisMetricResult
+		expectedPassed  bool
+		expectedError   bool
+	}{
+		{
+			name:            "no metrics",
+			metrics:         []apiv1.AnalysisMetric{},
+			provider:        &FakeMetricsProvider{},
+			expectedResults: []apiv1.AnalysisMetricResult{},
+			expectedPassed:  true,
+		},
+		{
+			name:     "all metrics pass",
+			metrics:  []apiv1.AnalysisMetric{errorRateMetric, backlogMetric},
+			provider: &FakeMetricsProvider{Values: map[string]float64{errorRateQuery: 0.01, backlogQuery: 100}},
+			expectedResults: []apiv1.AnalysisMetricResult{
+				{Name: "error-rate", Value: "0.01", Passed: true},
+				{Name: "backlog", Value: "100", Passed: true},
+			},
+			expectedPassed: true,
+		},
+		{
+			name:     "one metric fails",
+			metrics:  []apiv1.AnalysisMetric{errorRateMetric, backlogMetric},
+			provider: &FakeMetricsProvider{Values: map[string]float64{errorRateQuery: 0.2, backlogQuery: 3}},
+			expectedResults: []apiv1.AnalysisMetricResult{
+				{Name: "error-rate", Value: "0.2", Passed: false},
+				{Name: "backlog", Value: "3", Passed: true},
+			},
+			expectedPassed: false,
+		},
+		{
+			name: "invalid threshold fails the metric",
+			metrics: []apiv1.AnalysisMetric{{
+				Name:      "bad-threshold",
+				Query:     backlogQuery,
+				Operator:  apiv1.AnalysisOperatorGreaterThan,
+				Threshold: "abc",
+			}},
+			provider: &FakeMetricsProvider{Values: map[string]float64{backlogQuery: 3}},
+			expectedResults: []apiv1.AnalysisMetricResult{
+				{Name: "bad-threshold", Passed: false, Message: `invalid threshold "abc": strconv.ParseFloat: parsing "abc": invalid syntax`},
+			},
+			expectedPassed: false,
+		},
+		{
+			name: "invalid operator fails the metric",
+			metrics: []apiv1.AnalysisMetric{{
+				Name:      "bad-operator",
+				Query:     backlogQuery,
+				Operator:  "=>",
+				Threshold: "1",
+			}},
+			provider: &FakeMetricsProvider{Values: map[string]float64{backlogQuery: 3}},
+			expectedResults: []apiv1.AnalysisMetricResult{
+				{Name: "bad-operator", Value: "3", Passed: false, Message: `invalid operator "=>"`},
+			},
+			expectedPassed: false,
+		},
+		{
+			name:          "provider error",
+			metrics:       []apiv1.AnalysisMetric{errorRateMetric},
+			provider:      &FakeMetricsProvider{Err: errors.New("connection refused")},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, passed, err := Evaluate(context.Background(), tc.provider, tc.metrics, args)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResults, results)
+			assert.Equal(t, tc.expectedPassed, passed)
+		})
+	}
+}
+
+func Test_compare(t *testing.T) {
+	testCases := []struct {
+		operator  apiv1.AnalysisOperator
+		value     float64
+		threshold float64
+		expected  bool
+	}{
+		{apiv1.AnalysisOperatorLessThan, 1, 2, true},
+		{apiv1.AnalysisOperatorLessThan, 2, 2, false},
+		{apiv1.AnalysisOperatorLessThanOrEqual, 2, 2, true},
+		{apiv1.AnalysisOperatorGreaterThan, 3, 2, true},
+		{apiv1.AnalysisOperatorGreaterThan, 2, 2, false},
+		{apiv1.AnalysisOperatorGreaterThanOrEqual, 2, 2, true},
+		{apiv1.AnalysisOperatorEqual, 2, 2, true},
+		{apiv1.AnalysisOperatorNotEqual, 2, 2, false},
+	}
+	for _, tc := range testCases {
+		result, err := compare(tc.value, tc.operator, tc.threshold)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, result, "%v %s %v", tc.value, tc.operator, tc.threshold)
+	}
+}
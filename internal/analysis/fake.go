@@ -0,0 +1,25 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+)
+
+// FakeMetricsProvider is a MetricsProvider for testing which returns preset values for known queries
+type FakeMetricsProvider struct {
+	// Values maps each query to the value returned for it
+	Values map[string]float64
+	// Err, if set, is returned for every query
+	Err error
+}
+
+func (p *FakeMetricsProvider) Query(ctx context.Context, query string) (float64, error) {
+	if p.Err != nil {
+		return 0, p.Err
+	}
+	value, found := p.Values[query]
+	if !found {
+		return 0, fmt.Errorf("no value for query %q", query)
+	}
+	return value, nil
+}
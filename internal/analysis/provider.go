@@ -0,0 +1,107 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetricsProvider evaluates a query to a single value
+type MetricsProvider interface {
+	Query(ctx context.Context, query string) (float64, error)
+}
+
+// PrometheusProvider is a MetricsProvider which queries the Prometheus HTTP API
+type PrometheusProvider struct {
+	address    string
+	httpClient *http.Client
+}
+
+func NewPrometheusProvider(address string) (*PrometheusProvider, error) {
+	if _, err := url.ParseRequestURI(address); err != nil {
+		return nil, fmt.Errorf("invalid Prometheus address %q: %v", address, err)
+	}
+	return &PrometheusProvider{
+		address:    strings.TrimSuffix(address, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// prometheusResponse is the subset of the Prometheus query API response that we need
+type prometheusResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type prometheusSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  []interface{}     `json:"value"`
+}
+
+// Query performs an instant query and returns its value: the query must result in a scalar or a vector with exactly one element
+func (p *PrometheusProvider) Query(ctx context.Context, query string) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.address+"/api/v1/query?"+url.Values{"query": {query}}.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error querying Prometheus: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("error reading Prometheus response: %v", err)
+	}
+	var promResponse prometheusResponse
+	if err = json.Unmarshal(body, &promResponse); err != nil {
+		return 0, fmt.Errorf("error parsing Prometheus response (status code %d): %v", resp.StatusCode, err)
+	}
+	if promResponse.Status != "success" {
+		return 0, fmt.Errorf("prometheus query failed: %s: %s", promResponse.ErrorType, promResponse.Error)
+	}
+
+	switch promResponse.Data.ResultType {
+	case "scalar":
+		var value []interface{}
+		if err = json.Unmarshal(promResponse.Data.Result, &value); err != nil {
+			return 0, fmt.Errorf("error parsing Prometheus scalar result: %v", err)
+		}
+		return parseSampleValue(value)
+	case "vector":
+		var samples []prometheusSample
+		if err = json.Unmarshal(promResponse.Data.Result, &samples); err != nil {
+			return 0, fmt.Errorf("error parsing Prometheus vector result: %v", err)
+		}
+		if len(samples) != 1 {
+			return 0, fmt.Errorf("prometheus query %q returned %d results, expected 1", query, len(samples))
+		}
+		return parseSampleValue(samples[0].Value)
+	default:
+		return 0, fmt.Errorf("unsupported Prometheus result type %q", promResponse.Data.ResultType)
+	}
+}
+
+// a sample value is returned as [<unix time>, "<value>"]
+func parseSampleValue(value []interface{}) (float64, error) {
+	if len(value) != 2 {
+		return 0, fmt.Errorf("unexpected Prometheus sample value %v", value)
+	}
+	valueStr, ok := value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected Prometheus sample value %v", value)
+	}
+	return strconv.ParseFloat(valueStr, 64)
+}
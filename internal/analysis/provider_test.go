@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusProvider_Query(t *testing.T) {
+	testCases := []struct {
+		name          string
+		statusCode    int
+		response      string
+		expectedValue float64
+		expectedError bool
+	}{
+		{
+			name:          "vector with one sample",
+			statusCode:    http.StatusOK,
+			response:      `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"pipeline":"p"},"value":[1700000000.123,"0.25"]}]}}`,
+			expectedValue: 0.25,
+		},
+		{
+			name:          "scalar",
+			statusCode:    http.StatusOK,
+			response:      `{"status":"success","data":{"resultType":"scalar","result":[1700000000.123,"42"]}}`,
+			expectedValue: 42,
+		},
+		{
+			name:          "empty vector",
+			statusCode:    http.StatusOK,
+			response:      `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			expectedError: true,
+		},
+		{
+			name:          "vector with multiple samples",
+			statusCode:    http.StatusOK,
+			response:      `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"1"]},{"metric":{},"value":[1,"2"]}]}}`,
+			expectedError: true,
+		},
+		{
+			name:          "query error",
+			statusCode:    http.StatusBadRequest,
+			response:      `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+			expectedError: true,
+		},
+		{
+			name:          "not a Prometheus response",
+			statusCode:    http.StatusBadGateway,
+			response:      `bad gateway`,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/v1/query", r.URL.Path)
+				assert.Equal(t, `sum(pending{pipeline="p"})`, r.URL.Query().Get("query"))
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer server.Close()
+
+			provider, err := NewPrometheusProvider(server.URL + "/")
+			assert.NoError(t, err)
+
+			value, err := provider.Query(context.Background(), `sum(pending{pipeline="p"})`)
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedValue, value)
+			}
+		})
+	}
+}
+
+func TestNewPrometheusProvider_InvalidAddress(t *testing.T) {
+	_, err := NewPrometheusProvider("not a url")
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -63,6 +64,10 @@ const (
 	// this includes both the case of pausing for Pipeline updating as well as for NumaflowController and isbsvc updating
 	LabelKeyAllowDataLoss = "numaplane.numaproj.io/allow-data-loss"
 
+	// LabelKeyPausePriority is the label key on a PipelineRollout giving the integer priority of its Pipeline when Pipelines are
+	// paused in priority order for an ISBService or Numaflow Controller update: lower priorities pause first and resume last
+	LabelKeyPausePriority = "numaplane.numaproj.io/pause-priority"
+
 	// LabelKeyUpgradeState is the label key used to identify the upgrade state of a resource that is managed by
 	// a NumaRollout.
 	LabelKeyUpgradeState = "numaplane.numaproj.io/upgrade-state"
@@ -79,6 +84,28 @@ const (
 	// after an upgrade.
 	LabelValueUpgradeRecyclable UpgradeState = "recyclable"
 
+	// AnnotationKeyDryRun is the annotation on a Rollout which, if "true", causes Numaplane to compute what it would do to
+	// reconcile the Rollout and report it in the Rollout's Status, rather than doing it
+	AnnotationKeyDryRun = "numaplane.numaproj.io/dry-run"
+
+	// AnnotationKeyRollbackTo is the annotation on a Rollout giving the number of a revision in its revision history to roll
+	// back to: the revision's child definition is written back into the Rollout's spec, and the annotation is removed
+	AnnotationKeyRollbackTo = "numaplane.numaproj.io/rollback-to"
+
+	// AnnotationKeyOverrideGuardrails is the annotation on a Rollout which, if "true", allows changes which the guardrails
+	// would otherwise block or require a particular upgrade strategy for, applying them like any other change
+	AnnotationKeyOverrideGuardrails = "numaplane.numaproj.io/override-guardrails"
+
+	// AnnotationKeyPromote is the annotation on a Rollout which, if "true" during a Progressive upgrade using manual promotion,
+	// promotes the upgrading child once it's awaiting promotion, i.e. once it has passed any canary steps and Analysis (which it
+	// doesn't skip); it's removed when the upgrade is done
+	AnnotationKeyPromote = "numaplane.numaproj.io/promote"
+
+	// AnnotationKeyAbort is the annotation on a Rollout which, if "true" during a Progressive upgrade, rolls the upgrade back:
+	// the upgrading child is drained and removed, leaving the promoted child in place, and the aborted child spec isn't retried
+	// until it's changed; it's removed when the upgrade is done
+	AnnotationKeyAbort = "numaplane.numaproj.io/abort"
+
 	// AnnotationKeyNumaflowInstanceID is the annotation passed to Numaflow Controller so it knows whether it should reconcile the resource
 	AnnotationKeyNumaflowInstanceID = "numaflow.numaproj.io/instance"
 )
@@ -95,3 +122,9 @@ var (
 	// default requeue time used by Reconcilers
 	DefaultDelayedRequeue = ctrl.Result{RequeueAfter: 20 * time.Second}
 )
+
+// RevisionHistoryConfigMapName returns the name of the ConfigMap holding the revision history of the Rollout of the given kind
+// and name
+func RevisionHistoryConfigMapName(rolloutKind string, rolloutName string) string {
+	return fmt.Sprintf("%s-%s-revisions", strings.ToLower(rolloutKind), rolloutName)
+}
@@ -34,7 +34,8 @@ type NumaflowControllerDefinitionsManager struct {
 }
 
 type NamespaceConfig struct {
-	UpgradeStrategy USDEUserStrategy `json:"upgradeStrategy,omitempty" yaml:"upgradeStrategy,omitempty"`
+	UpgradeStrategy    USDEUserStrategy          `json:"upgradeStrategy,omitempty" yaml:"upgradeStrategy,omitempty"`
+	MaintenanceWindows []apiv1.MaintenanceWindow `json:"maintenanceWindows,omitempty" yaml:"maintenanceWindows,omitempty"`
 }
 
 var instance *ConfigManager
@@ -71,6 +72,11 @@ type GlobalConfig struct {
 	IncludedResources string `json:"includedResources" mapstructure:"includedResources"`
 	// List of Numaflow Controller image names to look for
 	NumaflowControllerImageNames []string `json:"numaflowControllerImageNames" mapstructure:"numaflowControllerImageNames"`
+	// Number of applied child definitions kept in the revision history of each Rollout (defaults to 10 if not set)
+	RevisionHistoryLimit int `json:"revisionHistoryLimit" mapstructure:"revisionHistoryLimit"`
+	// Seconds a Rollout may be progressing towards deploying its child before it's considered stuck, unless the Rollout
+	// sets its own progressDeadlineSeconds (0 means no deadline)
+	ProgressDeadlineSeconds int32 `json:"progressDeadlineSeconds" mapstructure:"progressDeadlineSeconds"`
 }
 
 type NumaflowControllerDefinitionConfig struct {
@@ -27,6 +27,8 @@ func TestLoadConfigMatchValues(t *testing.T) {
 	assert.Equal(t, 3, config.LogLevel, "Log Level does not match")
 	assert.Contains(t, config.NumaflowControllerImageNames, "numaflow")
 	assert.Contains(t, config.NumaflowControllerImageNames, "numaflow-rc")
+	assert.Equal(t, 5, config.RevisionHistoryLimit)
+	assert.Equal(t, int32(300), config.ProgressDeadlineSeconds)
 	// now verify that if we modify the file, it will still be okay
 	originalFile := "../../../tests/config/testconfig.yaml"
 	fileToCopy := "../../../tests/config/testconfig2.yaml"
@@ -1,12 +1,69 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
 
 type USDEConfig struct {
 	// If user's config doesn't exist or doesn't specify strategy, this is the default
 	DefaultUpgradeStrategy      USDEUserStrategy `json:"defaultUpgradeStrategy" mapstructure:"defaultUpgradeStrategy"`
 	PipelineSpecExcludedPaths   []string         `json:"pipelineSpecExcludedPaths,omitempty" yaml:"pipelineSpecExcludedPaths,omitempty"`
 	ISBServiceSpecExcludedPaths []string         `json:"isbServiceSpecExcludedPaths,omitempty" yaml:"isbServiceSpecExcludedPaths,omitempty"`
+	MonoVertexSpecExcludedPaths []string         `json:"monoVertexSpecExcludedPaths,omitempty" yaml:"monoVertexSpecExcludedPaths,omitempty"`
+	// SpecExcludedPaths maps a kind, in the form "<Kind>.<group>" (e.g. "MonoVertex.numaflow.numaproj.io"), to additional
+	// apply paths for its spec, so that paths can be configured for any kind
+	SpecExcludedPaths map[string][]string `json:"specExcludedPaths,omitempty" yaml:"specExcludedPaths,omitempty"`
+	// Rules determine the strategy for changes to particular paths of the spec, ahead of the excluded paths
+	PipelineSpecRules   []USDERule `json:"pipelineSpecRules,omitempty" yaml:"pipelineSpecRules,omitempty"`
+	ISBServiceSpecRules []USDERule `json:"isbServiceSpecRules,omitempty" yaml:"isbServiceSpecRules,omitempty"`
+	MonoVertexSpecRules []USDERule `json:"monoVertexSpecRules,omitempty" yaml:"monoVertexSpecRules,omitempty"`
+	// Changes to these Labels or Annotations require the user's data loss strategy rather than a direct apply, as do
+	// changes to the Numaflow Controller instance annotation, whether or not it's listed.
+	// Each entry is either a full key or, if it ends with "*", a key prefix.
+	DataLossLabelKeys      []string `json:"dataLossLabelKeys,omitempty" yaml:"dataLossLabelKeys,omitempty"`
+	DataLossAnnotationKeys []string `json:"dataLossAnnotationKeys,omitempty" yaml:"dataLossAnnotationKeys,omitempty"`
+	// If the namespace and Rollout don't specify maintenance windows, these are the default
+	MaintenanceWindows []apiv1.MaintenanceWindow `json:"maintenanceWindows,omitempty" yaml:"maintenanceWindows,omitempty"`
+	// If the Pipelines haven't all paused this long after a pause-and-drain update of an ISBService or the Numaflow
+	// Controller began, PauseTimeoutPolicy is applied (defaulting to "notify"). Zero means no timeout.
+	PauseTimeout       time.Duration            `json:"pauseTimeout,omitempty" yaml:"pauseTimeout,omitempty"`
+	PauseTimeoutPolicy apiv1.PauseTimeoutPolicy `json:"pauseTimeoutPolicy,omitempty" yaml:"pauseTimeoutPolicy,omitempty"`
+	// By default, all Pipelines are paused at once for a pause-and-drain update of an ISBService or the Numaflow Controller.
+	// If PauseByPriority is set, they're paused in order of the pause priority label of their PipelineRollouts, a priority at a time.
+	// If PauseBatchSize is set, they're paused this many at a time (in priority order if PauseByPriority is set).
+	// Each batch is paused once the previous batch has paused, and they're resumed in reverse order.
+	PauseByPriority bool `json:"pauseByPriority,omitempty" yaml:"pauseByPriority,omitempty"`
+	PauseBatchSize  int  `json:"pauseBatchSize,omitempty" yaml:"pauseBatchSize,omitempty"`
+}
+
+// USDERuleStrategy is the strategy a USDERule requires
+type USDERuleStrategy string
+
+const (
+	ApplyRuleStrategy       USDERuleStrategy = "apply"
+	PPNDRuleStrategy        USDERuleStrategy = "ppnd"
+	ProgressiveRuleStrategy USDERuleStrategy = "progressive"
+	RecreateRuleStrategy    USDERuleStrategy = "recreate"
+)
+
+// USDERule requires a particular strategy if the value at Path changes.
+// Path is demarcated by "." and may include "*" to match any map key or array element, or an index to match one array element.
+// Rules are applied in order: a field matched by an earlier rule is not considered by later rules.
+type USDERule struct {
+	Path     string           `json:"path" yaml:"path"`
+	Strategy USDERuleStrategy `json:"strategy" yaml:"strategy"`
+}
+
+func (s USDERuleStrategy) IsValid() bool {
+	switch s {
+	case ApplyRuleStrategy, PPNDRuleStrategy, ProgressiveRuleStrategy, RecreateRuleStrategy:
+		return true
+	default:
+		return false
+	}
 }
 
 func (cm *ConfigManager) UpdateUSDEConfig(config USDEConfig) {
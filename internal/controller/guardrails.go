@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/usde"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// The guardrails (see usde.GuardrailViolation) are enforced here, by the reconcilers, before they apply a change to a child:
+// the validating webhook only rejects a violating change earlier, and it's optional and may be bypassed. The Rollout's
+// GuardrailsBlocked Condition records whether they block its update.
+
+// check the change from the existing Pipeline to the new one against the guardrails
+// return the upgrade strategy they require, if any, or an error if they block the change
+func checkPipelineGuardrails(ctx context.Context, c client.Client, pipelineRollout *apiv1.PipelineRollout, existingPipelineDef *kubernetes.GenericObject, newPipelineDef *kubernetes.GenericObject) (apiv1.UpgradeStrategy, error) {
+	if usde.GuardrailsOverridden(pipelineRollout) {
+		markGuardrailsBlocked(pipelineRollout.GetStatus(), pipelineRollout.Generation, nil)
+		return apiv1.UpgradeStrategyNoOp, nil
+	}
+	violations, err := usde.CheckPipelineSpecChange(existingPipelineDef.Spec.Raw, newPipelineDef.Spec.Raw)
+	if err != nil {
+		return apiv1.UpgradeStrategyError, err
+	}
+	violations, err = usde.ExemptRecordedRevision(ctx, c, pipelineRollout, apiv1.PipelineRolloutGroupVersionKind.Kind, pipelineRollout.Spec.Pipeline.Spec.Raw, violations)
+	if err != nil {
+		return apiv1.UpgradeStrategyError, err
+	}
+	logGuardrailViolations(ctx, violations)
+	strategy, err := usde.GetGuardrailStrategy(violations)
+	markGuardrailsBlocked(pipelineRollout.GetStatus(), pipelineRollout.Generation, err)
+	return strategy, err
+}
+
+// check the change from the existing ISBService to the new one against the guardrails
+// return the upgrade strategy they require, if any, or an error if they block the change
+func checkISBServiceGuardrails(ctx context.Context, c client.Client, isbServiceRollout *apiv1.ISBServiceRollout, existingISBServiceDef *kubernetes.GenericObject, newISBServiceDef *kubernetes.GenericObject) (apiv1.UpgradeStrategy, error) {
+	if usde.GuardrailsOverridden(isbServiceRollout) {
+		markGuardrailsBlocked(isbServiceRollout.GetStatus(), isbServiceRollout.Generation, nil)
+		return apiv1.UpgradeStrategyNoOp, nil
+	}
+	violations, err := usde.CheckISBServiceSpecChange(existingISBServiceDef.Spec.Raw, newISBServiceDef.Spec.Raw)
+	if err != nil {
+		return apiv1.UpgradeStrategyError, err
+	}
+	violations, err = usde.ExemptRecordedRevision(ctx, c, isbServiceRollout, apiv1.ISBServiceRolloutGroupVersionKind.Kind, isbServiceRollout.Spec.InterStepBufferService.Spec.Raw, violations)
+	if err != nil {
+		return apiv1.UpgradeStrategyError, err
+	}
+	logGuardrailViolations(ctx, violations)
+	strategy, err := usde.GetGuardrailStrategy(violations)
+	markGuardrailsBlocked(isbServiceRollout.GetStatus(), isbServiceRollout.Generation, err)
+	return strategy, err
+}
+
+// check a change to the NumaflowControllerRollout's InstanceID against the guardrails
+// The InstanceID is compared with the one of the latest revision which was deployed successfully. That's only needed while
+// the spec hasn't been deployed yet: once it has, its InstanceID is the one the promoted Numaflow Controller was deployed from.
+// return an error if the guardrails block the change
+func checkNumaflowControllerGuardrails(ctx context.Context, c client.Client, controllerRollout *apiv1.NumaflowControllerRollout) error {
+	if usde.GuardrailsOverridden(controllerRollout) {
+		markGuardrailsBlocked(controllerRollout.GetStatus(), controllerRollout.Generation, nil)
+		return nil
+	}
+	if condition := controllerRollout.Status.GetCondition(apiv1.ConditionChildResourceDeployed); condition != nil &&
+		condition.Status == metav1.ConditionTrue && condition.ObservedGeneration == controllerRollout.Generation {
+		markGuardrailsBlocked(controllerRollout.GetStatus(), controllerRollout.Generation, nil)
+		return nil
+	}
+
+	revisions, _, err := loadRevisionHistory(ctx, c, controllerRollout)
+	if err != nil {
+		return err
+	}
+	var deployedRevision *apiv1.RolloutRevision
+	for i := len(revisions) - 1; i >= 0 && deployedRevision == nil; i-- {
+		if revisions[i].Outcome == apiv1.RevisionOutcomeSucceeded {
+			deployedRevision = &revisions[i]
+		}
+	}
+	if deployedRevision == nil {
+		markGuardrailsBlocked(controllerRollout.GetStatus(), controllerRollout.Generation, nil)
+		return nil
+	}
+	var deployedController apiv1.Controller
+	if err := json.Unmarshal(deployedRevision.Definition.Raw, &deployedController); err != nil {
+		return fmt.Errorf("failed to unmarshal revision %d: %w", deployedRevision.Revision, err)
+	}
+
+	violations, err := usde.CheckNumaflowControllerInstanceIDChange(ctx, c, controllerRollout, deployedController.InstanceID)
+	if err != nil {
+		return err
+	}
+	logGuardrailViolations(ctx, violations)
+	_, err = usde.GetGuardrailStrategy(violations)
+	markGuardrailsBlocked(controllerRollout.GetStatus(), controllerRollout.Generation, err)
+	return err
+}
+
+// set the Rollout's GuardrailsBlocked Condition from the result of checking the guardrails, so that the reason its child isn't
+// updated is clear from the Rollout whether or not the change was rejected on admission
+func markGuardrailsBlocked(status *apiv1.Status, generation int64, guardrailErr error) {
+	if guardrailErr != nil {
+		status.MarkTrueWithReason(apiv1.ConditionGuardrailsBlocked, "GuardrailViolated", guardrailErr.Error(), generation)
+	} else if status.GetCondition(apiv1.ConditionGuardrailsBlocked) != nil {
+		status.MarkFalse(apiv1.ConditionGuardrailsBlocked, "NotBlocked", "No guardrail blocks the update", generation)
+	}
+}
+
+func logGuardrailViolations(ctx context.Context, violations []usde.GuardrailViolation) {
+	numaLogger := logger.FromContext(ctx)
+	for _, violation := range violations {
+		numaLogger.WithValues("path", violation.Path, "strategy", violation.Strategy).Infof("guardrail: %s", violation.Message)
+	}
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// the reconciler blocks a change which the guardrails don't allow, whether or not the webhook admitted it, and reports why in
+// the Rollout's GuardrailsBlocked Condition
+func Test_checkISBServiceGuardrails(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+	isbServiceDef := func(jetstreamVersion string) *kubernetes.GenericObject {
+		raw, err := json.Marshal(createDefaultISBServiceSpec(jetstreamVersion))
+		assert.NoError(t, err)
+		return &kubernetes.GenericObject{Spec: k8sruntime.RawExtension{Raw: raw}}
+	}
+	isbServiceRollout := createISBServiceRollout(createDefaultISBServiceSpec("2.9"))
+
+	// a JetStream downgrade is blocked
+	_, err := checkISBServiceGuardrails(ctx, c, isbServiceRollout, isbServiceDef("2.10.3"), isbServiceDef("2.9"))
+	assert.ErrorContains(t, err, "jetstream.version")
+	condition := isbServiceRollout.Status.GetCondition(apiv1.ConditionGuardrailsBlocked)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, "GuardrailViolated", condition.Reason)
+		assert.Equal(t, err.Error(), condition.Message)
+	}
+
+	// until the guardrails are overridden
+	isbServiceRollout.Annotations = map[string]string{common.AnnotationKeyOverrideGuardrails: "true"}
+	strategy, err := checkISBServiceGuardrails(ctx, c, isbServiceRollout, isbServiceDef("2.10.3"), isbServiceDef("2.9"))
+	assert.NoError(t, err)
+	assert.Equal(t, apiv1.UpgradeStrategyNoOp, strategy)
+	condition = isbServiceRollout.Status.GetCondition(apiv1.ConditionGuardrailsBlocked)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	}
+
+	// a change which is allowed leaves no Condition behind
+	isbServiceRollout = createISBServiceRollout(createDefaultISBServiceSpec("2.10.3"))
+	_, err = checkISBServiceGuardrails(ctx, c, isbServiceRollout, isbServiceDef("2.9.6"), isbServiceDef("2.10.3"))
+	assert.NoError(t, err)
+	assert.Nil(t, isbServiceRollout.Status.GetCondition(apiv1.ConditionGuardrailsBlocked))
+}
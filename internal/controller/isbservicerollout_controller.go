@@ -132,6 +132,18 @@ func (r *ISBServiceRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		}
 	}
 
+	// if asked to roll back to a previous revision, write it back into the spec: the updated spec is then reconciled as usual
+	if isbServiceRollout.DeletionTimestamp.IsZero() {
+		rolledBack, err := rollbackIfRequested(ctx, r.client, r.recorder, isbServiceRollout)
+		if err != nil {
+			r.ErrorHandler(isbServiceRollout, err, "RollbackFailed", "Failed to roll back isb service rollout")
+			return ctrl.Result{}, err
+		}
+		if rolledBack {
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
 	// save off a copy of the original before we modify it
 	isbServiceRolloutOrig := isbServiceRollout
 	isbServiceRollout = isbServiceRolloutOrig.DeepCopy()
@@ -146,6 +158,7 @@ func (r *ISBServiceRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			r.ErrorHandler(isbServiceRollout, statusUpdateErr, "UpdateStatusFailed", "Failed to update isb service rollout status")
 			return ctrl.Result{}, statusUpdateErr
 		}
+		recordRevision(ctx, r.client, r.recorder, isbServiceRollout)
 		return ctrl.Result{}, err
 	}
 
@@ -167,6 +180,8 @@ func (r *ISBServiceRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 	// Update the Status subresource
 	if isbServiceRollout.DeletionTimestamp.IsZero() { // would've already been deleted
+		recordRevision(ctx, r.client, r.recorder, isbServiceRollout)
+		result = processProgressDeadline(ctx, isbServiceRollout, isbServiceRollout.Spec.ProgressDeadlineSeconds, ControllerISBSVCRollout, r.recorder, r.customMetrics, result)
 		statusUpdateErr := r.updateISBServiceRolloutStatus(ctx, isbServiceRollout)
 		if statusUpdateErr != nil {
 			r.ErrorHandler(isbServiceRollout, statusUpdateErr, "UpdateStatusFailed", "Failed to update isb service rollout status")
@@ -202,7 +217,9 @@ func (r *ISBServiceRolloutReconciler) reconcile(ctx context.Context, isbServiceR
 	if !isbServiceRollout.DeletionTimestamp.IsZero() {
 		numaLogger.Info("Deleting ISBServiceRollout")
 		if controllerutil.ContainsFinalizer(isbServiceRollout, finalizerName) {
-			GetPauseModule().deletePauseRequest(isbsvcKey)
+			if err := GetPauseModule().deletePauseRequest(ctx, isbsvcKey); err != nil {
+				return ctrl.Result{}, err
+			}
 			controllerutil.RemoveFinalizer(isbServiceRollout, finalizerName)
 		}
 		// generate metrics for ISB Service deletion.
@@ -223,19 +240,21 @@ func (r *ISBServiceRolloutReconciler) reconcile(ctx context.Context, isbServiceR
 		GetPauseModule().newPauseRequest(isbsvcKey)
 	}
 
-	newISBServiceDef := &kubernetes.GenericObject{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       common.NumaflowISBServiceKind,
-			APIVersion: common.NumaflowAPIGroup + "/" + common.NumaflowAPIVersion,
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:            isbServiceRollout.Name,
-			Namespace:       isbServiceRollout.Namespace,
-			Labels:          isbServiceRollout.Spec.InterStepBufferService.Labels,
-			Annotations:     isbServiceRollout.Spec.InterStepBufferService.Annotations,
-			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(isbServiceRollout.GetObjectMeta(), apiv1.ISBServiceRolloutGroupVersionKind)},
-		},
-		Spec: isbServiceRollout.Spec.InterStepBufferService.Spec,
+	// the Numaflow Controller which manages the ISBService doesn't change during this reconciliation
+	ctx, err := withResolvedNumaflowControllerInstanceID(ctx, r.client, isbServiceRollout.Namespace, apiv1.ISBServiceRolloutGroupVersionKind.Kind,
+		isbServiceRollout.Name, isbServiceRollout.Spec.InterStepBufferService.Annotations)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// (after a Progressive upgrade, the promoted ISBService is no longer the one named after the ISBServiceRollout)
+	isbServiceName, err := r.getPromotedChildName(ctx, isbServiceRollout)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	newISBServiceDef, err := r.makeRunningISBServiceDefinition(ctx, isbServiceRollout, isbServiceName)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
 	existingISBServiceDef, err := kubernetes.GetResource(ctx, r.client, newISBServiceDef.GroupVersionKind(),
@@ -243,7 +262,7 @@ func (r *ISBServiceRolloutReconciler) reconcile(ctx context.Context, isbServiceR
 	if err != nil {
 		// create an object as it doesn't exist
 		if apierrors.IsNotFound(err) {
-			numaLogger.Debugf("ISBService %s/%s doesn't exist so creating", isbServiceRollout.Namespace, isbServiceRollout.Name)
+			numaLogger.Debugf("ISBService %s/%s doesn't exist so creating", newISBServiceDef.Namespace, newISBServiceDef.Name)
 			isbServiceRollout.Status.MarkPending()
 
 			if err = kubernetes.CreateResource(ctx, r.client, newISBServiceDef); err != nil {
@@ -260,16 +279,16 @@ func (r *ISBServiceRolloutReconciler) reconcile(ctx context.Context, isbServiceR
 		// Object already exists
 		// perform logic related to updating
 		newISBServiceDef = r.merge(existingISBServiceDef, newISBServiceDef)
-		needsRequeue, err := r.processExistingISBService(ctx, isbServiceRollout, existingISBServiceDef, newISBServiceDef, syncStartTime)
+		result, err := r.processExistingISBService(ctx, isbServiceRollout, existingISBServiceDef, newISBServiceDef, syncStartTime)
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("error processing existing ISBService: %v", err)
 		}
-		if needsRequeue {
-			return common.DefaultDelayedRequeue, nil
+		if !result.IsZero() {
+			return result, nil
 		}
 	}
 
-	if err = r.applyPodDisruptionBudget(ctx, isbServiceRollout); err != nil {
+	if err = r.applyPodDisruptionBudget(ctx, isbServiceRollout, isbServiceName); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to apply PodDisruptionBudget for ISBServiceRollout %s, err: %v", isbServiceRollout.Name, err)
 	}
 
@@ -304,10 +323,10 @@ func (r *ISBServiceRolloutReconciler) merge(existingISBService, newISBService *k
 
 // process an existing ISBService
 // return:
-// - true if needs a requeue
+// - the result indicating whether and when to requeue
 // - error if any
 func (r *ISBServiceRolloutReconciler) processExistingISBService(ctx context.Context, isbServiceRollout *apiv1.ISBServiceRollout,
-	existingISBServiceDef, newISBServiceDef *kubernetes.GenericObject, syncStartTime time.Time) (bool, error) {
+	existingISBServiceDef, newISBServiceDef *kubernetes.GenericObject, syncStartTime time.Time) (ctrl.Result, error) {
 
 	numaLogger := logger.FromContext(ctx)
 
@@ -316,15 +335,30 @@ func (r *ISBServiceRolloutReconciler) processExistingISBService(ctx context.Cont
 
 	_, isbServiceIsUpdating, err := r.isISBServiceUpdating(ctx, isbServiceRollout, existingISBServiceDef)
 	if err != nil {
-		return false, fmt.Errorf("error determining if ISBService is updating: %v", err)
+		return ctrl.Result{}, fmt.Errorf("error determining if ISBService is updating: %v", err)
 	}
 
 	// determine if we're trying to update the ISBService spec
 	// if it's a simple change, direct apply
 	// if not, it will require PPND or Progressive
-	isbServiceNeedsToUpdate, upgradeStrategyType, err := usde.ResourceNeedsUpdating(ctx, newISBServiceDef, existingISBServiceDef)
+	userPreferredStrategy, err := usde.GetUserStrategy(ctx, newISBServiceDef.Namespace, isbServiceRollout.GetUserUpgradeStrategy())
 	if err != nil {
-		return false, err
+		return ctrl.Result{}, err
+	}
+	isbServiceRollout.Status.UserStrategy = apiv1.UserUpgradeStrategy(userPreferredStrategy)
+
+	isbServiceNeedsToUpdate, upgradeStrategyType, err := usde.ResourceNeedsUpdating(ctx, newISBServiceDef, existingISBServiceDef, userPreferredStrategy)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// some changes can't be applied in place (e.g. switching between JetStream and Redis)
+	guardrailStrategy, err := checkISBServiceGuardrails(ctx, r.client, isbServiceRollout, existingISBServiceDef, newISBServiceDef)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if guardrailStrategy != apiv1.UpgradeStrategyNoOp {
+		isbServiceNeedsToUpdate = true
+		upgradeStrategyType = guardrailStrategy
 	}
 	numaLogger.
 		WithValues("isbserviceNeedsToUpdate", isbServiceNeedsToUpdate, "upgradeStrategyType", upgradeStrategyType).
@@ -345,16 +379,38 @@ func (r *ISBServiceRolloutReconciler) processExistingISBService(ctx context.Cont
 
 	// if not, should we set one?
 	if !inProgressStrategySet {
+		// an update requiring PPND, Progressive or Recreate can only start during a maintenance window
+		awaitingWindow, untilWindow, err := awaitMaintenanceWindow(ctx, isbServiceRollout, isbServiceRollout.GetMaintenanceWindows(), upgradeStrategyType)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if awaitingWindow {
+			// come back when the maintenance window opens
+			return ctrl.Result{RequeueAfter: untilWindow}, nil
+		}
+
 		if upgradeStrategyType == apiv1.UpgradeStrategyPPND {
 			inProgressStrategy = apiv1.UpgradeStrategyPPND
 			r.inProgressStrategyMgr.setStrategy(ctx, isbServiceRollout, inProgressStrategy)
 		}
 		if upgradeStrategyType == apiv1.UpgradeStrategyProgressive {
-			inProgressStrategy = apiv1.UpgradeStrategyProgressive
-			r.inProgressStrategyMgr.setStrategy(ctx, isbServiceRollout, inProgressStrategy)
+			// don't retry a spec which already failed and was rolled back
+			previouslyFailed, err := progressiveUpgradePreviouslyFailed(ctx, isbServiceRollout, isbServiceProgressiveController{r})
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if previouslyFailed {
+				numaLogger.Debug("Progressive upgrade of this spec previously failed, not retrying")
+				isbServiceRollout.Status.MarkProgressiveUpgradeFailed("Upgrade to this spec previously failed and was rolled back; update the spec to retry", isbServiceRollout.Generation)
+			} else {
+				inProgressStrategy = apiv1.UpgradeStrategyProgressive
+				r.inProgressStrategyMgr.setStrategy(ctx, isbServiceRollout, inProgressStrategy)
+			}
 		}
 	}
 
+	requeue := false
+
 	switch inProgressStrategy {
 	case apiv1.UpgradeStrategyPPND:
 		done, err := processChildObjectWithPPND(ctx, r.client, isbServiceRollout, r, isbServiceNeedsToUpdate, isbServiceIsUpdating, func() error {
@@ -367,30 +423,67 @@ func (r *ISBServiceRolloutReconciler) processExistingISBService(ctx context.Cont
 			return nil
 		})
 		if err != nil {
-			return false, err
+			return ctrl.Result{}, err
 		}
 		if done {
 			r.inProgressStrategyMgr.unsetStrategy(ctx, isbServiceRollout)
 		} else {
 			// requeue if done with PPND is false
-			return true, nil
+			return common.DefaultDelayedRequeue, nil
+		}
+	case apiv1.UpgradeStrategyProgressive:
+		// once started, a Progressive upgrade runs to completion even if the promoted child no longer needs updating
+		if isbServiceNeedsToUpdate || progressiveUpgradeStarted(isbServiceRollout) {
+			numaLogger.Debug("processing ISBService with Progressive")
+			done, err := processResourceWithProgressive(ctx, isbServiceRollout, existingISBServiceDef, isbServiceProgressiveController{r}, r.client)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if done {
+				r.inProgressStrategyMgr.unsetStrategy(ctx, isbServiceRollout)
+			} else {
+				// the upgrading ISBService may be under Analysis, which needs to be checked periodically
+				requeue = true
+			}
+		}
+	case apiv1.UpgradeStrategyNoOp:
+		if isbServiceNeedsToUpdate && upgradeStrategyType == apiv1.UpgradeStrategyRecreate {
+			// the ISBService will be created again with the new spec once it's gone
+			numaLogger.Infof("deleting ISBService %s/%s in order to recreate it", existingISBServiceDef.Namespace, existingISBServiceDef.Name)
+			if err := kubernetes.DeleteResource(ctx, r.client, existingISBServiceDef); err != nil {
+				return ctrl.Result{}, err
+			}
+			isbServiceRollout.Status.MarkWaitingFor("ISBService %s to be deleted so that it can be recreated", existingISBServiceDef.Name)
+			return common.DefaultDelayedRequeue, nil
 		}
-	// TODO: Progressive strategy should ideally be creating a second parallel isbsvc, and all Pipelines should be on it;
-	// for now we just create a 2nd ISBServiceRollout, so we need the Apply path to work
-	case apiv1.UpgradeStrategyNoOp, apiv1.UpgradeStrategyProgressive:
 		if isbServiceNeedsToUpdate {
 			// update ISBService
 			err = r.updateISBService(ctx, isbServiceRollout, newISBServiceDef)
 			if err != nil {
-				return false, fmt.Errorf("error updating ISBService, %s: %v", apiv1.UpgradeStrategyNoOp, err)
+				return ctrl.Result{}, fmt.Errorf("error updating ISBService, %s: %v", apiv1.UpgradeStrategyNoOp, err)
 			}
 			r.customMetrics.ReconciliationDuration.WithLabelValues(ControllerISBSVCRollout, "update").Observe(time.Since(syncStartTime).Seconds())
 		}
 	default:
-		return false, fmt.Errorf("%v strategy not recognized", inProgressStrategy)
+		return ctrl.Result{}, fmt.Errorf("%v strategy not recognized", inProgressStrategy)
 	}
 
-	return false, nil
+	// clean up recyclable ISBServices once their Pipelines have moved off of them
+	err = garbageCollectChildren(ctx, isbServiceRollout, isbServiceProgressiveController{r}, r.client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	recyclableISBServices, err := getRecyclableObjects(ctx, isbServiceRollout, isbServiceProgressiveController{r})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(recyclableISBServices) > 0 {
+		isbServiceRollout.Status.MarkWaitingFor("Pipelines to move off of %d previously promoted ISBService(s)", len(recyclableISBServices))
+	}
+	if requeue || len(recyclableISBServices) > 0 {
+		return common.DefaultDelayedRequeue, nil
+	}
+	return ctrl.Result{}, nil
 }
 
 func (r *ISBServiceRolloutReconciler) updateISBService(ctx context.Context, isbServiceRollout *apiv1.ISBServiceRollout, newISBServiceDef *kubernetes.GenericObject) error {
@@ -431,6 +524,11 @@ func (r *ISBServiceRolloutReconciler) updatePauseMetric(isbServiceRollout *apiv1
 	r.customMetrics.ISBServicePausedSeconds.WithLabelValues(isbServiceRollout.Name).Set(timeElapsed.Seconds())
 }
 
+func (r *ISBServiceRolloutReconciler) recordPauseTimeout(rollout client.Object, policy apiv1.PauseTimeoutPolicy, message string) {
+	r.recorder.Event(rollout, corev1.EventTypeWarning, "PauseTimedOut", message)
+	r.customMetrics.PauseTimeouts.WithLabelValues(ControllerISBSVCRollout, rollout.GetNamespace(), rollout.GetName(), string(policy)).Inc()
+}
+
 func (r *ISBServiceRolloutReconciler) getRolloutKey(rolloutNamespace string, rolloutName string) string {
 	return GetPauseModule().getISBServiceKey(rolloutNamespace, rolloutName)
 }
@@ -471,11 +569,13 @@ func (r *ISBServiceRolloutReconciler) getPipelineList(ctx context.Context, rollo
 	)
 }
 
-// Apply pod disruption budget for the ISBService
-func (r *ISBServiceRolloutReconciler) applyPodDisruptionBudget(ctx context.Context, isbServiceRollout *apiv1.ISBServiceRollout) error {
+// Apply pod disruption budget for the promoted ISBService
+func (r *ISBServiceRolloutReconciler) applyPodDisruptionBudget(ctx context.Context, isbServiceRollout *apiv1.ISBServiceRollout, isbServiceName string) error {
 	pdb := kubernetes.NewPodDisruptionBudget(isbServiceRollout.Name, isbServiceRollout.Namespace, 1,
 		[]metav1.OwnerReference{*metav1.NewControllerRef(isbServiceRollout.GetObjectMeta(), apiv1.ISBServiceRolloutGroupVersionKind)},
 	)
+	// the PDB keeps the ISBServiceRollout's name, but follows the promoted ISBService
+	pdb.Spec.Selector.MatchLabels[numaflowv1.KeyISBSvcName] = isbServiceName
 
 	// Create the pdb only if it doesn't exist
 	existingPDB := &policyv1.PodDisruptionBudget{}
@@ -489,8 +589,9 @@ func (r *ISBServiceRolloutReconciler) applyPodDisruptionBudget(ctx context.Conte
 		}
 	} else {
 		// Update the pdb if needed
-		if existingPDB.Spec.MaxUnavailable != pdb.Spec.MaxUnavailable {
+		if existingPDB.Spec.MaxUnavailable != pdb.Spec.MaxUnavailable || !equality.Semantic.DeepEqual(existingPDB.Spec.Selector, pdb.Spec.Selector) {
 			existingPDB.Spec.MaxUnavailable = pdb.Spec.MaxUnavailable
+			existingPDB.Spec.Selector = pdb.Spec.Selector
 			if err := r.client.Update(ctx, existingPDB); err != nil {
 				return err
 			}
@@ -619,7 +720,7 @@ func (r *ISBServiceRolloutReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	// Watch ISBServiceRollouts
 	if err := controller.Watch(source.Kind(mgr.GetCache(), &apiv1.ISBServiceRollout{},
-		&handler.TypedEnqueueRequestForObject[*apiv1.ISBServiceRollout]{}, predicate.TypedGenerationChangedPredicate[*apiv1.ISBServiceRollout]{})); err != nil {
+		&handler.TypedEnqueueRequestForObject[*apiv1.ISBServiceRollout]{}, rolloutChangedPredicate[*apiv1.ISBServiceRollout]())); err != nil {
 		return fmt.Errorf("failed to watch ISBServiceRollout: %v", err)
 	}
 
@@ -282,7 +282,8 @@ func Test_reconcile_isbservicerollout_PPND(t *testing.T) {
 		existingPipeline          *numaflowv1.Pipeline
 		existingPauseRequest      *bool // was ISBServiceRollout previously requesting pause?
 		initialInProgressStrategy apiv1.UpgradeStrategy
-		expectedPauseRequest      *bool // after reconcile(), should it be requesting pause?
+		pauseTimedOutPolicy       apiv1.PauseTimeoutPolicy // if set, the pause has already exceeded the pause timeout
+		expectedPauseRequest      *bool                    // after reconcile(), should it be requesting pause?
 		expectedRolloutPhase      apiv1.Phase
 		// require these Conditions to be set (note that in real life, previous reconciliations may have set other Conditions from before which are still present)
 		expectedConditionsSet      map[apiv1.ConditionType]metav1.ConditionStatus
@@ -389,6 +390,64 @@ func Test_reconcile_isbservicerollout_PPND(t *testing.T) {
 			expectedISBSvcSpec:         createDefaultISBServiceSpec("2.10.11"),
 			expectedInProgressStrategy: apiv1.UpgradeStrategyPPND,
 		},
+		{
+			name:                      "existing ISBService - new spec - pause timed out - abort",
+			newISBSvcSpec:             createDefaultISBServiceSpec("2.10.11"),
+			existingISBSvcDef:         createDefaultISBService("2.10.3", numaflowv1.ISBSvcPhaseRunning, true),
+			existingStatefulSetDef:    createDefaultISBStatefulSet("2.10.3", true),
+			existingPipelineRollout:   createPipelineRollout(numaflowv1.PipelineSpec{InterStepBufferServiceName: defaultISBSvcRolloutName}, map[string]string{}, map[string]string{}),
+			existingPipeline:          createDefaultPipelineOfPhase(numaflowv1.PipelinePhasePausing),
+			existingPauseRequest:      &trueValue,
+			initialInProgressStrategy: apiv1.UpgradeStrategyPPND,
+			pauseTimedOutPolicy:       apiv1.PauseTimeoutPolicyAbort,
+			expectedPauseRequest:      &falseValue,
+			expectedRolloutPhase:      apiv1.PhasePending,
+			expectedConditionsSet: map[apiv1.ConditionType]metav1.ConditionStatus{
+				apiv1.ConditionPauseTimedOut:    metav1.ConditionTrue,
+				apiv1.ConditionPausingPipelines: metav1.ConditionFalse,
+			},
+			expectedISBSvcSpec:         createDefaultISBServiceSpec("2.10.3"),
+			expectedInProgressStrategy: apiv1.UpgradeStrategyPPND,
+		},
+		{
+			name:                      "existing ISBService - new spec - pause timed out - force",
+			newISBSvcSpec:             createDefaultISBServiceSpec("2.10.11"),
+			existingISBSvcDef:         createDefaultISBService("2.10.3", numaflowv1.ISBSvcPhaseRunning, true),
+			existingStatefulSetDef:    createDefaultISBStatefulSet("2.10.3", true),
+			existingPipelineRollout:   createPipelineRollout(numaflowv1.PipelineSpec{InterStepBufferServiceName: defaultISBSvcRolloutName}, map[string]string{}, map[string]string{}),
+			existingPipeline:          createDefaultPipelineOfPhase(numaflowv1.PipelinePhasePausing),
+			existingPauseRequest:      &trueValue,
+			initialInProgressStrategy: apiv1.UpgradeStrategyPPND,
+			pauseTimedOutPolicy:       apiv1.PauseTimeoutPolicyForce,
+			expectedPauseRequest:      &trueValue,
+			expectedRolloutPhase:      apiv1.PhaseDeployed,
+			expectedConditionsSet: map[apiv1.ConditionType]metav1.ConditionStatus{
+				apiv1.ConditionChildResourceDeployed: metav1.ConditionTrue,
+				apiv1.ConditionPauseTimedOut:         metav1.ConditionTrue,
+				apiv1.ConditionPausingPipelines:      metav1.ConditionTrue,
+			},
+			expectedISBSvcSpec:         createDefaultISBServiceSpec("2.10.11"),
+			expectedInProgressStrategy: apiv1.UpgradeStrategyPPND,
+		},
+		{
+			name:                      "existing ISBService - new spec - pause timed out - notify",
+			newISBSvcSpec:             createDefaultISBServiceSpec("2.10.11"),
+			existingISBSvcDef:         createDefaultISBService("2.10.3", numaflowv1.ISBSvcPhaseRunning, true),
+			existingStatefulSetDef:    createDefaultISBStatefulSet("2.10.3", true),
+			existingPipelineRollout:   createPipelineRollout(numaflowv1.PipelineSpec{InterStepBufferServiceName: defaultISBSvcRolloutName}, map[string]string{}, map[string]string{}),
+			existingPipeline:          createDefaultPipelineOfPhase(numaflowv1.PipelinePhasePausing),
+			existingPauseRequest:      &trueValue,
+			initialInProgressStrategy: apiv1.UpgradeStrategyPPND,
+			pauseTimedOutPolicy:       apiv1.PauseTimeoutPolicyNotify,
+			expectedPauseRequest:      &trueValue,
+			expectedRolloutPhase:      apiv1.PhasePending,
+			expectedConditionsSet: map[apiv1.ConditionType]metav1.ConditionStatus{
+				apiv1.ConditionPauseTimedOut:    metav1.ConditionTrue,
+				apiv1.ConditionPausingPipelines: metav1.ConditionTrue,
+			},
+			expectedISBSvcSpec:         createDefaultISBServiceSpec("2.10.3"),
+			expectedInProgressStrategy: apiv1.UpgradeStrategyPPND,
+		},
 		{
 			name:                      "existing ISBService - spec already updated - isbsvc reconciling",
 			newISBSvcSpec:             createDefaultISBServiceSpec("2.10.11"),
@@ -450,6 +509,14 @@ func Test_reconcile_isbservicerollout_PPND(t *testing.T) {
 			// the Reconcile() function does this, so we need to do it before calling reconcile() as well
 			rollout.Status.Init(rollout.Generation)
 
+			usdeConfig := config.USDEConfig{DefaultUpgradeStrategy: config.PPNDStrategyID}
+			if tc.pauseTimedOutPolicy != "" {
+				usdeConfig.PauseTimeout = time.Minute
+				usdeConfig.PauseTimeoutPolicy = tc.pauseTimedOutPolicy
+				rollout.Status.PauseRequestStatus.LastPauseBeginTime = metav1.NewTime(time.Now().Add(-time.Hour))
+			}
+			config.GetConfigManagerInstance().UpdateUSDEConfig(usdeConfig)
+
 			// create the already-existing ISBSvc in Kubernetes
 			if tc.existingISBSvcDef != nil {
 				createISBSvcInK8S(ctx, t, numaflowClientSet, tc.existingISBSvcDef)
@@ -501,10 +568,120 @@ func Test_reconcile_isbservicerollout_PPND(t *testing.T) {
 				assert.True(t, found, "condition type %s failed, conditions=%+v", conditionType, rollout.Status.Conditions)
 			}
 
+			// Check the pause timeout policy is reported
+			assert.Equal(t, tc.pauseTimedOutPolicy, rollout.Status.PauseRequestStatus.TimeoutPolicy)
+
 		})
 	}
 }
 
+// a Progressive upgrade creates a new ISBService, moves the Pipelines onto it, and then deletes the original one
+func Test_reconcile_isbservicerollout_Progressive(t *testing.T) {
+	restConfig, numaflowClientSet, numaplaneClient, k8sClientSet, err := commontest.PrepareK8SEnvironment()
+	assert.Nil(t, err)
+	assert.Nil(t, kubernetes.SetDynamicClient(restConfig))
+
+	ctx := context.Background()
+	config.GetConfigManagerInstance().UpdateUSDEConfig(config.USDEConfig{DefaultUpgradeStrategy: config.ProgressiveStrategyID})
+	defer config.GetConfigManagerInstance().UpdateUSDEConfig(config.USDEConfig{})
+
+	// other tests may call this, but it fails if called more than once
+	if customMetrics == nil {
+		customMetrics = metrics.RegisterCustomMetrics()
+	}
+	r := NewISBServiceRolloutReconciler(numaplaneClient, scheme.Scheme, customMetrics, record.NewFakeRecorder(64))
+	pipelineROReconciler = &PipelineRolloutReconciler{client: numaplaneClient, queue: util.NewWorkQueue("fake_queue")}
+
+	upgradingISBSvcName := defaultISBSvcRolloutName + "-0"
+	cleanup := func() {
+		_ = numaplaneClient.Delete(ctx, &apiv1.ISBServiceRollout{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: defaultISBSvcRolloutName}})
+		for _, isbsvcName := range []string{defaultISBSvcRolloutName, upgradingISBSvcName} {
+			_ = numaflowClientSet.NumaflowV1alpha1().InterStepBufferServices(defaultNamespace).Delete(ctx, isbsvcName, metav1.DeleteOptions{})
+		}
+		_ = k8sClientSet.AppsV1().StatefulSets(defaultNamespace).Delete(ctx, deriveISBSvcStatefulSetName(defaultISBSvcRolloutName), metav1.DeleteOptions{})
+		_ = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Delete(ctx, defaultPipelineName, metav1.DeleteOptions{})
+		_ = numaplaneClient.Delete(ctx, &apiv1.PipelineRollout{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: defaultPipelineRolloutName}})
+	}
+	cleanup()
+	defer cleanup()
+
+	createISBSvcInK8S(ctx, t, numaflowClientSet, createDefaultISBService("2.10.3", numaflowv1.ISBSvcPhaseRunning, true))
+	createStatefulSetInK8S(ctx, t, k8sClientSet, createDefaultISBStatefulSet("2.10.3", true))
+	createPipelineRolloutInK8S(ctx, t, numaplaneClient, createPipelineRollout(numaflowv1.PipelineSpec{InterStepBufferServiceName: defaultISBSvcRolloutName}, map[string]string{}, map[string]string{}))
+	createPipelineInK8S(ctx, t, numaflowClientSet, createDefaultPipelineOfPhase(numaflowv1.PipelinePhaseRunning))
+
+	rollout := createISBServiceRollout(createDefaultISBServiceSpec("2.10.11"))
+	rollout.UID = ""
+	assert.NoError(t, numaplaneClient.Create(ctx, rollout))
+	rollout.Status.Init(rollout.Generation)
+
+	// the new ISBService is created alongside the original one
+	_, err = r.reconcile(ctx, rollout, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, apiv1.UpgradeStrategyProgressive, rollout.Status.UpgradeInProgress)
+	assert.Equal(t, apiv1.ProgressiveStateAssessing, rollout.Status.ProgressiveStatus.State)
+	assert.Equal(t, upgradingISBSvcName, rollout.Status.ProgressiveStatus.UpgradingChildName)
+	originalISBSvc, err := numaflowClientSet.NumaflowV1alpha1().InterStepBufferServices(defaultNamespace).Get(ctx, defaultISBSvcRolloutName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, createDefaultISBServiceSpec("2.10.3"), originalISBSvc.Spec)
+	upgradingISBSvc, err := numaflowClientSet.NumaflowV1alpha1().InterStepBufferServices(defaultNamespace).Get(ctx, upgradingISBSvcName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, createDefaultISBServiceSpec("2.10.11"), upgradingISBSvc.Spec)
+	assert.Equal(t, string(common.LabelValueUpgradeInProgress), upgradingISBSvc.Labels[common.LabelKeyUpgradeState])
+
+	// until it's promoted, Pipelines stay on the original one
+	pipelineSpec, err := pipelineROReconciler.getPipelineSpecWithPromotedISBService(ctx, createPipelineRollout(numaflowv1.PipelineSpec{InterStepBufferServiceName: defaultISBSvcRolloutName}, map[string]string{}, map[string]string{}))
+	assert.NoError(t, err)
+	assert.Equal(t, defaultISBSvcRolloutName, getTestPipelineISBSvcName(t, pipelineSpec))
+
+	// once the new ISBService is healthy, it's promoted and the Pipelines are told to move onto it
+	upgradingISBSvc.Status = numaflowv1.InterStepBufferServiceStatus{
+		Phase: numaflowv1.ISBSvcPhaseRunning,
+		Status: numaflowv1.Status{Conditions: []metav1.Condition{{
+			Type: "ChildrenResourcesHealthy", Status: metav1.ConditionTrue, Reason: "Healthy", LastTransitionTime: metav1.Now(),
+		}}},
+	}
+	_, err = numaflowClientSet.NumaflowV1alpha1().InterStepBufferServices(defaultNamespace).UpdateStatus(ctx, upgradingISBSvc, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	result, err := r.reconcile(ctx, rollout, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, common.DefaultDelayedRequeue, result)
+	assert.Equal(t, apiv1.ProgressiveStateDone, rollout.Status.ProgressiveStatus.State)
+	assert.Equal(t, apiv1.UpgradeStrategy(""), rollout.Status.UpgradeInProgress)
+	assert.Equal(t, 1, pipelineROReconciler.queue.Len())
+	pipelineSpec, err = pipelineROReconciler.getPipelineSpecWithPromotedISBService(ctx, createPipelineRollout(numaflowv1.PipelineSpec{InterStepBufferServiceName: defaultISBSvcRolloutName}, map[string]string{}, map[string]string{}))
+	assert.NoError(t, err)
+	assert.Equal(t, upgradingISBSvcName, getTestPipelineISBSvcName(t, pipelineSpec))
+
+	// the original ISBService remains as long as a Pipeline uses it
+	originalISBSvc, err = numaflowClientSet.NumaflowV1alpha1().InterStepBufferServices(defaultNamespace).Get(ctx, defaultISBSvcRolloutName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, string(common.LabelValueUpgradeRecyclable), originalISBSvc.Labels[common.LabelKeyUpgradeState])
+
+	// once the Pipeline has moved, it's deleted
+	pipeline, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Get(ctx, defaultPipelineName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	pipeline.Spec.InterStepBufferServiceName = upgradingISBSvcName
+	_, err = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Update(ctx, pipeline, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	result, err = r.reconcile(ctx, rollout, time.Now())
+	assert.NoError(t, err)
+	assert.True(t, result.IsZero())
+	_, err = numaflowClientSet.NumaflowV1alpha1().InterStepBufferServices(defaultNamespace).Get(ctx, defaultISBSvcRolloutName, metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// and the PodDisruptionBudget follows the promoted ISBService
+	pdb := &policyv1.PodDisruptionBudget{}
+	assert.NoError(t, numaplaneClient.Get(ctx, k8stypes.NamespacedName{Namespace: defaultNamespace, Name: defaultISBSvcRolloutName}, pdb))
+	assert.Equal(t, upgradingISBSvcName, pdb.Spec.Selector.MatchLabels[numaflowv1.KeyISBSvcName])
+}
+
+func getTestPipelineISBSvcName(t *testing.T, rawSpec k8sruntime.RawExtension) string {
+	var pipelineSpec PipelineSpec
+	assert.NoError(t, json.Unmarshal(rawSpec.Raw, &pipelineSpec))
+	return pipelineSpec.getISBSvcName()
+}
+
 func createDefaultISBServiceSpec(jetstreamVersion string) numaflowv1.InterStepBufferServiceSpec {
 	return numaflowv1.InterStepBufferServiceSpec{
 		Redis: &numaflowv1.RedisBufferService{},
@@ -532,6 +709,10 @@ func createDefaultISBService(jetstreamVersion string, phase numaflowv1.ISBSvcPha
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      defaultISBSvcRolloutName,
 			Namespace: defaultNamespace,
+			Labels: map[string]string{
+				common.LabelKeyParentRollout: defaultISBSvcRolloutName,
+				common.LabelKeyUpgradeState:  string(common.LabelValueUpgradePromoted),
+			},
 		},
 		Spec:   createDefaultISBServiceSpec(jetstreamVersion),
 		Status: status,
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// A Progressive upgrade of an ISBServiceRollout creates a new InterStepBufferService alongside the promoted one.
+// Once the new one is promoted, the Pipelines which use the ISBServiceRollout are moved onto it by their PipelineRollouts
+// (see getPromotedISBServiceName()), and the previously promoted one is deleted once no Pipeline uses it any longer.
+
+// get the name of the ISBServiceRollout's promoted InterStepBufferService
+// (the first InterStepBufferService of an ISBServiceRollout has the same name as the ISBServiceRollout; any created by a
+// Progressive upgrade after that are suffixed with an index)
+func (r *ISBServiceRolloutReconciler) getPromotedChildName(ctx context.Context, isbServiceRollout *apiv1.ISBServiceRollout) (string, error) {
+	isbsvcName, found, err := findChildName(ctx, isbServiceRollout, isbServiceProgressiveController{r}, string(common.LabelValueUpgradePromoted))
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return isbServiceRollout.Name, nil
+	}
+	return isbsvcName, nil
+}
+
+// get the name of the promoted InterStepBufferService of the ISBServiceRollout with this name, which is the one that
+// Pipelines using the ISBServiceRollout should run on
+// (if there's no such ISBServiceRollout, the InterStepBufferService is assumed to have the same name)
+func getPromotedISBServiceName(ctx context.Context, c client.Client, namespace string, isbServiceRolloutName string) (string, error) {
+	isbServiceRollout := &apiv1.ISBServiceRollout{}
+	if err := c.Get(ctx, k8stypes.NamespacedName{Namespace: namespace, Name: isbServiceRolloutName}, isbServiceRollout); err != nil {
+		if apierrors.IsNotFound(err) {
+			return isbServiceRolloutName, nil
+		}
+		return "", fmt.Errorf("error getting ISBServiceRollout %s/%s: %v", namespace, isbServiceRolloutName, err)
+	}
+	return (&ISBServiceRolloutReconciler{}).getPromotedChildName(ctx, isbServiceRollout)
+}
+
+// create the definition of the ISBServiceRollout's InterStepBufferService with the given name, labeled "promoted"
+func (r *ISBServiceRolloutReconciler) makeRunningISBServiceDefinition(ctx context.Context, isbServiceRollout *apiv1.ISBServiceRollout, name string) (*kubernetes.GenericObject, error) {
+	isbServiceDef, err := r.makeISBServiceDefinition(ctx, isbServiceRollout, name)
+	if err != nil {
+		return nil, err
+	}
+	isbServiceDef.Labels[common.LabelKeyUpgradeState] = string(common.LabelValueUpgradePromoted)
+	return isbServiceDef, nil
+}
+
+func (r *ISBServiceRolloutReconciler) makeISBServiceDefinition(ctx context.Context, isbServiceRollout *apiv1.ISBServiceRollout, name string) (*kubernetes.GenericObject, error) {
+	labels := map[string]string{}
+	for key, val := range isbServiceRollout.Spec.InterStepBufferService.Labels {
+		labels[key] = val
+	}
+	labels[common.LabelKeyParentRollout] = isbServiceRollout.Name
+	annotations, err := withNumaflowControllerInstanceID(ctx, r.client, isbServiceRollout.Namespace, apiv1.ISBServiceRolloutGroupVersionKind.Kind, isbServiceRollout.Name, isbServiceRollout.Spec.InterStepBufferService.Annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kubernetes.GenericObject{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       common.NumaflowISBServiceKind,
+			APIVersion: common.NumaflowAPIGroup + "/" + common.NumaflowAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       isbServiceRollout.Namespace,
+			Labels:          labels,
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(isbServiceRollout.GetObjectMeta(), apiv1.ISBServiceRolloutGroupVersionKind)},
+		},
+		Spec: isbServiceRollout.Spec.InterStepBufferService.Spec,
+	}, nil
+}
+
+// isbServiceProgressiveController adapts the ISBServiceRolloutReconciler to the progressiveController interface, whose merge
+// may return an error
+type isbServiceProgressiveController struct {
+	*ISBServiceRolloutReconciler
+}
+
+func (c isbServiceProgressiveController) merge(existingISBService, newISBService *kubernetes.GenericObject) (*kubernetes.GenericObject, error) {
+	return c.ISBServiceRolloutReconciler.merge(existingISBService, newISBService), nil
+}
+
+// the following functions enable isbServiceProgressiveController to implement progressiveController interface
+func (r *ISBServiceRolloutReconciler) listChildren(ctx context.Context, rolloutObject RolloutObject, labelSelector string, fieldSelector string) ([]*kubernetes.GenericObject, error) {
+	isbServiceRollout := rolloutObject.(*apiv1.ISBServiceRollout)
+	return kubernetes.ListLiveResource(
+		ctx, common.NumaflowAPIGroup, common.NumaflowAPIVersion, "interstepbufferservices",
+		isbServiceRollout.Namespace, labelSelector, fieldSelector)
+}
+
+func (r *ISBServiceRolloutReconciler) createBaseChildDefinition(ctx context.Context, rolloutObject RolloutObject, name string) (*kubernetes.GenericObject, error) {
+	isbServiceRollout := rolloutObject.(*apiv1.ISBServiceRollout)
+	return r.makeISBServiceDefinition(ctx, isbServiceRollout, name)
+}
+
+func (r *ISBServiceRolloutReconciler) getCurrentChildCount(rolloutObject RolloutObject) (int32, bool) {
+	isbServiceRollout := rolloutObject.(*apiv1.ISBServiceRollout)
+	if isbServiceRollout.Status.NameCount == nil {
+		return int32(0), false
+	} else {
+		return *isbServiceRollout.Status.NameCount, true
+	}
+}
+
+func (r *ISBServiceRolloutReconciler) updateCurrentChildCount(ctx context.Context, rolloutObject RolloutObject, nameCount int32) error {
+	isbServiceRollout := rolloutObject.(*apiv1.ISBServiceRollout)
+	isbServiceRollout.Status.NameCount = &nameCount
+	return r.updateISBServiceRolloutStatus(ctx, isbServiceRollout)
+}
+
+// increment the child count for the Rollout and return the count to use
+func (r *ISBServiceRolloutReconciler) incrementChildCount(ctx context.Context, rolloutObject RolloutObject) (int32, error) {
+	currentNameCount, found := r.getCurrentChildCount(rolloutObject)
+	if !found {
+		currentNameCount = int32(0)
+		err := r.updateCurrentChildCount(ctx, rolloutObject, int32(0))
+		if err != nil {
+			return int32(0), err
+		}
+	}
+
+	err := r.updateCurrentChildCount(ctx, rolloutObject, currentNameCount+1)
+	if err != nil {
+		return int32(0), err
+	}
+	return currentNameCount, nil
+}
+
+// an InterStepBufferService is drained once no Pipeline in its namespace runs on it any longer
+func (r *ISBServiceRolloutReconciler) childIsDrained(ctx context.Context, isbsvc *kubernetes.GenericObject) (bool, error) {
+	gvk := schema.GroupVersionKind{Group: common.NumaflowAPIGroup, Version: common.NumaflowAPIVersion, Kind: common.NumaflowPipelineKind}
+	pipelines, err := kubernetes.ListResources(ctx, r.client, gvk, client.InNamespace(isbsvc.Namespace))
+	if err != nil {
+		return false, err
+	}
+	for _, pipeline := range pipelines {
+		var pipelineSpec PipelineSpec
+		if err := json.Unmarshal(pipeline.Spec.Raw, &pipelineSpec); err != nil {
+			return false, fmt.Errorf("failed to convert Pipeline spec %q into PipelineSpec type, err=%v", string(pipeline.Spec.Raw), err)
+		}
+		if pipelineSpec.getISBSvcName() == isbsvc.Name {
+			logger.FromContext(ctx).Debugf("ISBService %s/%s is still used by Pipeline %s", isbsvc.Namespace, isbsvc.Name, pipeline.Name)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// an InterStepBufferService is drained by moving its Pipelines onto the promoted one, which their PipelineRollouts do
+// once they're reconciled
+func (r *ISBServiceRolloutReconciler) drain(ctx context.Context, isbsvc *kubernetes.GenericObject) error {
+	pipelines, err := r.getPipelineList(ctx, isbsvc.Namespace, isbsvc.Labels[common.LabelKeyParentRollout])
+	if err != nil {
+		return err
+	}
+	for _, pipeline := range pipelines {
+		pipelineROReconciler.enqueuePipeline(k8stypes.NamespacedName{Namespace: pipeline.Namespace, Name: pipeline.Labels[common.LabelKeyParentRollout]})
+	}
+	return nil
+}
+
+func (r *ISBServiceRolloutReconciler) childNeedsUpdating(ctx context.Context, existingChild *kubernetes.GenericObject, newChildDefinition *kubernetes.GenericObject) (bool, error) {
+	existingSpecAsMap := make(map[string]interface{})
+	if err := json.Unmarshal(existingChild.Spec.Raw, &existingSpecAsMap); err != nil {
+		return false, err
+	}
+	newSpecAsMap := make(map[string]interface{})
+	if err := json.Unmarshal(newChildDefinition.Spec.Raw, &newSpecAsMap); err != nil {
+		return false, err
+	}
+	return !reflect.DeepEqual(existingSpecAsMap, newSpecAsMap), nil
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/numaproj/numaplane/internal/usde"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// awaitMaintenanceWindow determines whether an update using the given strategy must wait for a maintenance window:
+// this is the case for the disruptive strategies (PPND, Progressive and Recreate) outside of the maintenance windows in effect
+// for the Rollout.
+// The Rollout's AwaitingMaintenanceWindow Condition is set accordingly, and its phase is set to Pending while it waits.
+// return whether to wait, how long until the next window opens, and error if any
+func awaitMaintenanceWindow(ctx context.Context, rollout revisionedRollout, rolloutWindows []apiv1.MaintenanceWindow,
+	upgradeStrategy apiv1.UpgradeStrategy) (bool, time.Duration, error) {
+
+	numaLogger := logger.FromContext(ctx)
+	status := rollout.GetStatus()
+
+	notAwaiting := func() {
+		if status.GetCondition(apiv1.ConditionAwaitingMaintenanceWindow) != nil {
+			status.MarkFalse(apiv1.ConditionAwaitingMaintenanceWindow, "NotAwaiting", "No update is waiting for a maintenance window", rollout.GetGeneration())
+		}
+	}
+
+	if upgradeStrategy != apiv1.UpgradeStrategyPPND && upgradeStrategy != apiv1.UpgradeStrategyProgressive && upgradeStrategy != apiv1.UpgradeStrategyRecreate {
+		notAwaiting()
+		return false, 0, nil
+	}
+
+	windows := usde.GetMaintenanceWindows(rollout.GetNamespace(), rolloutWindows)
+	inWindow, nextStart, err := usde.InMaintenanceWindow(windows, time.Now())
+	if err != nil {
+		return false, 0, err
+	}
+	if inWindow {
+		notAwaiting()
+		return false, 0, nil
+	}
+	if nextStart.IsZero() {
+		return false, 0, fmt.Errorf("none of the maintenance windows of %s/%s will ever open", rollout.GetNamespace(), rollout.GetName())
+	}
+
+	numaLogger.Debugf("%s update waiting for the maintenance window starting at %s", upgradeStrategy, nextStart)
+	status.MarkPending()
+	status.MarkWaitingFor("the maintenance window opening at %s", nextStart.UTC().Format(time.RFC3339))
+	status.MarkTrueWithReason(apiv1.ConditionAwaitingMaintenanceWindow, "OutsideMaintenanceWindow",
+		fmt.Sprintf("%s update will start in the maintenance window opening at %s", upgradeStrategy, nextStart.UTC().Format(time.RFC3339)),
+		rollout.GetGeneration())
+	return true, time.Until(nextStart), nil
+}
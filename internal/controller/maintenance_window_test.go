@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_awaitMaintenanceWindow(t *testing.T) {
+	// a window which is only open on February 29th
+	outsideWindows := []apiv1.MaintenanceWindow{{Schedule: "0 0 29 2 *", Duration: metav1.Duration{Duration: time.Minute}}}
+
+	tests := []struct {
+		name             string
+		upgradeStrategy  apiv1.UpgradeStrategy
+		expectedAwaiting bool
+	}{
+		{name: "PPND waits", upgradeStrategy: apiv1.UpgradeStrategyPPND, expectedAwaiting: true},
+		{name: "Progressive waits", upgradeStrategy: apiv1.UpgradeStrategyProgressive, expectedAwaiting: true},
+		{name: "Recreate waits", upgradeStrategy: apiv1.UpgradeStrategyRecreate, expectedAwaiting: true},
+		{name: "Apply doesn't wait", upgradeStrategy: apiv1.UpgradeStrategyApply, expectedAwaiting: false},
+		{name: "NoOp doesn't wait", upgradeStrategy: apiv1.UpgradeStrategyNoOp, expectedAwaiting: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rollout := createPipelineRollout(pipelineSpec, map[string]string{}, map[string]string{})
+			rollout.Status.MarkDeployed(rollout.Generation)
+
+			awaiting, untilWindow, err := awaitMaintenanceWindow(context.Background(), rollout, outsideWindows, tc.upgradeStrategy)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedAwaiting, awaiting)
+			if tc.expectedAwaiting {
+				assert.True(t, untilWindow > 0)
+				assert.Equal(t, apiv1.PhasePending, rollout.Status.Phase)
+				assert.Equal(t, metav1.ConditionTrue, rollout.Status.GetCondition(apiv1.ConditionAwaitingMaintenanceWindow).Status)
+			} else {
+				assert.Equal(t, apiv1.PhaseDeployed, rollout.Status.Phase)
+				assert.Nil(t, rollout.Status.GetCondition(apiv1.ConditionAwaitingMaintenanceWindow))
+			}
+		})
+	}
+}
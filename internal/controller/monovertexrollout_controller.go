@@ -129,6 +129,18 @@ func (r *MonoVertexRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		}
 	}
 
+	// if asked to roll back to a previous revision, write it back into the spec: the updated spec is then reconciled as usual
+	if monoVertexRollout.DeletionTimestamp.IsZero() {
+		rolledBack, err := rollbackIfRequested(ctx, r.client, r.recorder, monoVertexRollout)
+		if err != nil {
+			r.ErrorHandler(monoVertexRollout, err, "RollbackFailed", "Failed to roll back MonoVertexRollout")
+			return ctrl.Result{}, err
+		}
+		if rolledBack {
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
 	// store copy of original rollout
 	monoVertexRolloutOrig := monoVertexRollout
 	monoVertexRollout = monoVertexRolloutOrig.DeepCopy()
@@ -143,6 +155,7 @@ func (r *MonoVertexRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			r.ErrorHandler(monoVertexRollout, statusUpdateErr, "UpdateStatusFailed", "Failed to update MonoVertexRollout status")
 			return ctrl.Result{}, statusUpdateErr
 		}
+		recordRevision(ctx, r.client, r.recorder, monoVertexRollout)
 		return ctrl.Result{}, err
 	}
 
@@ -163,6 +176,8 @@ func (r *MonoVertexRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	}
 
 	if monoVertexRollout.DeletionTimestamp.IsZero() {
+		recordRevision(ctx, r.client, r.recorder, monoVertexRollout)
+		result = processProgressDeadline(ctx, monoVertexRollout, monoVertexRollout.Spec.ProgressDeadlineSeconds, ControllerMonoVertexRollout, r.recorder, r.customMetrics, result)
 		statusUpdateErr := r.updateMonoVertexRolloutStatus(ctx, monoVertexRollout)
 		if statusUpdateErr != nil {
 			r.ErrorHandler(monoVertexRollout, statusUpdateErr, "StatusUpdateFailed", "Failed to update MonoVertexRollout")
@@ -195,6 +210,9 @@ func (r *MonoVertexRolloutReconciler) reconcile(ctx context.Context, monoVertexR
 	if !monoVertexRollout.DeletionTimestamp.IsZero() {
 		numaLogger.Info("Deleting MonoVertexRollout")
 		if controllerutil.ContainsFinalizer(monoVertexRollout, finalizerName) {
+			if err := GetPauseModule().deletePauseRequest(ctx, GetPauseModule().getMonoVertexRolloutKey(monoVertexRollout.Namespace, monoVertexRollout.Name)); err != nil {
+				return ctrl.Result{}, err
+			}
 			controllerutil.RemoveFinalizer(monoVertexRollout, finalizerName)
 		}
 		// generate metrics for MonoVertex deletion
@@ -208,11 +226,24 @@ func (r *MonoVertexRolloutReconciler) reconcile(ctx context.Context, monoVertexR
 		controllerutil.AddFinalizer(monoVertexRollout, finalizerName)
 	}
 
+	if err := r.updateUserPauseRequest(ctx, monoVertexRollout); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the Numaflow Controller which manages the MonoVertex doesn't change during this reconciliation
+	ctx, err := withResolvedNumaflowControllerInstanceID(ctx, r.client, monoVertexRollout.Namespace, apiv1.MonoVertexRolloutGroupVersionKind.Kind,
+		monoVertexRollout.Name, monoVertexRollout.Spec.MonoVertex.Annotations)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	newMonoVertexDef, err := r.makeRunningMonoVertexDefinition(ctx, monoVertexRollout)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
+	result := ctrl.Result{}
+
 	existingMonoVertexDef, err := kubernetes.GetResource(ctx, r.client, newMonoVertexDef.GroupVersionKind(),
 		k8stypes.NamespacedName{Namespace: newMonoVertexDef.Namespace, Name: newMonoVertexDef.Name})
 	if err != nil {
@@ -236,7 +267,8 @@ func (r *MonoVertexRolloutReconciler) reconcile(ctx context.Context, monoVertexR
 		if err != nil {
 			return ctrl.Result{}, err
 		}
-		if err := r.processExistingMonoVertex(ctx, monoVertexRollout, existingMonoVertexDef, newMonoVertexDef, syncStartTime); err != nil {
+		result, err = r.processExistingMonoVertex(ctx, monoVertexRollout, existingMonoVertexDef, newMonoVertexDef, syncStartTime)
+		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("error processing existing MonoVertex: %v", err)
 		}
 	}
@@ -244,12 +276,14 @@ func (r *MonoVertexRolloutReconciler) reconcile(ctx context.Context, monoVertexR
 	// process status
 	r.processMonoVertexStatus(ctx, existingMonoVertexDef, monoVertexRollout)
 
-	return ctrl.Result{}, nil
+	return result, nil
 
 }
 
+// process an existing MonoVertex
+// return the result indicating whether and when to requeue, and error if any
 func (r *MonoVertexRolloutReconciler) processExistingMonoVertex(ctx context.Context, monoVertexRollout *apiv1.MonoVertexRollout,
-	existingMonoVertexDef, newMonoVertexDef *kubernetes.GenericObject, syncStartTime time.Time) error {
+	existingMonoVertexDef, newMonoVertexDef *kubernetes.GenericObject, syncStartTime time.Time) (ctrl.Result, error) {
 
 	numaLogger := logger.FromContext(ctx)
 
@@ -257,9 +291,15 @@ func (r *MonoVertexRolloutReconciler) processExistingMonoVertex(ctx context.Cont
 	// if it's a simple change, direct apply
 	// if not and if user-preferred strategy is "Progressive", it will require Progressive rollout to perform the update with guaranteed no-downtime
 	// and capability to rollback an unhealthy one
-	mvNeedsToUpdate, upgradeStrategyType, err := usde.ResourceNeedsUpdating(ctx, newMonoVertexDef, existingMonoVertexDef)
+	userPreferredStrategy, err := usde.GetUserStrategy(ctx, newMonoVertexDef.Namespace, monoVertexRollout.GetUserUpgradeStrategy())
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
+	}
+	monoVertexRollout.Status.UserStrategy = apiv1.UserUpgradeStrategy(userPreferredStrategy)
+
+	mvNeedsToUpdate, upgradeStrategyType, err := usde.ResourceNeedsUpdating(ctx, newMonoVertexDef, existingMonoVertexDef, userPreferredStrategy)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 	numaLogger.
 		WithValues("mvNeedsToUpdate", mvNeedsToUpdate, "upgradeStrategyType", upgradeStrategyType).
@@ -279,30 +319,67 @@ func (r *MonoVertexRolloutReconciler) processExistingMonoVertex(ctx context.Cont
 	inProgressStrategySet := (inProgressStrategy != apiv1.UpgradeStrategyNoOp)
 
 	// if not, should we set one?
+	awaitingWindow := false
+	var untilWindow time.Duration
 	if !inProgressStrategySet {
-		if upgradeStrategyType == apiv1.UpgradeStrategyProgressive {
-			inProgressStrategy = apiv1.UpgradeStrategyProgressive
-			r.inProgressStrategyMgr.setStrategy(ctx, monoVertexRollout, inProgressStrategy)
+		// an update requiring PPND, Progressive or Recreate can only start during a maintenance window
+		awaitingWindow, untilWindow, err = awaitMaintenanceWindow(ctx, monoVertexRollout, monoVertexRollout.GetMaintenanceWindows(), upgradeStrategyType)
+		if err != nil {
+			return ctrl.Result{}, err
 		}
+
+		if upgradeStrategyType == apiv1.UpgradeStrategyProgressive && !awaitingWindow {
+			// don't retry a spec which already failed and was rolled back
+			previouslyFailed, err := progressiveUpgradePreviouslyFailed(ctx, monoVertexRollout, r)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if previouslyFailed {
+				numaLogger.Debug("Progressive upgrade of this spec previously failed, not retrying")
+				monoVertexRollout.Status.MarkProgressiveUpgradeFailed("Upgrade to this spec previously failed and was rolled back; update the spec to retry", monoVertexRollout.Generation)
+			} else {
+				inProgressStrategy = apiv1.UpgradeStrategyProgressive
+				r.inProgressStrategyMgr.setStrategy(ctx, monoVertexRollout, inProgressStrategy)
+			}
+		}
+	}
+	if awaitingWindow {
+		// come back when the maintenance window opens
+		return ctrl.Result{RequeueAfter: untilWindow}, nil
 	}
+
+	requeue := false
 	switch inProgressStrategy {
 	case apiv1.UpgradeStrategyProgressive:
-		if mvNeedsToUpdate {
+		// once started, a Progressive upgrade runs to completion even if the promoted child no longer needs updating
+		if mvNeedsToUpdate || progressiveUpgradeStarted(monoVertexRollout) {
 			numaLogger.Debug("processing MonoVertex with Progressive")
 			done, err := processResourceWithProgressive(ctx, monoVertexRollout, existingMonoVertexDef, r, r.client)
 			if err != nil {
-				return err
+				return ctrl.Result{}, err
 			}
 			if done {
 				r.inProgressStrategyMgr.unsetStrategy(ctx, monoVertexRollout)
+			} else {
+				// the upgrading MonoVertex may be under Analysis, which needs to be checked periodically
+				requeue = true
 			}
 		}
 
 	default:
+		if mvNeedsToUpdate && upgradeStrategyType == apiv1.UpgradeStrategyRecreate {
+			// the MonoVertex will be created again with the new spec once it's gone
+			numaLogger.Infof("deleting MonoVertex %s/%s in order to recreate it", existingMonoVertexDef.Namespace, existingMonoVertexDef.Name)
+			if err := kubernetes.DeleteResource(ctx, r.client, existingMonoVertexDef); err != nil {
+				return ctrl.Result{}, err
+			}
+			monoVertexRollout.Status.MarkWaitingFor("MonoVertex %s to be deleted so that it can be recreated", existingMonoVertexDef.Name)
+			return common.DefaultDelayedRequeue, nil
+		}
 		if mvNeedsToUpdate {
 			err := r.updateMonoVertex(ctx, monoVertexRollout, newMonoVertexDef)
 			if err != nil {
-				return err
+				return ctrl.Result{}, err
 			}
 			r.customMetrics.ReconciliationDuration.WithLabelValues(ControllerMonoVertexRollout, "update").Observe(time.Since(syncStartTime).Seconds())
 		}
@@ -310,10 +387,13 @@ func (r *MonoVertexRolloutReconciler) processExistingMonoVertex(ctx context.Cont
 	// clean up recyclable monovertices
 	err = garbageCollectChildren(ctx, monoVertexRollout, r, r.client)
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
 
-	return nil
+	if requeue {
+		return common.DefaultDelayedRequeue, nil
+	}
+	return ctrl.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -326,7 +406,7 @@ func (r *MonoVertexRolloutReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	// Watch MonoVertexRollouts
 	if err := controller.Watch(source.Kind(mgr.GetCache(), &apiv1.MonoVertexRollout{},
-		&handler.TypedEnqueueRequestForObject[*apiv1.MonoVertexRollout]{}, predicate.TypedGenerationChangedPredicate[*apiv1.MonoVertexRollout]{})); err != nil {
+		&handler.TypedEnqueueRequestForObject[*apiv1.MonoVertexRollout]{}, rolloutChangedPredicate[*apiv1.MonoVertexRollout]())); err != nil {
 		return fmt.Errorf("failed to watch MonoVertexRollouts: %w", err)
 	}
 
@@ -452,6 +532,25 @@ func (r *MonoVertexRolloutReconciler) setChildResourcesPauseCondition(rollout *a
 
 }
 
+// register the MonoVertexRollout's spec.paused with the PauseModule as a Pause Request for its MonoVertex, and report
+// the active Pause Requests in the Status
+func (r *MonoVertexRolloutReconciler) updateUserPauseRequest(ctx context.Context, monoVertexRollout *apiv1.MonoVertexRollout) error {
+	pm := GetPauseModule()
+	requester := pm.getMonoVertexRolloutKey(monoVertexRollout.Namespace, monoVertexRollout.Name)
+	updated, err := pm.updatePauseRequest(ctx, requester, monoVertexRollout.Spec.Paused)
+	if err != nil {
+		return err
+	}
+	if updated {
+		logger.FromContext(ctx).Infof("user pause request set to %t", monoVertexRollout.Spec.Paused)
+	}
+
+	monoVertexRolloutKey := k8stypes.NamespacedName{Namespace: monoVertexRollout.Namespace, Name: monoVertexRollout.Name}
+	monoVertexRollout.Status.PauseRequests = pm.getActivePauseRequests(monoVertexRolloutKey,
+		[]namedPauseRequester{{key: requester, name: userPauseRequester}}, monoVertexRollout.Status.PauseRequests)
+	return nil
+}
+
 func (r *MonoVertexRolloutReconciler) needsUpdate(old, new *apiv1.MonoVertexRollout) bool {
 	if old == nil {
 		return true
@@ -528,14 +627,28 @@ func (r *MonoVertexRolloutReconciler) makeRunningMonoVertexDefinition(
 	}
 	metadata.Labels[common.LabelKeyUpgradeState] = string(common.LabelValueUpgradePromoted)
 
-	return r.makeMonoVertexDefinition(monoVertexRollout, monoVertexName, metadata)
+	monoVertexDef, err := r.makeMonoVertexDefinition(ctx, monoVertexRollout, monoVertexName, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if monoVertexRollout.Spec.Paused {
+		if err := withDesiredPhase(monoVertexDef, string(numaflowv1.MonoVertexPhasePaused)); err != nil {
+			return nil, err
+		}
+	}
+	return monoVertexDef, nil
 }
 
 func (r *MonoVertexRolloutReconciler) makeMonoVertexDefinition(
+	ctx context.Context,
 	monoVertexRollout *apiv1.MonoVertexRollout,
 	monoVertexName string,
 	metadata apiv1.Metadata,
 ) (*kubernetes.GenericObject, error) {
+	annotations, err := withNumaflowControllerInstanceID(ctx, r.client, monoVertexRollout.Namespace, apiv1.MonoVertexRolloutGroupVersionKind.Kind, monoVertexRollout.Name, metadata.Annotations)
+	if err != nil {
+		return nil, err
+	}
 
 	return &kubernetes.GenericObject{
 		TypeMeta: metav1.TypeMeta{
@@ -546,7 +659,7 @@ func (r *MonoVertexRolloutReconciler) makeMonoVertexDefinition(
 			Name:            monoVertexName,
 			Namespace:       monoVertexRollout.Namespace,
 			Labels:          metadata.Labels,
-			Annotations:     metadata.Annotations,
+			Annotations:     annotations,
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(monoVertexRollout.GetObjectMeta(), apiv1.MonoVertexRolloutGroupVersionKind)},
 		},
 		Spec: monoVertexRollout.Spec.MonoVertex.Spec,
@@ -573,13 +686,13 @@ func (r *MonoVertexRolloutReconciler) listChildren(ctx context.Context, rolloutO
 		monoVertexRollout.Namespace, labelSelector, fieldSelector)
 }
 
-func (r *MonoVertexRolloutReconciler) createBaseChildDefinition(rolloutObject RolloutObject, name string) (*kubernetes.GenericObject, error) {
+func (r *MonoVertexRolloutReconciler) createBaseChildDefinition(ctx context.Context, rolloutObject RolloutObject, name string) (*kubernetes.GenericObject, error) {
 	monoVertexRollout := rolloutObject.(*apiv1.MonoVertexRollout)
 	metadata, err := getBaseMonoVertexMetadata(monoVertexRollout)
 	if err != nil {
 		return nil, err
 	}
-	return r.makeMonoVertexDefinition(monoVertexRollout, name, metadata)
+	return r.makeMonoVertexDefinition(ctx, monoVertexRollout, name, metadata)
 }
 
 func (r *MonoVertexRolloutReconciler) getCurrentChildCount(rolloutObject RolloutObject) (int32, bool) {
@@ -646,3 +759,41 @@ func (r *MonoVertexRolloutReconciler) childNeedsUpdating(ctx context.Context, a
 
 	return !reflect.DeepEqual(mvWithoutDesiredPhaseA, mvWithoutDesiredPhaseB), nil
 }
+
+// the following functions enable MonoVertexRolloutReconciler to implement canaryController interface
+func (r *MonoVertexRolloutReconciler) canarySteps(rolloutObject RolloutObject) []apiv1.CanaryStep {
+	monoVertexRollout := rolloutObject.(*apiv1.MonoVertexRollout)
+	return monoVertexRollout.GetCanarySteps()
+}
+
+func (r *MonoVertexRolloutReconciler) getReplicas(monoVertexDef *kubernetes.GenericObject) (int32, error) {
+	unstruc, err := kubernetes.ObjectToUnstructured(monoVertexDef)
+	if err != nil {
+		return 0, err
+	}
+	replicas, found, err := unstructured.NestedFloat64(unstruc.Object, "spec", "replicas")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get replicas from MonoVertex: %w", err)
+	}
+	if !found {
+		// Numaflow's default
+		return 1, nil
+	}
+	return int32(replicas), nil
+}
+
+func (r *MonoVertexRolloutReconciler) withReplicas(monoVertexDef *kubernetes.GenericObject, replicas int32) (*kubernetes.GenericObject, error) {
+	unstruc, err := kubernetes.ObjectToUnstructured(monoVertexDef)
+	if err != nil {
+		return nil, err
+	}
+	if err = unstructured.SetNestedField(unstruc.Object, int64(replicas), "spec", "replicas"); err != nil {
+		return nil, fmt.Errorf("failed to set replicas in MonoVertex: %w", err)
+	}
+	return kubernetes.UnstructuredToObject(unstruc)
+}
+
+func (r *MonoVertexRolloutReconciler) scale(ctx context.Context, monoVertexDef *kubernetes.GenericObject, replicas int32) error {
+	patchJson := fmt.Sprintf(`{"spec": {"replicas": %d}}`, replicas)
+	return kubernetes.PatchResource(ctx, r.client, monoVertexDef, patchJson, k8stypes.MergePatchType)
+}
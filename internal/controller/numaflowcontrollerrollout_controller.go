@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
@@ -36,6 +37,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	yamlserializer "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
 	k8stypes "k8s.io/apimachinery/pkg/types"
@@ -48,6 +50,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 	sigsyaml "sigs.k8s.io/yaml"
 
@@ -142,6 +145,18 @@ func (r *NumaflowControllerRolloutReconciler) Reconcile(ctx context.Context, req
 		}
 	}
 
+	// if asked to roll back to a previous revision, write it back into the spec: the updated spec is then reconciled as usual
+	if numaflowControllerRollout.DeletionTimestamp.IsZero() {
+		rolledBack, err := rollbackIfRequested(ctx, r.client, r.recorder, numaflowControllerRollout)
+		if err != nil {
+			r.ErrorHandler(numaflowControllerRollout, err, "RollbackFailed", "Failed to roll back numaflow controller rollout")
+			return ctrl.Result{}, err
+		}
+		if rolledBack {
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
 	// save off a copy of the original before we modify it
 	numaflowControllerRolloutOrig := numaflowControllerRollout
 	numaflowControllerRollout = numaflowControllerRolloutOrig.DeepCopy()
@@ -156,6 +171,7 @@ func (r *NumaflowControllerRolloutReconciler) Reconcile(ctx context.Context, req
 			r.ErrorHandler(numaflowControllerRollout, statusUpdateErr, "UpdateStatusFailed", "Failed to update status of numaflow controller rollout")
 			return ctrl.Result{}, statusUpdateErr
 		}
+		recordRevision(ctx, r.client, r.recorder, numaflowControllerRollout)
 		return ctrl.Result{}, err
 	}
 
@@ -190,6 +206,8 @@ func (r *NumaflowControllerRolloutReconciler) Reconcile(ctx context.Context, req
 
 	// Update the Status subresource
 	if numaflowControllerRollout.DeletionTimestamp.IsZero() { // would've already been deleted
+		recordRevision(ctx, r.client, r.recorder, numaflowControllerRollout)
+		result = processProgressDeadline(ctx, numaflowControllerRollout, numaflowControllerRollout.Spec.ProgressDeadlineSeconds, ControllerNumaflowControllerRollout, r.recorder, r.customMetrics, result)
 		statusUpdateErr := r.updateNumaflowControllerRolloutStatus(ctx, numaflowControllerRollout)
 		if statusUpdateErr != nil {
 			r.ErrorHandler(numaflowControllerRollout, statusUpdateErr, "UpdateStatusFailed", "Failed to update status of numaflow controller rollout")
@@ -235,13 +253,16 @@ func (r *NumaflowControllerRolloutReconciler) reconcile(
 		}
 	}()
 
-	controllerKey := GetPauseModule().getNumaflowControllerKey(namespace)
+	controllerKey := getNumaflowControllerRolloutKey(controllerRollout)
 
 	if !controllerRollout.DeletionTimestamp.IsZero() {
 		numaLogger.Info("Deleting NumaflowControllerRollout")
 		r.recorder.Eventf(controllerRollout, corev1.EventTypeNormal, "Deleting", "Deleting NumaflowControllerRollout")
 		if controllerutil.ContainsFinalizer(controllerRollout, finalizerName) {
-			GetPauseModule().deletePauseRequest(controllerKey)
+			if err := GetPauseModule().deletePauseRequest(ctx, controllerKey); err != nil {
+				return ctrl.Result{}, err
+			}
+			GetPauseModule().deleteClusterScopedController(k8stypes.NamespacedName{Namespace: controllerRollout.Namespace, Name: controllerRollout.Name})
 			controllerutil.RemoveFinalizer(controllerRollout, finalizerName)
 		}
 		// generate the metrics for the numaflow controller deletion based on a numaflow version.
@@ -256,24 +277,58 @@ func (r *NumaflowControllerRolloutReconciler) reconcile(
 		controllerutil.AddFinalizer(controllerRollout, finalizerName)
 	}
 
+	// a cluster-scoped Numaflow Controller pauses the Pipelines in all of the namespaces it manages
+	if err := r.updateManagedNamespaces(ctx, controllerRollout); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	_, pauseRequestExists := GetPauseModule().getPauseRequest(controllerKey)
 	if !pauseRequestExists {
 		// this is just creating an entry in the map if it doesn't already exist
 		GetPauseModule().newPauseRequest(controllerKey)
 	}
 
+	// changing the InstanceID would leave the Pipelines still using the existing one unmanaged
+	if err := checkNumaflowControllerGuardrails(ctx, r.client, controllerRollout); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	deployment, deploymentExists, err := r.getNumaflowControllerDeployment(ctx, controllerRollout)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
 	// determine the Upgrade Strategy user prefers
-	upgradeStrategy, err := usde.GetUserStrategy(ctx, controllerRollout.Namespace)
+	upgradeStrategy, err := usde.GetUserStrategy(ctx, controllerRollout.Namespace, "")
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	if deploymentExists && upgradeStrategy == config.PPNDStrategyID {
+	// an update which pauses the Pipelines or moves them to a new Numaflow Controller can only start during a maintenance window
+	if deploymentExists {
+		awaitingWindow, untilWindow, err := r.awaitMaintenanceWindow(ctx, controllerRollout, controllerKey, deployment, upgradeStrategy)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if awaitingWindow {
+			// come back when the maintenance window opens
+			return ctrl.Result{RequeueAfter: untilWindow}, nil
+		}
+	}
+
+	// (an upgrade which has started with the Progressive strategy runs to completion even if the strategy changes)
+	progressiveUpgradeInProgress := controllerRollout.Status.ProgressiveStatus.UpgradingInstanceID != ""
+	if deploymentExists && !controllerRollout.Spec.ClusterScoped && (upgradeStrategy == config.ProgressiveStrategyID || progressiveUpgradeInProgress) {
+		done, err := r.processWithProgressive(ctx, controllerRollout, namespace, deployment)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			return common.DefaultDelayedRequeue, nil
+		}
+		r.customMetrics.ReconciliationDuration.WithLabelValues(ControllerNumaflowControllerRollout, "update").Observe(time.Since(syncStartTime).Seconds())
+
+	} else if deploymentExists && upgradeStrategy == config.PPNDStrategyID {
 		numaLogger.Debugf("found existing numaflow-controller Deployment")
 
 		// if I need to update or am in the middle of an update of the Controller Deployment, then I need to make sure all the Pipelines are pausing
@@ -296,7 +351,7 @@ func (r *NumaflowControllerRolloutReconciler) reconcile(
 		done, err := processChildObjectWithPPND(ctx, r.client, controllerRollout, r, controllerDeploymentNeedsUpdating,
 			controllerDeploymentIsUpdating, func() error {
 				r.recorder.Eventf(controllerRollout, corev1.EventTypeNormal, "AllPipelinesPaused", "All Pipelines have paused so Numaflow Controller can safely update")
-				phase, err := r.sync(controllerRollout, namespace, numaLogger)
+				phase, err := r.sync(controllerRollout, namespace, []apiv1.Controller{getPromotedController(controllerRollout)}, numaLogger)
 				if err != nil {
 					return err
 				}
@@ -321,7 +376,8 @@ func (r *NumaflowControllerRolloutReconciler) reconcile(
 	// - new ControllerRollout
 	// - auto healing
 	// - somebody changed the manifest associated with the Controller version (shouldn't happen but could)
-	phase, err := r.sync(controllerRollout, namespace, numaLogger)
+	// - a Progressive upgrade just finished, so the previously promoted Controller is removed
+	phase, err := r.sync(controllerRollout, namespace, []apiv1.Controller{getPromotedController(controllerRollout)}, numaLogger)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -338,17 +394,132 @@ func (r *NumaflowControllerRolloutReconciler) reconcile(
 	return ctrl.Result{}, nil
 }
 
+// determine whether an update of the Numaflow Controller must wait for a maintenance window: only an update which hasn't
+// started yet is held, so that one which has started (pausing the Pipelines or moving them to a new Numaflow Controller)
+// runs to completion
+// return whether to wait, how long until the next window opens, and error if any
+func (r *NumaflowControllerRolloutReconciler) awaitMaintenanceWindow(
+	ctx context.Context,
+	controllerRollout *apiv1.NumaflowControllerRollout,
+	controllerKey string,
+	deployment *appsv1.Deployment,
+	userStrategy config.USDEUserStrategy,
+) (bool, time.Duration, error) {
+	upgradeStrategy := apiv1.UpgradeStrategyNoOp
+	if controllerRollout.Status.ProgressiveStatus.UpgradingInstanceID == "" {
+		if !controllerRollout.Spec.ClusterScoped && userStrategy == config.ProgressiveStrategyID {
+			// (a version whose Progressive upgrade failed isn't attempted again)
+			if controllerRollout.Status.ProgressiveStatus.FailedVersion != controllerRollout.Spec.Controller.Version {
+				upgradeStrategy = apiv1.UpgradeStrategyProgressive
+			}
+		} else if userStrategy == config.PPNDStrategyID {
+			if pauseRequested, _ := GetPauseModule().getPauseRequest(controllerKey); pauseRequested == nil || !*pauseRequested {
+				upgradeStrategy = apiv1.UpgradeStrategyPPND
+			}
+		}
+	}
+	if upgradeStrategy != apiv1.UpgradeStrategyNoOp {
+		needsUpdating, isUpdating, err := r.isControllerDeploymentUpdating(ctx, controllerRollout, deployment)
+		if err != nil {
+			return false, 0, err
+		}
+		if !needsUpdating || isUpdating {
+			upgradeStrategy = apiv1.UpgradeStrategyNoOp
+		}
+	}
+	return awaitMaintenanceWindow(ctx, controllerRollout, controllerRollout.GetMaintenanceWindows(), upgradeStrategy)
+}
+
 // for the purpose of logging
 func (r *NumaflowControllerRolloutReconciler) getChildTypeString() string {
 	return "Numaflow Controller"
 }
 
-func (r *NumaflowControllerRolloutReconciler) getPipelineList(ctx context.Context, rolloutNamespace string, _ string) ([]*kubernetes.GenericObject, error) {
-	return kubernetes.ListLiveResource(ctx, common.NumaflowAPIGroup, common.NumaflowAPIVersion, "pipelines", rolloutNamespace, common.LabelKeyParentRollout, "")
+func (r *NumaflowControllerRolloutReconciler) getPipelineList(ctx context.Context, rolloutNamespace string, rolloutName string) ([]*kubernetes.GenericObject, error) {
+	controller, clusterScoped := GetPauseModule().getClusterScopedController(k8stypes.NamespacedName{Namespace: rolloutNamespace, Name: rolloutName})
+	if !clusterScoped {
+		return kubernetes.ListLiveResource(ctx, common.NumaflowAPIGroup, common.NumaflowAPIVersion, "pipelines", rolloutNamespace, common.LabelKeyParentRollout, "")
+	}
+
+	allPipelines, err := kubernetes.ListLiveResource(ctx, common.NumaflowAPIGroup, common.NumaflowAPIVersion, "pipelines", "", common.LabelKeyParentRollout, "")
+	if err != nil {
+		return nil, err
+	}
+	pipelines := make([]*kubernetes.GenericObject, 0, len(allPipelines))
+	for _, pipeline := range allPipelines {
+		if _, managed := controller.namespaces[pipeline.Namespace]; managed && pipeline.Annotations[common.AnnotationKeyNumaflowInstanceID] == controller.instanceID {
+			pipelines = append(pipelines, pipeline)
+		}
+	}
+	return pipelines, nil
 }
 
 func (r *NumaflowControllerRolloutReconciler) getRolloutKey(rolloutNamespace string, rolloutName string) string {
-	return GetPauseModule().getNumaflowControllerKey(rolloutNamespace)
+	pm := GetPauseModule()
+	if controller, clusterScoped := pm.getClusterScopedController(k8stypes.NamespacedName{Namespace: rolloutNamespace, Name: rolloutName}); clusterScoped {
+		return pm.getClusterScopedNumaflowControllerKey(controller.instanceID)
+	}
+	return pm.getNumaflowControllerKey(rolloutNamespace)
+}
+
+// get the key of the NumaflowControllerRollout's Pause Request based on its spec
+func getNumaflowControllerRolloutKey(controllerRollout *apiv1.NumaflowControllerRollout) string {
+	if controllerRollout.Spec.ClusterScoped {
+		return GetPauseModule().getClusterScopedNumaflowControllerKey(controllerRollout.Spec.Controller.InstanceID)
+	}
+	return GetPauseModule().getNumaflowControllerKey(controllerRollout.Namespace)
+}
+
+// determine the namespaces selected by a cluster-scoped NumaflowControllerRollout and register them with the PauseModule,
+// so that its Pipelines across those namespaces are paused for its updates
+func (r *NumaflowControllerRolloutReconciler) updateManagedNamespaces(ctx context.Context, controllerRollout *apiv1.NumaflowControllerRollout) error {
+	numaLogger := logger.FromContext(ctx)
+	pm := GetPauseModule()
+	controllerRolloutName := k8stypes.NamespacedName{Namespace: controllerRollout.Namespace, Name: controllerRollout.Name}
+
+	// if a cluster-scoped Pause Request changes key (i.e. switching to namespace scope, or changing InstanceID), remove the
+	// one under the previous key
+	if controller, registered := pm.getClusterScopedController(controllerRolloutName); registered {
+		previousKey := pm.getClusterScopedNumaflowControllerKey(controller.instanceID)
+		if previousKey != getNumaflowControllerRolloutKey(controllerRollout) {
+			numaLogger.Infof("removing pause request %q which no longer applies", previousKey)
+			if err := pm.deletePauseRequest(ctx, previousKey); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !controllerRollout.Spec.ClusterScoped {
+		pm.deleteClusterScopedController(controllerRolloutName)
+		controllerRollout.Status.ManagedNamespaces = nil
+		return nil
+	}
+
+	selector := labels.Everything()
+	if controllerRollout.Spec.NamespaceSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(controllerRollout.Spec.NamespaceSelector)
+		if err != nil {
+			return fmt.Errorf("invalid namespaceSelector: %w", err)
+		}
+	}
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.client.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	namespaces := make(map[string]struct{}, len(namespaceList.Items))
+	managedNamespaces := make([]string, 0, len(namespaceList.Items))
+	for _, namespace := range namespaceList.Items {
+		namespaces[namespace.Name] = struct{}{}
+		managedNamespaces = append(managedNamespaces, namespace.Name)
+	}
+	sort.Strings(managedNamespaces)
+	numaLogger.Debugf("cluster-scoped Numaflow Controller manages namespaces %v", managedNamespaces)
+
+	pm.setClusterScopedController(controllerRolloutName, controllerRollout.Spec.Controller.InstanceID, namespaces)
+	controllerRollout.Status.ManagedNamespaces = managedNamespaces
+	return nil
 }
 
 // determine if it needs to update or is already in the middle of an update (waiting for Reconciliation)
@@ -453,8 +624,8 @@ func ownerExists(existingRefs []interface{}, ownerRef map[string]interface{}) bo
 	return alreadyExists
 }
 
-func resolveManifestTemplate(manifest string, rollout *apiv1.NumaflowControllerRollout) ([]byte, error) {
-	if rollout == nil {
+func resolveManifestTemplate(manifest string, controller *apiv1.Controller) ([]byte, error) {
+	if controller == nil {
 		return []byte(manifest), nil
 	}
 
@@ -463,7 +634,7 @@ func resolveManifestTemplate(manifest string, rollout *apiv1.NumaflowControllerR
 		return nil, fmt.Errorf("unable to parse manifest: %v", err)
 	}
 
-	instanceID := rollout.Spec.Controller.InstanceID
+	instanceID := controller.InstanceID
 	instanceSuffix := ""
 	if strings.TrimSpace(instanceID) != "" {
 		instanceSuffix = fmt.Sprintf("-%s", instanceID)
@@ -486,41 +657,33 @@ func resolveManifestTemplate(manifest string, rollout *apiv1.NumaflowControllerR
 	return buf.Bytes(), nil
 }
 
+// sync the Numaflow Controllers with the given versions and InstanceIDs (normally just the promoted one), removing any
+// other resources previously applied for the NumaflowControllerRollout
 func (r *NumaflowControllerRolloutReconciler) sync(
 	rollout *apiv1.NumaflowControllerRollout,
 	namespace string,
+	controllers []apiv1.Controller,
 	numaLogger *logger.NumaLogger,
 ) (gitopsSyncCommon.OperationPhase, error) {
 
-	// Get the target manifests based on the version of the controller and throw an error if the definition not for a version.
-	version := rollout.Spec.Controller.Version
-	definition := config.GetConfigManagerInstance().GetControllerDefinitionsMgr().GetNumaflowControllerDefinitionsConfig()
-	manifest := definition[version]
-	if len(manifest) == 0 {
-		return gitopsSyncCommon.OperationError, fmt.Errorf("no controller definition found for version %s", version)
-	}
-
-	// Update templated manifest with information from the rollout
-	manifestBytes, err := resolveManifestTemplate(manifest, rollout)
-	if err != nil {
-		return gitopsSyncCommon.OperationError, fmt.Errorf("unable to resolve manifest: %v", err)
-	}
-
-	// Applying ownership reference
-	manifests, err := SplitYAMLToString(manifestBytes)
-	if err != nil {
-		return gitopsSyncCommon.OperationError, fmt.Errorf("can not parse file data, err: %v", err)
-	}
-	manifestsWithOwnership, err := applyOwnershipToManifests(manifests, rollout)
-	if err != nil {
-		return gitopsSyncCommon.OperationError, fmt.Errorf("failed to apply ownership reference, %w", err)
-	}
-
-	targetObjs, err := toUnstructuredAndApplyLabel(manifestsWithOwnership, rollout.Name)
-	if err != nil {
-		return gitopsSyncCommon.OperationError, fmt.Errorf("failed to parse the manifest, %w", err)
+	targetObjs := []*unstructured.Unstructured{}
+	targetObjKeys := map[kubeUtil.ResourceKey]struct{}{}
+	for _, controller := range controllers {
+		controllerObjs, err := getControllerTargetObjects(rollout, controller, numaLogger)
+		if err != nil {
+			return gitopsSyncCommon.OperationError, err
+		}
+		// Numaflow Controllers running side by side need their resources to be named by InstanceID
+		for _, obj := range controllerObjs {
+			key := kubeUtil.GetResourceKey(obj)
+			if _, found := targetObjKeys[key]; found {
+				return gitopsSyncCommon.OperationError, fmt.Errorf("the Numaflow Controller version %s with InstanceID %q conflicts with another Numaflow Controller on %s %q; its manifest needs to use {{ .InstanceSuffix }}",
+					controller.Version, controller.InstanceID, key.Kind, key.Name)
+			}
+			targetObjKeys[key] = struct{}{}
+		}
+		targetObjs = append(targetObjs, controllerObjs...)
 	}
-	numaLogger.Debugf("found %d target objects associated with Numaflow Controller version %s; versions defined:%+v", len(targetObjs), version, definition)
 
 	reconciliationResult, diffResults, err := r.compareState(rollout, namespace, targetObjs, numaLogger)
 	if err != nil {
@@ -567,6 +730,40 @@ func (r *NumaflowControllerRolloutReconciler) sync(
 	return phase, nil
 }
 
+// get the resources of the Numaflow Controller with the given version and InstanceID
+func getControllerTargetObjects(rollout *apiv1.NumaflowControllerRollout, controller apiv1.Controller, numaLogger *logger.NumaLogger) ([]*unstructured.Unstructured, error) {
+	// Get the target manifests based on the version of the controller and throw an error if the definition not for a version.
+	version := controller.Version
+	definition := config.GetConfigManagerInstance().GetControllerDefinitionsMgr().GetNumaflowControllerDefinitionsConfig()
+	manifest := definition[version]
+	if len(manifest) == 0 {
+		return nil, fmt.Errorf("no controller definition found for version %s", version)
+	}
+
+	// Update templated manifest with information from the rollout
+	manifestBytes, err := resolveManifestTemplate(manifest, &controller)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve manifest: %v", err)
+	}
+
+	// Applying ownership reference
+	manifests, err := SplitYAMLToString(manifestBytes)
+	if err != nil {
+		return nil, fmt.Errorf("can not parse file data, err: %v", err)
+	}
+	manifestsWithOwnership, err := applyOwnershipToManifests(manifests, rollout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply ownership reference, %w", err)
+	}
+
+	targetObjs, err := toUnstructuredAndApplyLabel(manifestsWithOwnership, rollout.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest, %w", err)
+	}
+	numaLogger.Debugf("found %d target objects associated with Numaflow Controller version %s; versions defined:%+v", len(targetObjs), version, definition)
+	return targetObjs, nil
+}
+
 // compareState compares with desired state of the objects with the live state in the cluster
 // for the target objects.
 func (r *NumaflowControllerRolloutReconciler) compareState(
@@ -646,15 +843,20 @@ func getDeploymentCondition(status appsv1.DeploymentStatus, condType appsv1.Depl
 // - the Deployment, if it exists
 // - whether it exists
 // - error if any
+// (this is the promoted Numaflow Controller's Deployment)
 func (r *NumaflowControllerRolloutReconciler) getNumaflowControllerDeployment(ctx context.Context, controllerRollout *apiv1.NumaflowControllerRollout) (*appsv1.Deployment, bool, error) {
-	instanceID := controllerRollout.Spec.Controller.InstanceID
+	return r.getNumaflowControllerDeploymentForInstance(ctx, controllerRollout.Namespace, controllerRollout.GetPromotedInstanceID())
+}
+
+// get the Deployment of the Numaflow Controller with this InstanceID, and whether it exists
+func (r *NumaflowControllerRolloutReconciler) getNumaflowControllerDeploymentForInstance(ctx context.Context, namespace string, instanceID string) (*appsv1.Deployment, bool, error) {
 	numaflowControllerDeploymentName := NumaflowControllerDeploymentName
 	if strings.TrimSpace(instanceID) != "" {
 		numaflowControllerDeploymentName = fmt.Sprintf("%s-%s", NumaflowControllerDeploymentName, instanceID)
 	}
 
 	deployment := &appsv1.Deployment{}
-	if err := r.client.Get(ctx, k8stypes.NamespacedName{Namespace: controllerRollout.Namespace, Name: numaflowControllerDeploymentName}, deployment); err != nil {
+	if err := r.client.Get(ctx, k8stypes.NamespacedName{Namespace: namespace, Name: numaflowControllerDeploymentName}, deployment); err != nil {
 		if apierrors.IsNotFound(err) {
 			return nil, false, nil
 		} else {
@@ -790,6 +992,11 @@ func (r *NumaflowControllerRolloutReconciler) updatePauseMetric(controllerRollou
 	r.customMetrics.NumaflowControllerPausedSeconds.WithLabelValues(controllerRollout.Name).Set(timeElapsed.Seconds())
 }
 
+func (r *NumaflowControllerRolloutReconciler) recordPauseTimeout(rollout client.Object, policy apiv1.PauseTimeoutPolicy, message string) {
+	r.recorder.Event(rollout, corev1.EventTypeWarning, "PauseTimedOut", message)
+	r.customMetrics.PauseTimeouts.WithLabelValues(ControllerNumaflowControllerRollout, rollout.GetNamespace(), rollout.GetName(), string(policy)).Inc()
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *NumaflowControllerRolloutReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	controller, err := runtimecontroller.New(ControllerNumaflowControllerRollout, mgr, runtimecontroller.Options{Reconciler: r})
@@ -799,7 +1006,7 @@ func (r *NumaflowControllerRolloutReconciler) SetupWithManager(mgr ctrl.Manager)
 
 	// Watch for changes to primary resource NumaflowControllerRollout
 	if err := controller.Watch(source.Kind(mgr.GetCache(), &apiv1.NumaflowControllerRollout{},
-		&handler.TypedEnqueueRequestForObject[*apiv1.NumaflowControllerRollout]{}, predicate.TypedGenerationChangedPredicate[*apiv1.NumaflowControllerRollout]{})); err != nil {
+		&handler.TypedEnqueueRequestForObject[*apiv1.NumaflowControllerRollout]{}, rolloutChangedPredicate[*apiv1.NumaflowControllerRollout]())); err != nil {
 		return fmt.Errorf("failed to watch NumaflowControllerRollout: %w", err)
 	}
 
@@ -838,9 +1045,31 @@ func (r *NumaflowControllerRolloutReconciler) SetupWithManager(mgr ctrl.Manager)
 		return fmt.Errorf("failed to watch RoleBinding: %w", err)
 	}
 
+	// Watch for changes to Namespaces so that cluster-scoped NumaflowControllerRollouts update the namespaces they manage
+	if err := controller.Watch(source.Kind(mgr.GetCache(), &corev1.Namespace{},
+		handler.TypedEnqueueRequestsFromMapFunc(r.enqueueClusterScopedRollouts), predicate.TypedLabelChangedPredicate[*corev1.Namespace]{})); err != nil {
+		return fmt.Errorf("failed to watch Namespace: %w", err)
+	}
+
 	return nil
 }
 
+// enqueue all of the cluster-scoped NumaflowControllerRollouts, whose managed namespaces may have changed
+func (r *NumaflowControllerRolloutReconciler) enqueueClusterScopedRollouts(ctx context.Context, _ *corev1.Namespace) []reconcile.Request {
+	controllerRollouts := &apiv1.NumaflowControllerRolloutList{}
+	if err := r.client.List(ctx, controllerRollouts); err != nil {
+		logger.FromContext(ctx).Error(err, "failed to list NumaflowControllerRollouts")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, controllerRollout := range controllerRollouts.Items {
+		if controllerRollout.Spec.ClusterScoped {
+			requests = append(requests, reconcile.Request{NamespacedName: k8stypes.NamespacedName{Namespace: controllerRollout.Namespace, Name: controllerRollout.Name}})
+		}
+	}
+	return requests
+}
+
 // SplitYAMLToString splits a YAML file into strings. Returns list of yamls
 // found in the yaml. If an error occurs, returns objects that have been parsed so far too.
 func SplitYAMLToString(yamlData []byte) ([]string, error) {
@@ -404,7 +404,11 @@ func Test_resolveManifestTemplate(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			manifestBytes, err := resolveManifestTemplate(tc.manifest, tc.rollout)
+			var controller *apiv1.Controller
+			if tc.rollout != nil {
+				controller = &tc.rollout.Spec.Controller
+			}
+			manifestBytes, err := resolveManifestTemplate(tc.manifest, controller)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -674,3 +678,318 @@ func createDeploymentDefinition(imagePath string, stillReconciling bool) *appsv1
 		},
 	}
 }
+
+func Test_NumaflowControllerRollout_clusterScoped(t *testing.T) {
+	restConfig, numaflowClientSet, numaplaneClient, k8sClientSet, err := commontest.PrepareK8SEnvironment()
+	assert.Nil(t, err)
+	assert.Nil(t, kubernetes.SetDynamicClient(restConfig))
+
+	ctx := context.Background()
+	r := &NumaflowControllerRolloutReconciler{client: numaplaneClient}
+	pm := GetPauseModule()
+
+	// namespaces "team-a-*" are selected, "team-b" isn't
+	for namespace, team := range map[string]string{"team-a-1": "a", "team-a-2": "a", "team-b": "b"} {
+		_, err := k8sClientSet.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: map[string]string{"team": team}}}, metav1.CreateOptions{})
+		if !errors.IsAlreadyExists(err) {
+			assert.NoError(t, err)
+		}
+	}
+	// the cluster-scoped Numaflow Controller only manages the Pipelines with its InstanceID
+	for _, pipeline := range []struct {
+		namespace  string
+		name       string
+		instanceID string
+	}{
+		{namespace: "team-a-1", name: "pipeline-managed-0", instanceID: "1"},
+		{namespace: "team-a-2", name: "pipeline-managed-0", instanceID: "1"},
+		{namespace: "team-a-1", name: "pipeline-other-instance-0", instanceID: "2"},
+		{namespace: "team-b", name: "pipeline-unselected-namespace-0", instanceID: "1"},
+	} {
+		_, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(pipeline.namespace).Create(ctx, &numaflowv1.Pipeline{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   pipeline.namespace,
+				Name:        pipeline.name,
+				Labels:      map[string]string{common.LabelKeyParentRollout: strings.TrimSuffix(pipeline.name, "-0")},
+				Annotations: map[string]string{common.AnnotationKeyNumaflowInstanceID: pipeline.instanceID},
+			},
+			Spec: numaflowv1.PipelineSpec{InterStepBufferServiceName: defaultISBSvcRolloutName},
+		}, metav1.CreateOptions{})
+		if !errors.IsAlreadyExists(err) {
+			assert.NoError(t, err)
+		}
+	}
+
+	rollout := createNumaflowControllerRolloutDef(defaultNamespace, "1.2.1", "", []metav1.Condition{})
+	rollout.Spec.Controller.InstanceID = "1"
+	rollout.Spec.ClusterScoped = true
+	rollout.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+	rolloutName := types.NamespacedName{Namespace: rollout.Namespace, Name: rollout.Name}
+	defer pm.deleteClusterScopedController(rolloutName)
+
+	assert.NoError(t, r.updateManagedNamespaces(ctx, rollout))
+	assert.Equal(t, []string{"team-a-1", "team-a-2"}, rollout.Status.ManagedNamespaces)
+
+	// the Pause Request is keyed by InstanceID rather than namespace
+	controllerKey := pm.getClusterScopedNumaflowControllerKey("1")
+	assert.Equal(t, controllerKey, getNumaflowControllerRolloutKey(rollout))
+	assert.Equal(t, controllerKey, r.getRolloutKey(rollout.Namespace, rollout.Name))
+
+	pipelines, err := r.getPipelineList(ctx, rollout.Namespace, rollout.Name)
+	assert.NoError(t, err)
+	pipelineNames := []string{}
+	for _, pipeline := range pipelines {
+		pipelineNames = append(pipelineNames, types.NamespacedName{Namespace: pipeline.Namespace, Name: pipeline.Name}.String())
+	}
+	assert.ElementsMatch(t, []string{"team-a-1/pipeline-managed-0", "team-a-2/pipeline-managed-0"}, pipelineNames)
+
+	// Pipelines check the Pause Request of the Numaflow Controller managing them
+	assert.Equal(t, controllerKey, pm.getNumaflowControllerKeyForPipeline("team-a-2", "1"))
+	assert.Equal(t, pm.getNumaflowControllerKey("team-a-1"), pm.getNumaflowControllerKeyForPipeline("team-a-1", "2"))
+	assert.Equal(t, pm.getNumaflowControllerKey("team-b"), pm.getNumaflowControllerKeyForPipeline("team-b", "1"))
+
+	// going back to namespace scope removes the cluster-scoped Pause Request
+	pm.newPauseRequest(controllerKey)
+	rollout.Spec.ClusterScoped = false
+	assert.NoError(t, r.updateManagedNamespaces(ctx, rollout))
+	assert.Nil(t, rollout.Status.ManagedNamespaces)
+	_, found := pm.getPauseRequest(controllerKey)
+	assert.False(t, found)
+	assert.Equal(t, pm.getNumaflowControllerKey("team-a-2"), pm.getNumaflowControllerKeyForPipeline("team-a-2", "1"))
+	assert.Equal(t, pm.getNumaflowControllerKey(defaultNamespace), r.getRolloutKey(rollout.Namespace, rollout.Name))
+}
+
+// a Numaflow Controller manifest whose resources are named by InstanceID, so that two of them can run side by side
+const progressiveControllerManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: numaflow-controller{{ .InstanceSuffix }}
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: controller-manager{{ .InstanceSuffix }}
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: controller-manager{{ .InstanceSuffix }}
+    spec:
+      containers:
+        - name: controller-manager
+          image: quay.io/numaproj/numaflow:v%s
+`
+
+func Test_reconcile_numaflowcontrollerrollout_Progressive(t *testing.T) {
+	restConfig, numaflowClientSet, numaplaneClient, k8sClientSet, err := commontest.PrepareK8SEnvironment()
+	assert.Nil(t, err)
+	assert.Nil(t, kubernetes.SetDynamicClient(restConfig))
+
+	config.GetConfigManagerInstance().UpdateUSDEConfig(config.USDEConfig{DefaultUpgradeStrategy: config.ProgressiveStrategyID})
+	controllerDefinitions := config.NumaflowControllerDefinitionConfig{}
+	for _, version := range []string{"1.2.0", "1.2.1"} {
+		controllerDefinitions.ControllerDefinitions = append(controllerDefinitions.ControllerDefinitions,
+			apiv1.ControllerDefinitions{Version: version, FullSpec: fmt.Sprintf(progressiveControllerManifest, version)})
+	}
+	config.GetConfigManagerInstance().GetControllerDefinitionsMgr().UpdateNumaflowControllerDefinitionConfig(controllerDefinitions)
+
+	ctx := context.Background()
+
+	// other tests may call this, but it fails if called more than once
+	if customMetrics == nil {
+		customMetrics = metrics.RegisterCustomMetrics()
+	}
+
+	r, err := NewNumaflowControllerRolloutReconciler(
+		numaplaneClient,
+		scheme.Scheme,
+		restConfig,
+		kubernetes.NewKubectl(),
+		customMetrics,
+		record.NewFakeRecorder(64),
+	)
+	assert.NoError(t, err)
+
+	// first delete previous resources in case they already exist, in Kubernetes
+	_ = k8sClientSet.AppsV1().Deployments(defaultNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+	_ = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+	_ = numaflowClientSet.NumaflowV1alpha1().MonoVertices(defaultNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+	_ = numaflowClientSet.NumaflowV1alpha1().InterStepBufferServices(defaultNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+	_ = numaplaneClient.Delete(ctx, &apiv1.PipelineRollout{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: defaultPipelineRolloutName}})
+	_ = numaplaneClient.Delete(ctx, &apiv1.NumaflowControllerRollout{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: NumaflowControllerDeploymentName}})
+
+	// the Numaflow Controller is running version 1.2.0, managing a healthy Pipeline
+	rollout := createNumaflowControllerRolloutDef(defaultNamespace, "1.2.0", "", []metav1.Condition{})
+	rollout.UID = ""
+	typeMeta := rollout.TypeMeta
+	assert.NoError(t, numaplaneClient.Create(ctx, rollout))
+	rollout.TypeMeta = typeMeta
+	// (the children of other tests' Rollouts mustn't be moved to its Numaflow Controller)
+	defer func() { _ = numaplaneClient.Delete(ctx, rollout) }()
+	rollout.Status.Init(rollout.Generation)
+	_, err = r.reconcile(ctx, rollout, defaultNamespace, time.Now())
+	assert.NoError(t, err)
+
+	createPipelineRolloutInK8S(ctx, t, numaplaneClient, createPipelineRollout(numaflowv1.PipelineSpec{InterStepBufferServiceName: defaultISBSvcRolloutName}, map[string]string{}, map[string]string{}))
+	pipeline := createDefaultPipelineOfPhase(numaflowv1.PipelinePhaseRunning)
+	pipeline.Status.ObservedGeneration = 1
+	pipeline.Status.Conditions = []metav1.Condition{{Type: string(numaflowv1.PipelineConditionDeployed), Status: metav1.ConditionTrue, Reason: "Successful", LastTransitionTime: metav1.Now()}}
+	createPipelineInK8S(ctx, t, numaflowClientSet, pipeline)
+
+	getPipelineInstanceID := func() string {
+		pipeline, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Get(ctx, defaultPipelineName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		return pipeline.Annotations[common.AnnotationKeyNumaflowInstanceID]
+	}
+
+	// upgrading runs the new version alongside it under a new InstanceID
+	rollout.Spec.Controller.Version = "1.2.1"
+	result, err := r.reconcile(ctx, rollout, defaultNamespace, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, common.DefaultDelayedRequeue, result)
+	assert.Equal(t, apiv1.PhasePending, rollout.Status.Phase)
+	assert.Equal(t, apiv1.ControllerProgressiveStatus{PromotedVersion: "1.2.0", UpgradingInstanceID: "0"}, rollout.Status.ProgressiveStatus)
+	promotedDeployment, err := k8sClientSet.AppsV1().Deployments(defaultNamespace).Get(ctx, NumaflowControllerDeploymentName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "quay.io/numaproj/numaflow:v1.2.0", promotedDeployment.Spec.Template.Spec.Containers[0].Image)
+	upgradingDeployment, err := k8sClientSet.AppsV1().Deployments(defaultNamespace).Get(ctx, NumaflowControllerDeploymentName+"-0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "quay.io/numaproj/numaflow:v1.2.1", upgradingDeployment.Spec.Template.Spec.Containers[0].Image)
+
+	// the Pipeline doesn't move until the new Numaflow Controller is healthy
+	assert.Equal(t, "", getPipelineInstanceID())
+	upgradingDeployment.Status = appsv1.DeploymentStatus{ObservedGeneration: upgradingDeployment.Generation, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1, ReadyReplicas: 1}
+	_, err = k8sClientSet.AppsV1().Deployments(defaultNamespace).UpdateStatus(ctx, upgradingDeployment, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	_, err = r.reconcile(ctx, rollout, defaultNamespace, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "0", getPipelineInstanceID())
+	assert.Equal(t, []apiv1.MigratedChild{{Kind: "PipelineRollout", Name: defaultPipelineRolloutName}}, rollout.Status.ProgressiveStatus.MigratedChildren)
+	// and its PipelineRollout agrees on which Numaflow Controller manages it
+	instanceID, err := getNumaflowControllerInstanceID(ctx, numaplaneClient, defaultNamespace, "PipelineRollout", defaultPipelineRolloutName, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "0", instanceID)
+	instanceID, err = getNumaflowControllerInstanceID(ctx, numaplaneClient, defaultNamespace, "PipelineRollout", "other-pipeline", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "", instanceID)
+
+	// once it's healthy, the new Numaflow Controller is promoted and the previous one removed
+	_, err = r.reconcile(ctx, rollout, defaultNamespace, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, apiv1.PhaseDeployed, rollout.Status.Phase)
+	assert.Equal(t, apiv1.ControllerProgressiveStatus{PromotedInstanceID: "0"}, rollout.Status.ProgressiveStatus)
+	if condition := rollout.Status.GetCondition(apiv1.ConditionProgressiveUpgradeSucceeded); assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	}
+	promotedDeployment, err = k8sClientSet.AppsV1().Deployments(defaultNamespace).Get(ctx, NumaflowControllerDeploymentName, metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err) || promotedDeployment.DeletionTimestamp != nil)
+	instanceID, err = getNumaflowControllerInstanceID(ctx, numaplaneClient, defaultNamespace, "PipelineRollout", "other-pipeline", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "0", instanceID)
+}
+
+// a child which fails after moving to the new Numaflow Controller moves back, and the upgrade isn't attempted again
+func Test_reconcile_numaflowcontrollerrollout_ProgressiveFailure(t *testing.T) {
+	restConfig, numaflowClientSet, numaplaneClient, k8sClientSet, err := commontest.PrepareK8SEnvironment()
+	assert.Nil(t, err)
+	assert.Nil(t, kubernetes.SetDynamicClient(restConfig))
+
+	config.GetConfigManagerInstance().UpdateUSDEConfig(config.USDEConfig{DefaultUpgradeStrategy: config.ProgressiveStrategyID})
+	controllerDefinitions := config.NumaflowControllerDefinitionConfig{}
+	for _, version := range []string{"1.2.0", "1.2.1"} {
+		controllerDefinitions.ControllerDefinitions = append(controllerDefinitions.ControllerDefinitions,
+			apiv1.ControllerDefinitions{Version: version, FullSpec: fmt.Sprintf(progressiveControllerManifest, version)})
+	}
+	config.GetConfigManagerInstance().GetControllerDefinitionsMgr().UpdateNumaflowControllerDefinitionConfig(controllerDefinitions)
+
+	ctx := context.Background()
+
+	// other tests may call this, but it fails if called more than once
+	if customMetrics == nil {
+		customMetrics = metrics.RegisterCustomMetrics()
+	}
+
+	r, err := NewNumaflowControllerRolloutReconciler(
+		numaplaneClient,
+		scheme.Scheme,
+		restConfig,
+		kubernetes.NewKubectl(),
+		customMetrics,
+		record.NewFakeRecorder(64),
+	)
+	assert.NoError(t, err)
+
+	// first delete previous resources in case they already exist, in Kubernetes
+	_ = k8sClientSet.AppsV1().Deployments(defaultNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+	_ = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+	_ = numaflowClientSet.NumaflowV1alpha1().MonoVertices(defaultNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+	_ = numaflowClientSet.NumaflowV1alpha1().InterStepBufferServices(defaultNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+	_ = numaplaneClient.Delete(ctx, &apiv1.PipelineRollout{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: defaultPipelineRolloutName}})
+	_ = numaplaneClient.Delete(ctx, &apiv1.NumaflowControllerRollout{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: NumaflowControllerDeploymentName}})
+
+	// the Numaflow Controller is running version 1.2.0, managing a healthy Pipeline
+	rollout := createNumaflowControllerRolloutDef(defaultNamespace, "1.2.0", "", []metav1.Condition{})
+	rollout.UID = ""
+	typeMeta := rollout.TypeMeta
+	assert.NoError(t, numaplaneClient.Create(ctx, rollout))
+	rollout.TypeMeta = typeMeta
+	defer func() { _ = numaplaneClient.Delete(ctx, rollout) }()
+	rollout.Status.Init(rollout.Generation)
+	_, err = r.reconcile(ctx, rollout, defaultNamespace, time.Now())
+	assert.NoError(t, err)
+
+	createPipelineRolloutInK8S(ctx, t, numaplaneClient, createPipelineRollout(numaflowv1.PipelineSpec{InterStepBufferServiceName: defaultISBSvcRolloutName}, map[string]string{}, map[string]string{}))
+	pipeline := createDefaultPipelineOfPhase(numaflowv1.PipelinePhaseRunning)
+	pipeline.Status.ObservedGeneration = 1
+	pipeline.Status.Conditions = []metav1.Condition{{Type: string(numaflowv1.PipelineConditionDeployed), Status: metav1.ConditionTrue, Reason: "Successful", LastTransitionTime: metav1.Now()}}
+	createPipelineInK8S(ctx, t, numaflowClientSet, pipeline)
+
+	getPipeline := func() *numaflowv1.Pipeline {
+		pipeline, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Get(ctx, defaultPipelineName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		return pipeline
+	}
+
+	// the Pipeline moves to the new Numaflow Controller once it's healthy
+	rollout.Spec.Controller.Version = "1.2.1"
+	_, err = r.reconcile(ctx, rollout, defaultNamespace, time.Now())
+	assert.NoError(t, err)
+	upgradingDeployment, err := k8sClientSet.AppsV1().Deployments(defaultNamespace).Get(ctx, NumaflowControllerDeploymentName+"-0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	upgradingDeployment.Status = appsv1.DeploymentStatus{ObservedGeneration: upgradingDeployment.Generation, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1, ReadyReplicas: 1}
+	_, err = k8sClientSet.AppsV1().Deployments(defaultNamespace).UpdateStatus(ctx, upgradingDeployment, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	_, err = r.reconcile(ctx, rollout, defaultNamespace, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "0", getPipeline().Annotations[common.AnnotationKeyNumaflowInstanceID])
+
+	// but it fails there
+	failedPipeline := getPipeline()
+	failedPipeline.Status.Phase = numaflowv1.PipelinePhaseFailed
+	failedPipeline.Status.ObservedGeneration = failedPipeline.Generation
+	_, err = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).UpdateStatus(ctx, failedPipeline, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	// so it moves back to the promoted Numaflow Controller, and the new one is removed
+	result, err := r.reconcile(ctx, rollout, defaultNamespace, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, common.DefaultDelayedRequeue, result)
+	assert.Equal(t, "", getPipeline().Annotations[common.AnnotationKeyNumaflowInstanceID])
+	assert.Equal(t, apiv1.ControllerProgressiveStatus{PromotedVersion: "1.2.0", FailedVersion: "1.2.1"}, rollout.Status.ProgressiveStatus)
+	if condition := rollout.Status.GetCondition(apiv1.ConditionProgressiveUpgradeSucceeded); assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	}
+	upgradingDeployment, err = k8sClientSet.AppsV1().Deployments(defaultNamespace).Get(ctx, NumaflowControllerDeploymentName+"-0", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err) || upgradingDeployment.DeletionTimestamp != nil)
+	instanceID, err := getNumaflowControllerInstanceID(ctx, numaplaneClient, defaultNamespace, "PipelineRollout", defaultPipelineRolloutName, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "", instanceID)
+
+	// and the same version isn't attempted again
+	_, err = r.reconcile(ctx, rollout, defaultNamespace, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, apiv1.ControllerProgressiveStatus{PromotedVersion: "1.2.0", FailedVersion: "1.2.1"}, rollout.Status.ProgressiveStatus)
+	promotedDeployment, err := k8sClientSet.AppsV1().Deployments(defaultNamespace).Get(ctx, NumaflowControllerDeploymentName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "quay.io/numaproj/numaflow:v1.2.0", promotedDeployment.Spec.Template.Spec.Containers[0].Image)
+}
@@ -0,0 +1,460 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sort"
+
+	gitopsSyncCommon "github.com/argoproj/gitops-engine/pkg/sync/common"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// A Progressive upgrade of a NumaflowControllerRollout runs the new version of the Numaflow Controller alongside the
+// promoted one under a distinct InstanceID. The children of the Rollouts in the namespace (first Pipelines, then MonoVertices,
+// then InterStepBufferServices) are moved onto it one at a time by updating their "numaflow.numaproj.io/instance" annotation,
+// and each one must be healthy before the next one moves. Once they all have, the new Numaflow Controller is promoted and the
+// previous one is removed.
+// The Rollouts determine the annotation for their children from the NumaflowControllerRollout's Status
+// (see getNumaflowControllerInstanceID()), so that they agree on which Numaflow Controller manages each child.
+
+// the kinds of children which move to the new Numaflow Controller, in the order in which they move
+var migratingChildKinds = []struct {
+	rolloutKind string
+	pluralName  string
+}{
+	{rolloutKind: apiv1.PipelineRolloutGroupVersionKind.Kind, pluralName: "pipelines"},
+	{rolloutKind: apiv1.MonoVertexRolloutGroupVersionKind.Kind, pluralName: "monovertices"},
+	{rolloutKind: apiv1.ISBServiceRolloutGroupVersionKind.Kind, pluralName: "interstepbufferservices"},
+}
+
+// migratingChild is the set of children of a Rollout which move to the new Numaflow Controller together
+type migratingChild struct {
+	rolloutKind string
+	rolloutName string
+	children    []*kubernetes.GenericObject
+}
+
+// get the promoted Numaflow Controller, which runs the version in the spec unless a Progressive upgrade is in progress
+func getPromotedController(controllerRollout *apiv1.NumaflowControllerRollout) apiv1.Controller {
+	version := controllerRollout.Spec.Controller.Version
+	if controllerRollout.Status.ProgressiveStatus.UpgradingInstanceID != "" {
+		version = controllerRollout.Status.ProgressiveStatus.PromotedVersion
+	}
+	return apiv1.Controller{InstanceID: controllerRollout.GetPromotedInstanceID(), Version: version}
+}
+
+// get the InstanceID for the next Numaflow Controller created by a Progressive upgrade
+func (r *NumaflowControllerRolloutReconciler) getNextInstanceID(controllerRollout *apiv1.NumaflowControllerRollout) string {
+	nameCount := int32(0)
+	if controllerRollout.Status.NameCount != nil {
+		nameCount = *controllerRollout.Status.NameCount
+	}
+	nextNameCount := nameCount + 1
+	controllerRollout.Status.NameCount = &nextNameCount
+
+	if controllerRollout.Spec.Controller.InstanceID == "" {
+		return fmt.Sprint(nameCount)
+	}
+	return fmt.Sprintf("%s-%d", controllerRollout.Spec.Controller.InstanceID, nameCount)
+}
+
+// process a NumaflowControllerRollout using the Progressive strategy
+// return whether there's no upgrade in progress any longer, in which case the caller syncs the promoted Numaflow Controller
+func (r *NumaflowControllerRolloutReconciler) processWithProgressive(
+	ctx context.Context,
+	controllerRollout *apiv1.NumaflowControllerRollout,
+	namespace string,
+	promotedDeployment *appsv1.Deployment,
+) (bool, error) {
+	numaLogger := logger.FromContext(ctx)
+	progressiveStatus := &controllerRollout.Status.ProgressiveStatus
+
+	if progressiveStatus.UpgradingInstanceID == "" {
+		needsUpdating, _, err := r.isControllerDeploymentUpdating(ctx, controllerRollout, promotedDeployment)
+		if err != nil {
+			return false, err
+		}
+		if !needsUpdating {
+			progressiveStatus.FailedVersion = ""
+			return true, nil
+		}
+		if progressiveStatus.FailedVersion == controllerRollout.Spec.Controller.Version {
+			// keep the promoted Numaflow Controller as it is until the version changes
+			numaLogger.Debugf("not upgrading to Numaflow Controller version %s again since its Progressive upgrade failed", progressiveStatus.FailedVersion)
+			controllerRollout.Status.MarkWaitingFor("the Numaflow Controller version to change, since the Progressive upgrade to %s failed",
+				progressiveStatus.FailedVersion)
+			return false, r.syncFailedProgressiveUpgrade(ctx, controllerRollout, namespace)
+		}
+		promotedVersion, err := getControllerDeploymentVersion(promotedDeployment)
+		if err != nil {
+			return false, err
+		}
+
+		progressiveStatus.PromotedInstanceID = controllerRollout.GetPromotedInstanceID()
+		progressiveStatus.PromotedVersion = promotedVersion
+		progressiveStatus.UpgradingInstanceID = r.getNextInstanceID(controllerRollout)
+		progressiveStatus.MigratedChildren = nil
+		progressiveStatus.FailedVersion = ""
+		numaLogger.Infof("starting Progressive upgrade from Numaflow Controller version %s to %s with InstanceID %q",
+			promotedVersion, controllerRollout.Spec.Controller.Version, progressiveStatus.UpgradingInstanceID)
+		r.recorder.Eventf(controllerRollout, corev1.EventTypeNormal, "ProgressiveUpgradeStarted", "Running Numaflow Controller version %s with InstanceID %q alongside version %s",
+			controllerRollout.Spec.Controller.Version, progressiveStatus.UpgradingInstanceID, promotedVersion)
+		if err := r.updateNumaflowControllerRolloutStatus(ctx, controllerRollout); err != nil {
+			return false, err
+		}
+	} else if controllerRollout.Spec.Controller.Version == progressiveStatus.PromotedVersion {
+		return r.abortProgressiveUpgrade(ctx, controllerRollout)
+	}
+
+	upgradingController := apiv1.Controller{InstanceID: progressiveStatus.UpgradingInstanceID, Version: controllerRollout.Spec.Controller.Version}
+	phase, err := r.sync(controllerRollout, namespace, []apiv1.Controller{getPromotedController(controllerRollout), upgradingController}, numaLogger)
+	if err != nil {
+		return false, err
+	}
+	if phase != gitopsSyncCommon.OperationSucceeded {
+		return false, fmt.Errorf("sync operation is not successful")
+	}
+	// (the sync marks it deployed, but it isn't until the upgrade is done)
+	controllerRollout.Status.MarkPending()
+
+	upgradingDeployment, _, err := r.getNumaflowControllerDeploymentForInstance(ctx, controllerRollout.Namespace, progressiveStatus.UpgradingInstanceID)
+	if err != nil {
+		return false, err
+	}
+	if healthy, _, msg := processDeploymentHealth(upgradingDeployment); !healthy {
+		numaLogger.Debugf("waiting for new Numaflow Controller to be healthy: %s", msg)
+		controllerRollout.Status.MarkWaitingFor("new Numaflow Controller with InstanceID %q to be healthy: %s", progressiveStatus.UpgradingInstanceID, msg)
+		return false, nil
+	}
+
+	done, failureMsg, err := r.migrateChildren(ctx, controllerRollout)
+	if err != nil {
+		return false, err
+	}
+	if failureMsg != "" {
+		return false, r.failProgressiveUpgrade(ctx, controllerRollout, namespace, failureMsg)
+	}
+	if !done {
+		return false, nil
+	}
+
+	// every child has moved, so promote the new Numaflow Controller
+	numaLogger.Infof("Progressive upgrade done: promoting Numaflow Controller with InstanceID %q", progressiveStatus.UpgradingInstanceID)
+	r.recorder.Eventf(controllerRollout, corev1.EventTypeNormal, "ProgressiveUpgradeSucceeded", "Numaflow Controller version %s with InstanceID %q promoted",
+		controllerRollout.Spec.Controller.Version, progressiveStatus.UpgradingInstanceID)
+	*progressiveStatus = apiv1.ControllerProgressiveStatus{PromotedInstanceID: progressiveStatus.UpgradingInstanceID}
+	controllerRollout.Status.MarkProgressiveUpgradeSucceeded("all children moved to the new Numaflow Controller", controllerRollout.Generation)
+	// the Rollouts mustn't see the previous Numaflow Controller as promoted once it's removed
+	if err := r.updateNumaflowControllerRolloutStatus(ctx, controllerRollout); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// move the children onto the new Numaflow Controller one at a time, each once the previous one is healthy
+// return whether they've all moved, and a message if any of them failed after moving
+func (r *NumaflowControllerRolloutReconciler) migrateChildren(ctx context.Context, controllerRollout *apiv1.NumaflowControllerRollout) (bool, string, error) {
+	numaLogger := logger.FromContext(ctx)
+	progressiveStatus := &controllerRollout.Status.ProgressiveStatus
+	upgradingInstanceID := progressiveStatus.UpgradingInstanceID
+
+	migratingChildren, err := r.getMigratingChildren(ctx, controllerRollout)
+	if err != nil {
+		return false, "", err
+	}
+
+	for _, migratingChild := range migratingChildren {
+		migratedChild := findMigratedChild(progressiveStatus.MigratedChildren, migratingChild.rolloutKind, migratingChild.rolloutName)
+		if migratedChild == nil {
+			// move this one next: first record it, so that its Rollout agrees on its Numaflow Controller
+			numaLogger.Infof("moving children of %s %s to the new Numaflow Controller", migratingChild.rolloutKind, migratingChild.rolloutName)
+			progressiveStatus.MigratedChildren = append(progressiveStatus.MigratedChildren,
+				apiv1.MigratedChild{Kind: migratingChild.rolloutKind, Name: migratingChild.rolloutName})
+			if err := r.updateNumaflowControllerRolloutStatus(ctx, controllerRollout); err != nil {
+				return false, "", err
+			}
+			controllerRollout.Status.MarkWaitingFor("children of %s %s to move to the new Numaflow Controller", migratingChild.rolloutKind, migratingChild.rolloutName)
+			return false, "", setNumaflowControllerInstanceID(ctx, r.client, migratingChild.children, upgradingInstanceID)
+		}
+		if migratedChild.Healthy {
+			continue
+		}
+
+		// (the Rollout may have applied its child before it saw the child move, so make sure it has)
+		if err := setNumaflowControllerInstanceID(ctx, r.client, migratingChild.children, upgradingInstanceID); err != nil {
+			return false, "", err
+		}
+		healthy, failureMsg, err := checkMigratedChildHealth(migratingChild)
+		if err != nil {
+			return false, "", err
+		}
+		if failureMsg != "" {
+			return false, failureMsg, nil
+		}
+		if !healthy {
+			numaLogger.Debugf("waiting for children of %s %s to be healthy on the new Numaflow Controller", migratingChild.rolloutKind, migratingChild.rolloutName)
+			controllerRollout.Status.MarkWaitingFor("children of %s %s to be healthy on the new Numaflow Controller", migratingChild.rolloutKind, migratingChild.rolloutName)
+			return false, "", nil
+		}
+		migratedChild.Healthy = true
+		r.recorder.Eventf(controllerRollout, corev1.EventTypeNormal, "ChildMigrated", "%s %s moved to Numaflow Controller with InstanceID %q",
+			migratingChild.rolloutKind, migratingChild.rolloutName, upgradingInstanceID)
+	}
+	return true, "", nil
+}
+
+// the upgrade was reverted to the promoted version before it finished: move the children back to the promoted Numaflow
+// Controller, after which the caller removes the new one
+func (r *NumaflowControllerRolloutReconciler) abortProgressiveUpgrade(ctx context.Context, controllerRollout *apiv1.NumaflowControllerRollout) (bool, error) {
+	progressiveStatus := &controllerRollout.Status.ProgressiveStatus
+	logger.FromContext(ctx).Infof("aborting Progressive upgrade to Numaflow Controller with InstanceID %q", progressiveStatus.UpgradingInstanceID)
+
+	migratingChildren, err := r.getMigratingChildren(ctx, controllerRollout)
+	if err != nil {
+		return false, err
+	}
+	*progressiveStatus = apiv1.ControllerProgressiveStatus{PromotedInstanceID: progressiveStatus.PromotedInstanceID}
+	if err := r.updateNumaflowControllerRolloutStatus(ctx, controllerRollout); err != nil {
+		return false, err
+	}
+	for _, migratingChild := range migratingChildren {
+		if err := setNumaflowControllerInstanceID(ctx, r.client, migratingChild.children, controllerRollout.GetPromotedInstanceID()); err != nil {
+			return false, err
+		}
+	}
+	r.recorder.Eventf(controllerRollout, corev1.EventTypeNormal, "ProgressiveUpgradeAborted", "Numaflow Controller version %s restored for all children",
+		controllerRollout.Spec.Controller.Version)
+	return true, nil
+}
+
+// a child failed after moving to the new Numaflow Controller: move the children back to the promoted Numaflow Controller and
+// remove the new one, and don't attempt an upgrade to this version again
+func (r *NumaflowControllerRolloutReconciler) failProgressiveUpgrade(ctx context.Context, controllerRollout *apiv1.NumaflowControllerRollout,
+	namespace string, failureMsg string) error {
+	progressiveStatus := &controllerRollout.Status.ProgressiveStatus
+	logger.FromContext(ctx).Infof("Progressive upgrade to Numaflow Controller with InstanceID %q failed: %s", progressiveStatus.UpgradingInstanceID, failureMsg)
+
+	migratingChildren, err := r.getMigratingChildren(ctx, controllerRollout)
+	if err != nil {
+		return err
+	}
+	*progressiveStatus = apiv1.ControllerProgressiveStatus{
+		PromotedInstanceID: progressiveStatus.PromotedInstanceID,
+		PromotedVersion:    progressiveStatus.PromotedVersion,
+		FailedVersion:      controllerRollout.Spec.Controller.Version,
+	}
+	controllerRollout.Status.MarkProgressiveUpgradeFailed(failureMsg, controllerRollout.Generation)
+	r.recorder.Eventf(controllerRollout, corev1.EventTypeWarning, "ProgressiveUpgradeFailed", failureMsg)
+	// the Rollouts must see their children as managed by the promoted Numaflow Controller before they move back
+	if err := r.updateNumaflowControllerRolloutStatus(ctx, controllerRollout); err != nil {
+		return err
+	}
+	for _, migratingChild := range migratingChildren {
+		if err := setNumaflowControllerInstanceID(ctx, r.client, migratingChild.children, controllerRollout.GetPromotedInstanceID()); err != nil {
+			return err
+		}
+	}
+	return r.syncFailedProgressiveUpgrade(ctx, controllerRollout, namespace)
+}
+
+// after a failed Progressive upgrade, keep just the promoted Numaflow Controller at the version it ran before the upgrade
+func (r *NumaflowControllerRolloutReconciler) syncFailedProgressiveUpgrade(ctx context.Context, controllerRollout *apiv1.NumaflowControllerRollout, namespace string) error {
+	promotedController := apiv1.Controller{
+		InstanceID: controllerRollout.GetPromotedInstanceID(),
+		Version:    controllerRollout.Status.ProgressiveStatus.PromotedVersion,
+	}
+	phase, err := r.sync(controllerRollout, namespace, []apiv1.Controller{promotedController}, logger.FromContext(ctx))
+	if err != nil {
+		return err
+	}
+	if phase != gitopsSyncCommon.OperationSucceeded {
+		return fmt.Errorf("sync operation is not successful")
+	}
+	// (the sync marks it deployed, but the version in the spec isn't)
+	controllerRollout.Status.MarkPending()
+	return nil
+}
+
+// get the children managed by either of the NumaflowControllerRollout's Numaflow Controllers, grouped by Rollout, in the
+// order in which they move
+func (r *NumaflowControllerRolloutReconciler) getMigratingChildren(ctx context.Context, controllerRollout *apiv1.NumaflowControllerRollout) ([]migratingChild, error) {
+	progressiveStatus := controllerRollout.Status.ProgressiveStatus
+	instanceIDs := map[string]struct{}{controllerRollout.GetPromotedInstanceID(): {}, progressiveStatus.UpgradingInstanceID: {}}
+
+	var migratingChildren []migratingChild
+	for _, childKind := range migratingChildKinds {
+		children, err := kubernetes.ListLiveResource(ctx, common.NumaflowAPIGroup, common.NumaflowAPIVersion, childKind.pluralName,
+			controllerRollout.Namespace, common.LabelKeyParentRollout, "")
+		if err != nil {
+			return nil, err
+		}
+		childrenByRollout := map[string][]*kubernetes.GenericObject{}
+		for _, child := range children {
+			if _, managed := instanceIDs[child.Annotations[common.AnnotationKeyNumaflowInstanceID]]; managed {
+				rolloutName := child.Labels[common.LabelKeyParentRollout]
+				childrenByRollout[rolloutName] = append(childrenByRollout[rolloutName], child)
+			}
+		}
+		rolloutNames := make([]string, 0, len(childrenByRollout))
+		for rolloutName := range childrenByRollout {
+			rolloutNames = append(rolloutNames, rolloutName)
+		}
+		sort.Strings(rolloutNames)
+		for _, rolloutName := range rolloutNames {
+			migratingChildren = append(migratingChildren, migratingChild{rolloutKind: childKind.rolloutKind, rolloutName: rolloutName, children: childrenByRollout[rolloutName]})
+		}
+	}
+	return migratingChildren, nil
+}
+
+func findMigratedChild(migratedChildren []apiv1.MigratedChild, rolloutKind string, rolloutName string) *apiv1.MigratedChild {
+	for i := range migratedChildren {
+		if migratedChildren[i].Kind == rolloutKind && migratedChildren[i].Name == rolloutName {
+			return &migratedChildren[i]
+		}
+	}
+	return nil
+}
+
+// return whether the children of a Rollout are healthy after moving to the new Numaflow Controller, and a message if
+// any of them failed
+func checkMigratedChildHealth(migratingChild migratingChild) (bool, string, error) {
+	healthy := true
+	for _, child := range migratingChild.children {
+		childStatus, err := kubernetes.ParseStatus(child)
+		if err != nil {
+			return false, "", err
+		}
+		if childStatus.ObservedGeneration < child.Generation {
+			healthy = false
+			continue
+		}
+		switch childStatus.Phase {
+		case "Failed":
+			return false, fmt.Sprintf("%s %s/%s failed after moving to the new Numaflow Controller", child.Kind, child.Namespace, child.Name), nil
+		case "Paused":
+		case "Running":
+			if !isNumaflowChildReady(&childStatus) {
+				healthy = false
+			}
+		default:
+			healthy = false
+		}
+	}
+	return healthy, "", nil
+}
+
+// update the children to be managed by the Numaflow Controller with this InstanceID
+func setNumaflowControllerInstanceID(ctx context.Context, c client.Client, children []*kubernetes.GenericObject, instanceID string) error {
+	var annotationValue *string
+	if instanceID != "" {
+		annotationValue = &instanceID
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]*string{common.AnnotationKeyNumaflowInstanceID: annotationValue}},
+	})
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if child.Annotations[common.AnnotationKeyNumaflowInstanceID] == instanceID {
+			continue
+		}
+		if err := kubernetes.PatchResource(ctx, c, child, string(patch), k8stypes.MergePatchType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// get the InstanceID of the Numaflow Controller which manages the child of the Rollout with this kind and name, given the
+// InstanceID in the Rollout's spec: this differs once the namespace's Numaflow Controller has been upgraded with the
+// Progressive strategy, and while it's being upgraded depends on whether the child has moved
+// (a cluster-scoped Numaflow Controller always uses the InstanceID in its spec)
+func getNumaflowControllerInstanceID(ctx context.Context, c client.Client, namespace string, rolloutKind string, rolloutName string, specInstanceID string) (string, error) {
+	controllerRollouts := &apiv1.NumaflowControllerRolloutList{}
+	if err := c.List(ctx, controllerRollouts, client.InNamespace(namespace)); err != nil {
+		return "", fmt.Errorf("error listing NumaflowControllerRollouts in namespace %s: %v", namespace, err)
+	}
+	for _, controllerRollout := range controllerRollouts.Items {
+		if controllerRollout.Spec.ClusterScoped || controllerRollout.Spec.Controller.InstanceID != specInstanceID {
+			continue
+		}
+		progressiveStatus := controllerRollout.Status.ProgressiveStatus
+		if progressiveStatus.UpgradingInstanceID != "" && findMigratedChild(progressiveStatus.MigratedChildren, rolloutKind, rolloutName) != nil {
+			return progressiveStatus.UpgradingInstanceID, nil
+		}
+		return controllerRollout.GetPromotedInstanceID(), nil
+	}
+	return specInstanceID, nil
+}
+
+// resolvedInstanceID is the InstanceID of the Numaflow Controller which manages the child of a Rollout, as resolved for one
+// reconciliation of the Rollout
+type resolvedInstanceID struct {
+	namespace      string
+	rolloutKind    string
+	rolloutName    string
+	specInstanceID string
+	instanceID     string
+}
+
+type resolvedInstanceIDKey struct{}
+
+// resolve the InstanceID of the Numaflow Controller which manages the child of the Rollout once per reconciliation: the
+// returned context carries it to withNumaflowControllerInstanceID(), however many times the child definition is built
+func withResolvedNumaflowControllerInstanceID(ctx context.Context, c client.Client, namespace string, rolloutKind string, rolloutName string,
+	annotations map[string]string) (context.Context, error) {
+	specInstanceID := annotations[common.AnnotationKeyNumaflowInstanceID]
+	instanceID, err := getNumaflowControllerInstanceID(ctx, c, namespace, rolloutKind, rolloutName, specInstanceID)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, resolvedInstanceIDKey{}, resolvedInstanceID{
+		namespace:      namespace,
+		rolloutKind:    rolloutKind,
+		rolloutName:    rolloutName,
+		specInstanceID: specInstanceID,
+		instanceID:     instanceID,
+	}), nil
+}
+
+// get the annotations for the child of a Rollout with the InstanceID of the Numaflow Controller which manages it, using the
+// one resolved for this reconciliation if any
+func withNumaflowControllerInstanceID(ctx context.Context, c client.Client, namespace string, rolloutKind string, rolloutName string, annotations map[string]string) (map[string]string, error) {
+	specInstanceID := annotations[common.AnnotationKeyNumaflowInstanceID]
+	resolved, found := ctx.Value(resolvedInstanceIDKey{}).(resolvedInstanceID)
+	if !found || resolved.namespace != namespace || resolved.rolloutKind != rolloutKind || resolved.rolloutName != rolloutName ||
+		resolved.specInstanceID != specInstanceID {
+		var err error
+		resolved.instanceID, err = getNumaflowControllerInstanceID(ctx, c, namespace, rolloutKind, rolloutName, specInstanceID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	instanceID := resolved.instanceID
+	if instanceID == specInstanceID {
+		return annotations, nil
+	}
+
+	resolvedAnnotations := maps.Clone(annotations)
+	if resolvedAnnotations == nil {
+		resolvedAnnotations = map[string]string{}
+	}
+	if instanceID == "" {
+		delete(resolvedAnnotations, common.AnnotationKeyNumaflowInstanceID)
+	} else {
+		resolvedAnnotations[common.AnnotationKeyNumaflowInstanceID] = instanceID
+	}
+	return resolvedAnnotations, nil
+}
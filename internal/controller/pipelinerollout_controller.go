@@ -197,13 +197,25 @@ func (r *PipelineRolloutReconciler) processPipelineRollout(ctx context.Context,
 		}
 	}
 
+	// if asked to roll back to a previous revision, write it back into the spec: the updated spec is then reconciled as usual
+	if pipelineRollout.DeletionTimestamp.IsZero() {
+		rolledBack, err := rollbackIfRequested(ctx, r.client, r.recorder, pipelineRollout)
+		if err != nil {
+			r.ErrorHandler(pipelineRollout, err, "RollbackFailed", "Failed to roll back PipelineRollout")
+			return ctrl.Result{}, err
+		}
+		if rolledBack {
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
 	// save off a copy of the original before we modify it
 	pipelineRolloutOrig := pipelineRollout
 	pipelineRollout = pipelineRolloutOrig.DeepCopy()
 
 	pipelineRollout.Status.Init(pipelineRollout.Generation)
 
-	requeue, existingPipelineDef, err := r.reconcile(ctx, pipelineRollout, syncStartTime)
+	result, existingPipelineDef, err := r.reconcile(ctx, pipelineRollout, syncStartTime)
 	if err != nil {
 		r.ErrorHandler(pipelineRollout, err, "ReconcileFailed", "Failed to reconcile PipelineRollout")
 		statusUpdateErr := r.updatePipelineRolloutStatusToFailed(ctx, pipelineRollout, err)
@@ -211,12 +223,18 @@ func (r *PipelineRolloutReconciler) processPipelineRollout(ctx context.Context,
 			r.ErrorHandler(pipelineRollout, statusUpdateErr, "UpdateStatusFailed", "Failed to update PipelineRollout status")
 			return ctrl.Result{}, statusUpdateErr
 		}
+		if !isDryRun(pipelineRollout) {
+			recordRevision(ctx, r.client, r.recorder, pipelineRollout)
+		}
 
 		return ctrl.Result{}, err
 	}
 
 	// Update PipelineRollout Status based on child resource (Pipeline) Status
-	err = r.processPipelineStatus(ctx, pipelineRollout, existingPipelineDef)
+	// (in dry-run mode, there may not be a Pipeline yet, and looking for one mustn't reserve a name for it)
+	if existingPipelineDef != nil || !isDryRun(pipelineRollout) {
+		err = r.processPipelineStatus(ctx, pipelineRollout, existingPipelineDef)
+	}
 	if err != nil {
 		r.ErrorHandler(pipelineRollout, err, "ProcessPipelineStatusFailed", "Failed to process Pipeline Status")
 		statusUpdateErr := r.updatePipelineRolloutStatusToFailed(ctx, pipelineRollout, err)
@@ -246,6 +264,11 @@ func (r *PipelineRolloutReconciler) processPipelineRollout(ctx context.Context,
 
 	// Update the Status subresource
 	if pipelineRollout.DeletionTimestamp.IsZero() { // would've already been deleted
+		if !isDryRun(pipelineRollout) {
+			recordRevision(ctx, r.client, r.recorder, pipelineRollout)
+			result = processProgressDeadline(ctx, pipelineRollout, pipelineRollout.Spec.ProgressDeadlineSeconds,
+				ControllerPipelineRollout, r.recorder, r.customMetrics, result)
+		}
 		statusUpdateErr := r.updatePipelineRolloutStatus(ctx, pipelineRollout)
 		if statusUpdateErr != nil {
 			r.ErrorHandler(pipelineRollout, statusUpdateErr, "UpdateStatusFailed", "Failed to update PipelineRollout status")
@@ -256,8 +279,8 @@ func (r *PipelineRolloutReconciler) processPipelineRollout(ctx context.Context,
 	// generate the metrics for the Pipeline.
 	r.customMetrics.IncPipelineROsRunning(pipelineRollout.Name, pipelineRollout.Namespace)
 
-	if requeue {
-		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+	if !result.IsZero() {
+		return result, nil
 	}
 
 	r.recorder.Eventf(pipelineRollout, "Normal", "ReconcileSuccess", "Reconciliation successful")
@@ -351,7 +374,7 @@ func (r *PipelineRolloutReconciler) reconcile(
 	ctx context.Context,
 	pipelineRollout *apiv1.PipelineRollout,
 	syncStartTime time.Time,
-) (bool, *kubernetes.GenericObject, error) {
+) (ctrl.Result, *kubernetes.GenericObject, error) {
 	numaLogger := logger.FromContext(ctx)
 	defer func() {
 		if pipelineRollout.Status.IsHealthy() {
@@ -366,23 +389,61 @@ func (r *PipelineRolloutReconciler) reconcile(
 	if !pipelineRollout.DeletionTimestamp.IsZero() {
 		numaLogger.Info("Deleting PipelineRollout")
 		if controllerutil.ContainsFinalizer(pipelineRollout, finalizerName) {
+			if err := GetPauseModule().deletePauseRequest(ctx, GetPauseModule().getPipelineRolloutKey(pipelineRollout.Namespace, pipelineRollout.Name)); err != nil {
+				return ctrl.Result{}, nil, err
+			}
 			controllerutil.RemoveFinalizer(pipelineRollout, finalizerName)
 		}
 		// generate the metrics for the Pipeline deletion.
 		r.customMetrics.DecPipelineROsRunning(pipelineRollout.Name, pipelineRollout.Namespace)
 		r.customMetrics.ReconciliationDuration.WithLabelValues(ControllerPipelineRollout, "delete").Observe(time.Since(syncStartTime).Seconds())
 		r.customMetrics.PipelinesRolloutHealth.DeleteLabelValues(pipelineRollout.Namespace, pipelineRollout.Name)
-		return false, nil, nil
+		return ctrl.Result{}, nil, nil
 	}
 
+	// the Numaflow Controller which manages the Pipeline doesn't change during this reconciliation
+	ctx, err := withResolvedNumaflowControllerInstanceID(ctx, r.client, pipelineRollout.Namespace, apiv1.PipelineRolloutGroupVersionKind.Kind,
+		pipelineRollout.Name, pipelineRollout.Spec.Pipeline.Annotations)
+	if err != nil {
+		return ctrl.Result{}, nil, err
+	}
+
+	// in dry-run mode, just report what we would do
+	if isDryRun(pipelineRollout) {
+		numaLogger.Debug("PipelineRollout is in dry-run mode, planning changes without making them")
+		plan, existingPipelineDef, err := r.planPipelineRollout(ctx, pipelineRollout)
+		if err != nil {
+			return ctrl.Result{}, existingPipelineDef, err
+		}
+		pipelineRollout.Status.Plan = plan
+		return ctrl.Result{}, existingPipelineDef, nil
+	}
+	pipelineRollout.Status.Plan = nil
+
 	// add Finalizer so we can ensure that we take appropriate action when CRD is deleted
 	if !controllerutil.ContainsFinalizer(pipelineRollout, finalizerName) {
 		controllerutil.AddFinalizer(pipelineRollout, finalizerName)
 	}
 
+	// PipelineRollouts whose dependencies form a cycle could never be upgraded
+	cycle, err := findDependencyCycle(ctx, r.client, pipelineRollout)
+	if err != nil {
+		return ctrl.Result{}, nil, err
+	}
+	if cycle != nil {
+		return ctrl.Result{}, nil, fmt.Errorf("dependencies of PipelineRollout form a cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	if err := r.updateUserPauseRequest(ctx, pipelineRollout); err != nil {
+		return ctrl.Result{}, nil, err
+	}
+	if err := r.setPauseRequestsStatus(pipelineRollout); err != nil {
+		return ctrl.Result{}, nil, err
+	}
+
 	newPipelineDef, err := r.makeRunningPipelineDefinition(ctx, pipelineRollout)
 	if err != nil {
-		return false, nil, err
+		return ctrl.Result{}, nil, err
 	}
 
 	// Get the object to see if it exists
@@ -396,14 +457,14 @@ func (r *PipelineRolloutReconciler) reconcile(
 
 			err = kubernetes.CreateResource(ctx, r.client, newPipelineDef)
 			if err != nil {
-				return false, nil, err
+				return ctrl.Result{}, nil, err
 			}
 			pipelineRollout.Status.MarkDeployed(pipelineRollout.Generation)
 			r.customMetrics.ReconciliationDuration.WithLabelValues(ControllerPipelineRollout, "create").Observe(time.Since(syncStartTime).Seconds())
-			return false, existingPipelineDef, nil
+			return ctrl.Result{}, existingPipelineDef, nil
 		}
 
-		return false, existingPipelineDef, fmt.Errorf("error getting Pipeline: %v", err)
+		return ctrl.Result{}, existingPipelineDef, fmt.Errorf("error getting Pipeline: %v", err)
 	}
 
 	// Object already exists
@@ -411,14 +472,14 @@ func (r *PipelineRolloutReconciler) reconcile(
 	if !checkOwnerRef(existingPipelineDef.OwnerReferences, pipelineRollout.UID) {
 		errStr := fmt.Sprintf("Pipeline %s already exists in namespace, not owned by a PipelineRollout", existingPipelineDef.Name)
 		numaLogger.Debugf("PipelineRollout %s failed because %s", pipelineRollout.Name, errStr)
-		return false, existingPipelineDef, errors.New(errStr)
+		return ctrl.Result{}, existingPipelineDef, errors.New(errStr)
 	}
 	newPipelineDef, err = r.merge(existingPipelineDef, newPipelineDef)
 	if err != nil {
-		return false, nil, err
+		return ctrl.Result{}, nil, err
 	}
-	err = r.processExistingPipeline(ctx, pipelineRollout, existingPipelineDef, newPipelineDef, syncStartTime)
-	return false, existingPipelineDef, err
+	result, err := r.processExistingPipeline(ctx, pipelineRollout, existingPipelineDef, newPipelineDef, syncStartTime)
+	return result, existingPipelineDef, err
 }
 
 // determine if this Pipeline is owned by this PipelineRollout
@@ -454,21 +515,34 @@ func (r *PipelineRolloutReconciler) merge(existingPipeline *kubernetes.GenericOb
 	return resultPipeline, nil
 }
 
+// process an existing pipeline
+// return the result indicating whether and when to requeue, and error if any
 func (r *PipelineRolloutReconciler) processExistingPipeline(ctx context.Context, pipelineRollout *apiv1.PipelineRollout,
-	existingPipelineDef *kubernetes.GenericObject, newPipelineDef *kubernetes.GenericObject, syncStartTime time.Time) error {
+	existingPipelineDef *kubernetes.GenericObject, newPipelineDef *kubernetes.GenericObject, syncStartTime time.Time) (ctrl.Result, error) {
 
 	numaLogger := logger.FromContext(ctx)
 
-	// what is the preferred strategy for this namespace?
-	userPreferredStrategy, err := usde.GetUserStrategy(ctx, newPipelineDef.Namespace)
+	// what is the preferred strategy for this Rollout?
+	userPreferredStrategy, err := usde.GetUserStrategy(ctx, newPipelineDef.Namespace, pipelineRollout.GetUserUpgradeStrategy())
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
+	pipelineRollout.Status.UserStrategy = apiv1.UserUpgradeStrategy(userPreferredStrategy)
 
 	// does the Resource need updating, and if so how?
-	pipelineNeedsToUpdate, upgradeStrategyType, err := usde.ResourceNeedsUpdating(ctx, newPipelineDef, existingPipelineDef)
+	pipelineNeedsToUpdate, upgradeStrategyType, err := usde.ResourceNeedsUpdating(ctx, newPipelineDef, existingPipelineDef, userPreferredStrategy)
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
+	}
+	// some changes can't be applied in place (e.g. a Pipeline can't be moved onto a different InterStepBufferService, so it's
+	// recreated alongside the current one)
+	guardrailStrategy, err := checkPipelineGuardrails(ctx, r.client, pipelineRollout, existingPipelineDef, newPipelineDef)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if guardrailStrategy != apiv1.UpgradeStrategyNoOp {
+		pipelineNeedsToUpdate = true
+		upgradeStrategyType = guardrailStrategy
 	}
 	numaLogger.
 		WithValues("pipelineNeedsToUpdate", pipelineNeedsToUpdate, "upgradeStrategyType", upgradeStrategyType).
@@ -486,18 +560,41 @@ func (r *PipelineRolloutReconciler) processExistingPipeline(ctx context.Context,
 	numaLogger.Debugf("current inProgressStrategy=%s", inProgressStrategy)
 	inProgressStrategySet := (inProgressStrategy != apiv1.UpgradeStrategyNoOp)
 
+	// if not, an upgrade waits for the PipelineRollouts this one depends on to be upgraded first
+	if !inProgressStrategySet && pipelineNeedsToUpdate {
+		deployed, waitingFor, err := dependenciesDeployed(ctx, r.client, pipelineRollout)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !deployed {
+			numaLogger.Debugf("holding back upgrade: waiting for %s", waitingFor)
+			pipelineRollout.Status.MarkWaitingFor("%s", waitingFor)
+			return common.DefaultDelayedRequeue, nil
+		}
+	}
+
 	// if not, should we set one?
+	awaitingWindow := false
+	var untilWindow time.Duration
 	if !inProgressStrategySet {
-		if userPreferredStrategy == config.PPNDStrategyID {
+		// an update requiring PPND, Progressive or Recreate can only start during a maintenance window
+		awaitingWindow, untilWindow, err = awaitMaintenanceWindow(ctx, pipelineRollout, pipelineRollout.GetMaintenanceWindows(), upgradeStrategyType)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// PPND may be required by the user's preference (in case the ISBService or Numaflow Controller is pausing) or by the update itself
+		// (the ISBService and Numaflow Controller wait for their own maintenance windows, so their pause requests aren't held here)
+		if userPreferredStrategy == config.PPNDStrategyID || upgradeStrategyType == apiv1.UpgradeStrategyPPND {
 			// if the preferred strategy is PPND, do we need to start the process for PPND (if we haven't already)?
 			needPPND := false
-			ppndRequired, err := r.needPPND(ctx, pipelineRollout, newPipelineDef, upgradeStrategyType == apiv1.UpgradeStrategyPPND)
+			ppndRequired, err := r.needPPND(ctx, pipelineRollout, newPipelineDef, upgradeStrategyType == apiv1.UpgradeStrategyPPND && !awaitingWindow)
 			if err != nil {
-				return err
+				return ctrl.Result{}, err
 			}
 			if ppndRequired == nil { // not enough information
-				// TODO: mark something in the Status for why we're remaining in "Pending" here
-				return nil
+				pipelineRollout.Status.MarkWaitingFor("the ISBService and Numaflow Controller to report whether they require Pipelines to pause")
+				return ctrl.Result{}, nil
 			}
 			needPPND = *ppndRequired
 			if needPPND {
@@ -505,8 +602,16 @@ func (r *PipelineRolloutReconciler) processExistingPipeline(ctx context.Context,
 				r.inProgressStrategyMgr.setStrategy(ctx, pipelineRollout, inProgressStrategy)
 			}
 		}
-		if userPreferredStrategy == config.ProgressiveStrategyID {
-			if upgradeStrategyType == apiv1.UpgradeStrategyProgressive {
+		if inProgressStrategy == apiv1.UpgradeStrategyNoOp && upgradeStrategyType == apiv1.UpgradeStrategyProgressive && !awaitingWindow {
+			// don't retry a spec which already failed and was rolled back
+			previouslyFailed, err := progressiveUpgradePreviouslyFailed(ctx, pipelineRollout, r)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if previouslyFailed {
+				numaLogger.Debug("Progressive upgrade of this spec previously failed, not retrying")
+				pipelineRollout.Status.MarkProgressiveUpgradeFailed("Upgrade to this spec previously failed and was rolled back; update the spec to retry", pipelineRollout.Generation)
+			} else {
 				inProgressStrategy = apiv1.UpgradeStrategyProgressive
 				r.inProgressStrategyMgr.setStrategy(ctx, pipelineRollout, inProgressStrategy)
 			}
@@ -521,56 +626,77 @@ func (r *PipelineRolloutReconciler) processExistingPipeline(ctx context.Context,
 			if apierrors.IsNotFound(err) {
 				numaLogger.WithValues("pipelineDefinition", *newPipelineDef).Warn("Pipeline not found.")
 			} else {
-				return fmt.Errorf("error getting Pipeline for status processing: %v", err)
+				return ctrl.Result{}, fmt.Errorf("error getting Pipeline for status processing: %v", err)
 			}
 		}
 		newPipelineDef, err = r.merge(existingPipelineDef, newPipelineDef)
 		if err != nil {
-			return err
+			return ctrl.Result{}, err
 		}
 	}
 
 	// now do whatever the inProgressStrategy is
+	requeue := false
 	switch inProgressStrategy {
 	case apiv1.UpgradeStrategyPPND:
 		numaLogger.Debug("processing pipeline with PPND")
 		done, err := r.processExistingPipelineWithPPND(ctx, pipelineRollout, existingPipelineDef, newPipelineDef)
 		if err != nil {
-			return err
+			return ctrl.Result{}, err
 		}
 		if done {
 			r.inProgressStrategyMgr.unsetStrategy(ctx, pipelineRollout)
 		}
 
 	case apiv1.UpgradeStrategyProgressive:
-		if pipelineNeedsToUpdate {
+		// once started, a Progressive upgrade runs to completion even if the promoted child no longer needs updating
+		if pipelineNeedsToUpdate || progressiveUpgradeStarted(pipelineRollout) {
 			numaLogger.Debug("processing pipeline with Progressive")
 			done, err := processResourceWithProgressive(ctx, pipelineRollout, existingPipelineDef, r, r.client)
 			if err != nil {
-				return err
+				return ctrl.Result{}, err
 			}
 			if done {
 				r.inProgressStrategyMgr.unsetStrategy(ctx, pipelineRollout)
+			} else {
+				// the upgrading Pipeline may be under Analysis, which needs to be checked periodically
+				requeue = true
 			}
 		}
 	default:
 		if pipelineNeedsToUpdate && upgradeStrategyType == apiv1.UpgradeStrategyApply {
 			if err := updatePipelineSpec(ctx, r.client, newPipelineDef); err != nil {
-				return err
+				return ctrl.Result{}, err
 			}
 			pipelineRollout.Status.MarkDeployed(pipelineRollout.Generation)
 		}
+		if pipelineNeedsToUpdate && upgradeStrategyType == apiv1.UpgradeStrategyRecreate && !awaitingWindow {
+			// the Pipeline will be created again with the new spec once it's gone
+			numaLogger.Infof("deleting Pipeline %s/%s in order to recreate it", existingPipelineDef.Namespace, existingPipelineDef.Name)
+			if err := kubernetes.DeleteResource(ctx, r.client, existingPipelineDef); err != nil {
+				return ctrl.Result{}, err
+			}
+			pipelineRollout.Status.MarkWaitingFor("Pipeline %s to be deleted so that it can be recreated", existingPipelineDef.Name)
+			requeue = true
+		}
 	}
 	// clean up recyclable pipelines
 	err = garbageCollectChildren(ctx, pipelineRollout, r, r.client)
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
 
 	if pipelineNeedsToUpdate {
 		r.customMetrics.ReconciliationDuration.WithLabelValues(ControllerPipelineRollout, "update").Observe(time.Since(syncStartTime).Seconds())
 	}
-	return nil
+	if requeue {
+		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+	}
+	if awaitingWindow && inProgressStrategy == apiv1.UpgradeStrategyNoOp {
+		// come back when the maintenance window opens
+		return ctrl.Result{RequeueAfter: untilWindow}, nil
+	}
+	return ctrl.Result{}, nil
 }
 func pipelineObservedGenerationCurrent(generation int64, observedGeneration int64) bool {
 	return generation <= observedGeneration
@@ -666,7 +792,7 @@ func (r *PipelineRolloutReconciler) updatePauseMetric(pipelineRollout *apiv1.Pip
 	_ = json.Unmarshal(pipelineRollout.Spec.Pipeline.Spec.Raw, &pipelineSpec)
 
 	timeElapsed := time.Since(pipelineRollout.Status.PauseStatus.LastPauseBeginTime.Time)
-	if r.isSpecBasedPause(pipelineSpec) {
+	if r.isSpecBasedPause(pipelineSpec) || pipelineRollout.Spec.Paused {
 		r.customMetrics.PipelinePausedSeconds.WithLabelValues(pipelineRollout.Namespace, pipelineRollout.Name, "user_pause").Set(timeElapsed.Seconds())
 	} else {
 		r.customMetrics.PipelinePausedSeconds.WithLabelValues(pipelineRollout.Namespace, pipelineRollout.Name, "system_pause").Set(timeElapsed.Seconds())
@@ -696,7 +822,7 @@ func (r *PipelineRolloutReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	// Watch PipelineRollouts
 	if err := controller.Watch(source.Kind(mgr.GetCache(), &apiv1.PipelineRollout{},
-		&handler.TypedEnqueueRequestForObject[*apiv1.PipelineRollout]{}, predicate.TypedGenerationChangedPredicate[*apiv1.PipelineRollout]{})); err != nil {
+		&handler.TypedEnqueueRequestForObject[*apiv1.PipelineRollout]{}, rolloutChangedPredicate[*apiv1.PipelineRollout]())); err != nil {
 		return fmt.Errorf("failed to watch PipelineRollouts: %v", err)
 	}
 
@@ -792,15 +918,35 @@ func (r *PipelineRolloutReconciler) makeRunningPipelineDefinition(
 	}
 	metadata.Labels[common.LabelKeyUpgradeState] = string(common.LabelValueUpgradePromoted)
 
-	return r.makePipelineDefinition(pipelineRollout, pipelineName, metadata)
+	pipelineDef, err := r.makePipelineDefinition(ctx, pipelineRollout, pipelineName, metadata)
+	if err != nil {
+		return nil, err
+	}
+	// the user's pause is applied the same way as if the Pipeline spec itself said to pause
+	if pipelineRollout.Spec.Paused {
+		if err := withDesiredPhase(pipelineDef, string(numaflowv1.PipelinePhasePaused)); err != nil {
+			return nil, err
+		}
+	}
+	return pipelineDef, nil
 }
 
 func (r *PipelineRolloutReconciler) makePipelineDefinition(
+	ctx context.Context,
 	pipelineRollout *apiv1.PipelineRollout,
 	pipelineName string,
 	metadata apiv1.Metadata,
 ) (*kubernetes.GenericObject, error) {
 
+	pipelineSpec, err := r.getPipelineSpecWithPromotedISBService(ctx, pipelineRollout)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := withNumaflowControllerInstanceID(ctx, r.client, pipelineRollout.Namespace, apiv1.PipelineRolloutGroupVersionKind.Kind, pipelineRollout.Name, metadata.Annotations)
+	if err != nil {
+		return nil, err
+	}
+
 	return &kubernetes.GenericObject{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       common.NumaflowPipelineKind,
@@ -810,13 +956,54 @@ func (r *PipelineRolloutReconciler) makePipelineDefinition(
 			Name:            pipelineName,
 			Namespace:       pipelineRollout.Namespace,
 			Labels:          metadata.Labels,
-			Annotations:     metadata.Annotations,
+			Annotations:     annotations,
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(pipelineRollout.GetObjectMeta(), apiv1.PipelineRolloutGroupVersionKind)},
 		},
-		Spec: pipelineRollout.Spec.Pipeline.Spec,
+		Spec: pipelineSpec,
 	}, nil
 }
 
+// get the Pipeline spec of the PipelineRollout, running on the promoted InterStepBufferService of its ISBServiceRollout
+// (which isn't the one named after the ISBServiceRollout once that's been upgraded with the Progressive strategy)
+func (r *PipelineRolloutReconciler) getPipelineSpecWithPromotedISBService(ctx context.Context, pipelineRollout *apiv1.PipelineRollout) (runtime.RawExtension, error) {
+	var pipelineSpec PipelineSpec
+	if err := json.Unmarshal(pipelineRollout.Spec.Pipeline.Spec.Raw, &pipelineSpec); err != nil {
+		return runtime.RawExtension{}, fmt.Errorf("failed to convert PipelineRollout spec %q into PipelineSpec type, err=%v", string(pipelineRollout.Spec.Pipeline.Spec.Raw), err)
+	}
+	isbServiceRolloutName := pipelineSpec.getISBSvcName()
+	isbsvcName, err := getPromotedISBServiceName(ctx, r.client, pipelineRollout.Namespace, isbServiceRolloutName)
+	if err != nil {
+		return runtime.RawExtension{}, err
+	}
+	if isbsvcName == isbServiceRolloutName {
+		return pipelineRollout.Spec.Pipeline.Spec, nil
+	}
+
+	specAsMap := make(map[string]interface{})
+	if err := json.Unmarshal(pipelineRollout.Spec.Pipeline.Spec.Raw, &specAsMap); err != nil {
+		return runtime.RawExtension{}, err
+	}
+	specAsMap["interStepBufferServiceName"] = isbsvcName
+	rawSpec, err := json.Marshal(specAsMap)
+	if err != nil {
+		return runtime.RawExtension{}, err
+	}
+	return runtime.RawExtension{Raw: rawSpec}, nil
+}
+
+// get the name of the ISBServiceRollout whose InterStepBufferService the Pipeline runs on
+// (the Pipeline's spec names the InterStepBufferService itself, which may not be named after the ISBServiceRollout)
+func getPipelineISBServiceRolloutName(pipeline *kubernetes.GenericObject) (string, error) {
+	if isbServiceRolloutName, found := pipeline.Labels[common.LabelKeyISBServiceNameForPipeline]; found {
+		return isbServiceRolloutName, nil
+	}
+	var pipelineSpec PipelineSpec
+	if err := json.Unmarshal(pipeline.Spec.Raw, &pipelineSpec); err != nil {
+		return "", fmt.Errorf("failed to convert Pipeline spec %q into PipelineSpec type, err=%v", string(pipeline.Spec.Raw), err)
+	}
+	return pipelineSpec.getISBSvcName(), nil
+}
+
 // the following functions enable PipelineRolloutReconciler to implement progressiveController interface
 func (r *PipelineRolloutReconciler) listChildren(ctx context.Context, rolloutObject RolloutObject, labelSelector string, fieldSelector string) ([]*kubernetes.GenericObject, error) {
 	pipelineRollout := rolloutObject.(*apiv1.PipelineRollout)
@@ -825,13 +1012,13 @@ func (r *PipelineRolloutReconciler) listChildren(ctx context.Context, rolloutObj
 		pipelineRollout.Namespace, labelSelector, fieldSelector)
 }
 
-func (r *PipelineRolloutReconciler) createBaseChildDefinition(rolloutObject RolloutObject, name string) (*kubernetes.GenericObject, error) {
+func (r *PipelineRolloutReconciler) createBaseChildDefinition(ctx context.Context, rolloutObject RolloutObject, name string) (*kubernetes.GenericObject, error) {
 	pipelineRollout := rolloutObject.(*apiv1.PipelineRollout)
 	metadata, err := getBasePipelineMetadata(pipelineRollout)
 	if err != nil {
 		return nil, err
 	}
-	return r.makePipelineDefinition(pipelineRollout, name, metadata)
+	return r.makePipelineDefinition(ctx, pipelineRollout, name, metadata)
 }
 
 func (r *PipelineRolloutReconciler) getCurrentChildCount(rolloutObject RolloutObject) (int32, bool) {
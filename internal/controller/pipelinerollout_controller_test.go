@@ -37,8 +37,10 @@ import (
 	"k8s.io/client-go/tools/record"
 
 	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	"github.com/numaproj/numaplane/internal/analysis"
 	"github.com/numaproj/numaplane/internal/common"
 	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/util"
 	"github.com/numaproj/numaplane/internal/util/kubernetes"
 	"github.com/numaproj/numaplane/internal/util/metrics"
 	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
@@ -784,9 +786,13 @@ func Test_processExistingPipeline_PPND(t *testing.T) {
 		initialInProgressStrategy      apiv1.UpgradeStrategy
 		numaflowControllerPauseRequest *bool
 		isbServicePauseRequest         *bool
+		maintenanceWindows             []apiv1.MaintenanceWindow
+		paused                         bool
 
 		expectedInProgressStrategy apiv1.UpgradeStrategy
 		expectedRolloutPhase       apiv1.Phase
+		expectedAwaitingWindow     bool
+		expectedPauseRequesters    []string
 		// require these Conditions to be set (note that in real life, previous reconciliations may have set other Conditions from before which are still present)
 		expectedPipelineSpecResult func(numaflowv1.PipelineSpec) bool
 	}{
@@ -832,6 +838,23 @@ func Test_processExistingPipeline_PPND(t *testing.T) {
 				return reflect.DeepEqual(pipelineWithDesiredPhase(pipelineSpec, numaflowv1.PipelinePhasePaused), spec)
 			},
 		},
+		{
+			name:                           "spec difference outside of maintenance window",
+			newPipelineSpec:                pipelineSpecWithTopologyChange,
+			existingPipelineDef:            *createDefaultPipeline(numaflowv1.PipelinePhaseRunning),
+			initialRolloutPhase:            apiv1.PhaseDeployed,
+			initialInProgressStrategy:      apiv1.UpgradeStrategyNoOp,
+			numaflowControllerPauseRequest: &falseValue,
+			isbServicePauseRequest:         &falseValue,
+			// only opens on February 29th
+			maintenanceWindows:         []apiv1.MaintenanceWindow{{Schedule: "0 0 29 2 *", Duration: metav1.Duration{Duration: time.Minute}}},
+			expectedInProgressStrategy: apiv1.UpgradeStrategyNoOp,
+			expectedRolloutPhase:       apiv1.PhasePending,
+			expectedAwaitingWindow:     true,
+			expectedPipelineSpecResult: func(spec numaflowv1.PipelineSpec) bool {
+				return reflect.DeepEqual(pipelineSpec, spec)
+			},
+		},
 		{
 			name:                           "external pause request at the same time as a DirectApply change",
 			newPipelineSpec:                pipelineSpecWithWatermarkDisabled,
@@ -842,6 +865,7 @@ func Test_processExistingPipeline_PPND(t *testing.T) {
 			isbServicePauseRequest:         &falseValue,
 			expectedInProgressStrategy:     apiv1.UpgradeStrategyPPND,
 			expectedRolloutPhase:           apiv1.PhasePending,
+			expectedPauseRequesters:        []string{"NumaflowControllerRollout"},
 			expectedPipelineSpecResult: func(spec numaflowv1.PipelineSpec) bool {
 				return reflect.DeepEqual(pipelineWithDesiredPhase(pipelineSpec, numaflowv1.PipelinePhasePaused), spec)
 			},
@@ -877,6 +901,41 @@ func Test_processExistingPipeline_PPND(t *testing.T) {
 				return reflect.DeepEqual(pipelineWithDesiredPhase(pipelineSpec, numaflowv1.PipelinePhaseRunning), spec)
 			},
 		},
+		{
+			name:                           "user sets paused=true",
+			newPipelineSpec:                pipelineSpec,
+			existingPipelineDef:            *createDefaultPipeline(numaflowv1.PipelinePhaseRunning),
+			initialRolloutPhase:            apiv1.PhaseDeployed,
+			initialInProgressStrategy:      apiv1.UpgradeStrategyNoOp,
+			numaflowControllerPauseRequest: &falseValue,
+			isbServicePauseRequest:         &falseValue,
+			paused:                         true,
+			expectedInProgressStrategy:     apiv1.UpgradeStrategyNoOp,
+			expectedRolloutPhase:           apiv1.PhaseDeployed,
+			expectedPauseRequesters:        []string{"user"},
+			expectedPipelineSpecResult: func(spec numaflowv1.PipelineSpec) bool {
+				return reflect.DeepEqual(pipelineWithDesiredPhase(pipelineSpec, numaflowv1.PipelinePhasePaused), spec)
+			},
+		},
+		{
+			name:            "user sets paused=false while ISBService still requires pause",
+			newPipelineSpec: pipelineSpec,
+			existingPipelineDef: *createPipelineOfSpec(
+				pipelineWithDesiredPhase(pipelineSpec, numaflowv1.PipelinePhasePaused),
+				defaultPipelineName, numaflowv1.PipelinePhasePaused, numaflowv1.Status{},
+				false, map[string]string{}),
+			initialRolloutPhase:            apiv1.PhaseDeployed,
+			initialInProgressStrategy:      apiv1.UpgradeStrategyNoOp,
+			numaflowControllerPauseRequest: &falseValue,
+			isbServicePauseRequest:         &trueValue,
+			paused:                         false,
+			expectedInProgressStrategy:     apiv1.UpgradeStrategyPPND,
+			expectedRolloutPhase:           apiv1.PhaseDeployed,
+			expectedPauseRequesters:        []string{"ISBServiceRollout/my-isbsvc"},
+			expectedPipelineSpecResult: func(spec numaflowv1.PipelineSpec) bool {
+				return reflect.DeepEqual(pipelineWithDesiredPhase(pipelineSpec, numaflowv1.PipelinePhasePaused), spec)
+			},
+		},
 		{
 			name:                           "PPND in progress, spec not yet applied, pipeline not paused",
 			newPipelineSpec:                pipelineSpecWithTopologyChange,
@@ -919,6 +978,7 @@ func Test_processExistingPipeline_PPND(t *testing.T) {
 			isbServicePauseRequest:         &trueValue,
 			expectedInProgressStrategy:     apiv1.UpgradeStrategyNoOp,
 			expectedRolloutPhase:           apiv1.PhaseDeployed,
+			expectedPauseRequesters:        []string{"NumaflowControllerRollout", "ISBServiceRollout/my-isbsvc"},
 			expectedPipelineSpecResult: func(spec numaflowv1.PipelineSpec) bool {
 				return reflect.DeepEqual(pipelineSpecWithTopologyChange, spec)
 			},
@@ -941,6 +1001,10 @@ func Test_processExistingPipeline_PPND(t *testing.T) {
 			}
 
 			rollout := createPipelineRollout(tc.newPipelineSpec, tc.pipelineRolloutAnnotations, map[string]string{})
+			if tc.maintenanceWindows != nil {
+				rollout.Spec.Strategy = &apiv1.PipelineTypeRolloutStrategy{RolloutStrategy: apiv1.RolloutStrategy{MaintenanceWindows: tc.maintenanceWindows}}
+			}
+			rollout.Spec.Paused = tc.paused
 			_ = numaplaneClient.Delete(ctx, rollout)
 
 			rollout.Status.Phase = tc.initialRolloutPhase
@@ -968,7 +1032,7 @@ func Test_processExistingPipeline_PPND(t *testing.T) {
 				GetPauseModule().pauseRequests[GetPauseModule().getISBServiceKey(defaultNamespace, "my-isbsvc")] = tc.isbServicePauseRequest
 			}
 
-			_, _, err = r.reconcile(context.Background(), rollout, time.Now())
+			result, _, err := r.reconcile(context.Background(), rollout, time.Now())
 			assert.NoError(t, err)
 
 			////// check results:
@@ -976,6 +1040,26 @@ func Test_processExistingPipeline_PPND(t *testing.T) {
 			assert.Equal(t, tc.expectedRolloutPhase, rollout.Status.Phase)
 			// Check In-Progress Strategy
 			assert.Equal(t, tc.expectedInProgressStrategy, rollout.Status.UpgradeInProgress)
+			// Check whether the update is held until the maintenance window
+			awaitingWindowCondition := rollout.Status.GetCondition(apiv1.ConditionAwaitingMaintenanceWindow)
+			if tc.expectedAwaitingWindow {
+				if assert.NotNil(t, awaitingWindowCondition) {
+					assert.Equal(t, metav1.ConditionTrue, awaitingWindowCondition.Status)
+				}
+				assert.Positive(t, result.RequeueAfter)
+			} else {
+				assert.Nil(t, awaitingWindowCondition)
+			}
+			// Check the active pause requests reported
+			pauseRequesters := []string{}
+			for _, pauseRequest := range rollout.Status.PauseRequests {
+				pauseRequesters = append(pauseRequesters, pauseRequest.Requester)
+				assert.False(t, pauseRequest.Since.IsZero())
+			}
+			if tc.expectedPauseRequesters == nil {
+				tc.expectedPauseRequesters = []string{}
+			}
+			assert.Equal(t, tc.expectedPauseRequesters, pauseRequesters)
 
 			// Check Pipeline spec
 			resultPipeline, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Get(ctx, defaultPipelineName, metav1.GetOptions{})
@@ -1014,6 +1098,30 @@ func Test_processExistingPipeline_Progressive(t *testing.T) {
 	progressiveUpgradeStrategy := apiv1.UpgradeStrategyProgressive
 	paused := numaflowv1.PipelinePhasePaused
 
+	pipelineSpecWithTopologyChangeRaw, _ := json.Marshal(pipelineSpecWithTopologyChange)
+	pipelineSpecWithTopologyChangeHash, err := util.JSONHash(pipelineSpecWithTopologyChangeRaw)
+	assert.NoError(t, err)
+
+	errorRateQuery := fmt.Sprintf(`sum(rate(pipeline_errors_total{pipeline="%s"}[600s]))`, newPipelineName)
+	analysisStrategy := &apiv1.PipelineTypeRolloutStrategy{
+		Progressive: apiv1.ProgressiveStrategy{
+			Analysis: &apiv1.Analysis{
+				Prometheus: apiv1.PrometheusProvider{Address: "http://prometheus:9090"},
+				Window:     &metav1.Duration{Duration: 10 * time.Minute},
+				Metrics: []apiv1.AnalysisMetric{
+					{
+						Name:      "error-rate",
+						Query:     `sum(rate(pipeline_errors_total{pipeline="{{.Name}}"}[{{.Window}}]))`,
+						Operator:  apiv1.AnalysisOperatorLessThan,
+						Threshold: "0.1",
+					},
+				},
+			},
+		},
+	}
+	analysisWithRollbackStrategy := analysisStrategy.DeepCopy()
+	analysisWithRollbackStrategy.Progressive.AutoRollback = true
+
 	testCases := []struct {
 		name                       string
 		newPipelineSpec            numaflowv1.PipelineSpec
@@ -1021,11 +1129,17 @@ func Test_processExistingPipeline_Progressive(t *testing.T) {
 		existingUpgradePipelineDef *numaflowv1.Pipeline
 		initialRolloutPhase        apiv1.Phase
 		initialInProgressStrategy  *apiv1.UpgradeStrategy
+		rolloutStrategy            *apiv1.PipelineTypeRolloutStrategy
+		metricsProvider            *analysis.FakeMetricsProvider
+		initialFailedSpecHash      string
 
 		expectedInProgressStrategy           apiv1.UpgradeStrategy
 		expectedRolloutPhase                 apiv1.Phase
 		expectedExistingPipelineDeleted      bool
 		expectedExistingPipelineDesiredPhase *numaflowv1.PipelinePhase
+		expectedAnalysisPhase                apiv1.AnalysisPhase
+		expectedFailedSpecHash               string
+		// if nil, the new Pipeline is expected not to exist
 		// require these Conditions to be set (note that in real life, previous reconciliations may have set other Conditions from before which are still present)
 		expectedPipelineSpecResult func(numaflowv1.PipelineSpec) bool
 	}{
@@ -1088,6 +1202,213 @@ func Test_processExistingPipeline_Progressive(t *testing.T) {
 				return reflect.DeepEqual(pipelineSpecWithTopologyChange, spec)
 			},
 		},
+		{
+			name:            "Progressive waits for Analysis to complete",
+			newPipelineSpec: pipelineSpecWithTopologyChange,
+			existingPipelineDef: *createPipeline(
+				numaflowv1.PipelinePhaseRunning,
+				numaflowv1.Status{},
+				false,
+				map[string]string{
+					common.LabelKeyUpgradeState:  string(common.LabelValueUpgradePromoted),
+					common.LabelKeyParentRollout: defaultPipelineRolloutName,
+				}),
+			existingUpgradePipelineDef: createPipelineOfSpec(
+				pipelineSpecWithTopologyChange, newPipelineName,
+				numaflowv1.PipelinePhaseRunning,
+				numaflowv1.Status{
+					Conditions: []metav1.Condition{
+						{
+							Type:   string(numaflowv1.PipelineConditionDaemonServiceHealthy),
+							Status: metav1.ConditionTrue,
+						},
+					},
+				},
+				false,
+				map[string]string{
+					common.LabelKeyUpgradeState:  string(common.LabelValueUpgradeInProgress),
+					common.LabelKeyParentRollout: defaultPipelineRolloutName,
+				}),
+			initialRolloutPhase:                  apiv1.PhasePending,
+			initialInProgressStrategy:            &progressiveUpgradeStrategy,
+			rolloutStrategy:                      analysisStrategy,
+			metricsProvider:                      &analysis.FakeMetricsProvider{Values: map[string]float64{errorRateQuery: 0.01}},
+			expectedInProgressStrategy:           apiv1.UpgradeStrategyProgressive,
+			expectedRolloutPhase:                 apiv1.PhasePending,
+			expectedExistingPipelineDeleted:      false,
+			expectedExistingPipelineDesiredPhase: nil,
+			expectedAnalysisPhase:                apiv1.AnalysisPhaseRunning,
+			expectedPipelineSpecResult: func(spec numaflowv1.PipelineSpec) bool {
+				return reflect.DeepEqual(pipelineSpecWithTopologyChange, spec)
+			},
+		},
+		{
+			name:            "Progressive Analysis fails",
+			newPipelineSpec: pipelineSpecWithTopologyChange,
+			existingPipelineDef: *createPipeline(
+				numaflowv1.PipelinePhaseRunning,
+				numaflowv1.Status{},
+				false,
+				map[string]string{
+					common.LabelKeyUpgradeState:  string(common.LabelValueUpgradePromoted),
+					common.LabelKeyParentRollout: defaultPipelineRolloutName,
+				}),
+			existingUpgradePipelineDef: createPipelineOfSpec(
+				pipelineSpecWithTopologyChange, newPipelineName,
+				numaflowv1.PipelinePhaseRunning,
+				numaflowv1.Status{
+					Conditions: []metav1.Condition{
+						{
+							Type:   string(numaflowv1.PipelineConditionDaemonServiceHealthy),
+							Status: metav1.ConditionTrue,
+						},
+					},
+				},
+				false,
+				map[string]string{
+					common.LabelKeyUpgradeState:  string(common.LabelValueUpgradeInProgress),
+					common.LabelKeyParentRollout: defaultPipelineRolloutName,
+				}),
+			initialRolloutPhase:                  apiv1.PhasePending,
+			initialInProgressStrategy:            &progressiveUpgradeStrategy,
+			rolloutStrategy:                      analysisStrategy,
+			metricsProvider:                      &analysis.FakeMetricsProvider{Values: map[string]float64{errorRateQuery: 0.5}},
+			expectedInProgressStrategy:           apiv1.UpgradeStrategyProgressive,
+			expectedRolloutPhase:                 apiv1.PhasePending,
+			expectedExistingPipelineDeleted:      false,
+			expectedExistingPipelineDesiredPhase: nil,
+			expectedAnalysisPhase:                apiv1.AnalysisPhaseFailed,
+			expectedPipelineSpecResult: func(spec numaflowv1.PipelineSpec) bool {
+				return reflect.DeepEqual(pipelineSpecWithTopologyChange, spec)
+			},
+		},
+		{
+			name:            "Progressive Analysis fails with AutoRollback",
+			newPipelineSpec: pipelineSpecWithTopologyChange,
+			existingPipelineDef: *createPipeline(
+				numaflowv1.PipelinePhaseRunning,
+				numaflowv1.Status{},
+				false,
+				map[string]string{
+					common.LabelKeyUpgradeState:  string(common.LabelValueUpgradePromoted),
+					common.LabelKeyParentRollout: defaultPipelineRolloutName,
+				}),
+			existingUpgradePipelineDef: createPipelineOfSpec(
+				pipelineSpecWithTopologyChange, newPipelineName,
+				numaflowv1.PipelinePhaseRunning,
+				numaflowv1.Status{
+					Conditions: []metav1.Condition{
+						{
+							Type:   string(numaflowv1.PipelineConditionDaemonServiceHealthy),
+							Status: metav1.ConditionTrue,
+						},
+					},
+				},
+				false,
+				map[string]string{
+					common.LabelKeyUpgradeState:  string(common.LabelValueUpgradeInProgress),
+					common.LabelKeyParentRollout: defaultPipelineRolloutName,
+				}),
+			initialRolloutPhase:                  apiv1.PhasePending,
+			initialInProgressStrategy:            &progressiveUpgradeStrategy,
+			rolloutStrategy:                      analysisWithRollbackStrategy,
+			metricsProvider:                      &analysis.FakeMetricsProvider{Values: map[string]float64{errorRateQuery: 0.5}},
+			expectedInProgressStrategy:           apiv1.UpgradeStrategyNoOp,
+			expectedRolloutPhase:                 apiv1.PhasePending,
+			expectedExistingPipelineDeleted:      false,
+			expectedExistingPipelineDesiredPhase: nil,
+			expectedAnalysisPhase:                apiv1.AnalysisPhaseFailed,
+			expectedFailedSpecHash:               pipelineSpecWithTopologyChangeHash,
+			expectedPipelineSpecResult: func(spec numaflowv1.PipelineSpec) bool {
+				// upgrading Pipeline is drained
+				return reflect.DeepEqual(pipelineWithDesiredPhase(pipelineSpecWithTopologyChange, numaflowv1.PipelinePhasePaused), spec)
+			},
+		},
+		{
+			name:            "Progressive waits without AutoRollback when upgrading Pipeline fails",
+			newPipelineSpec: pipelineSpecWithTopologyChange,
+			existingPipelineDef: *createPipeline(
+				numaflowv1.PipelinePhaseRunning,
+				numaflowv1.Status{},
+				false,
+				map[string]string{
+					common.LabelKeyUpgradeState:  string(common.LabelValueUpgradePromoted),
+					common.LabelKeyParentRollout: defaultPipelineRolloutName,
+				}),
+			existingUpgradePipelineDef: createPipelineOfSpec(
+				pipelineSpecWithTopologyChange, newPipelineName,
+				numaflowv1.PipelinePhaseFailed,
+				numaflowv1.Status{},
+				false,
+				map[string]string{
+					common.LabelKeyUpgradeState:  string(common.LabelValueUpgradeInProgress),
+					common.LabelKeyParentRollout: defaultPipelineRolloutName,
+				}),
+			initialRolloutPhase:                  apiv1.PhasePending,
+			initialInProgressStrategy:            &progressiveUpgradeStrategy,
+			expectedInProgressStrategy:           apiv1.UpgradeStrategyProgressive,
+			expectedRolloutPhase:                 apiv1.PhasePending,
+			expectedExistingPipelineDeleted:      false,
+			expectedExistingPipelineDesiredPhase: nil,
+			expectedPipelineSpecResult: func(spec numaflowv1.PipelineSpec) bool {
+				// upgrading Pipeline is left as it is
+				return reflect.DeepEqual(pipelineSpecWithTopologyChange, spec)
+			},
+		},
+		{
+			name:            "Fix applied to upgrading Pipeline which failed without AutoRollback",
+			newPipelineSpec: pipelineSpecWithTopologyChange,
+			existingPipelineDef: *createPipeline(
+				numaflowv1.PipelinePhaseRunning,
+				numaflowv1.Status{},
+				false,
+				map[string]string{
+					common.LabelKeyUpgradeState:  string(common.LabelValueUpgradePromoted),
+					common.LabelKeyParentRollout: defaultPipelineRolloutName,
+				}),
+			existingUpgradePipelineDef: createPipelineOfSpec(
+				pipelineSpec, newPipelineName,
+				numaflowv1.PipelinePhaseFailed,
+				numaflowv1.Status{},
+				false,
+				map[string]string{
+					common.LabelKeyUpgradeState:  string(common.LabelValueUpgradeInProgress),
+					common.LabelKeyParentRollout: defaultPipelineRolloutName,
+				}),
+			initialRolloutPhase:                  apiv1.PhasePending,
+			initialInProgressStrategy:            &progressiveUpgradeStrategy,
+			expectedInProgressStrategy:           apiv1.UpgradeStrategyProgressive,
+			expectedRolloutPhase:                 apiv1.PhasePending,
+			expectedExistingPipelineDeleted:      false,
+			expectedExistingPipelineDesiredPhase: nil,
+			expectedPipelineSpecResult: func(spec numaflowv1.PipelineSpec) bool {
+				// upgrading Pipeline is updated with the fix
+				return reflect.DeepEqual(pipelineSpecWithTopologyChange, spec)
+			},
+		},
+		{
+			name:            "Spec which previously failed is not retried",
+			newPipelineSpec: pipelineSpecWithTopologyChange,
+			existingPipelineDef: *createPipeline(
+				numaflowv1.PipelinePhaseRunning,
+				numaflowv1.Status{},
+				false,
+				map[string]string{
+					common.LabelKeyUpgradeState:  string(common.LabelValueUpgradePromoted),
+					common.LabelKeyParentRollout: defaultPipelineRolloutName,
+				}),
+			existingUpgradePipelineDef:           nil,
+			initialRolloutPhase:                  apiv1.PhasePending,
+			initialInProgressStrategy:            nil,
+			rolloutStrategy:                      analysisWithRollbackStrategy,
+			initialFailedSpecHash:                pipelineSpecWithTopologyChangeHash,
+			expectedInProgressStrategy:           apiv1.UpgradeStrategyNoOp,
+			expectedRolloutPhase:                 apiv1.PhasePending,
+			expectedExistingPipelineDeleted:      false,
+			expectedExistingPipelineDesiredPhase: nil,
+			expectedFailedSpecHash:               pipelineSpecWithTopologyChangeHash,
+			expectedPipelineSpecResult:           nil,
+		},
 		{
 			name:            "Clean up after progressive upgrade",
 			newPipelineSpec: pipelineSpecWithTopologyChange,
@@ -1164,8 +1485,17 @@ func Test_processExistingPipeline_Progressive(t *testing.T) {
 
 			rollout := createPipelineRollout(tc.newPipelineSpec, map[string]string{}, map[string]string{})
 			_ = numaplaneClient.Delete(ctx, rollout)
+			rollout.Spec.Strategy = tc.rolloutStrategy
+			if tc.metricsProvider != nil {
+				originalNewMetricsProvider := newMetricsProvider
+				t.Cleanup(func() { newMetricsProvider = originalNewMetricsProvider })
+				newMetricsProvider = func(*apiv1.Analysis) (analysis.MetricsProvider, error) {
+					return tc.metricsProvider, nil
+				}
+			}
 
 			rollout.Status.Phase = tc.initialRolloutPhase
+			rollout.Status.ProgressiveStatus.FailedSpecHash = tc.initialFailedSpecHash
 			if rollout.Status.NameCount == nil {
 				rollout.Status.NameCount = new(int32)
 				*rollout.Status.NameCount++
@@ -1220,12 +1550,24 @@ func Test_processExistingPipeline_Progressive(t *testing.T) {
 			assert.Equal(t, tc.expectedRolloutPhase, rollout.Status.Phase)
 			// Check In-Progress Strategy
 			assert.Equal(t, tc.expectedInProgressStrategy, rollout.Status.UpgradeInProgress)
+			// Check Analysis
+			if tc.expectedAnalysisPhase != "" {
+				assert.NotNil(t, rollout.Status.ProgressiveStatus.Analysis)
+				assert.Equal(t, tc.expectedAnalysisPhase, rollout.Status.ProgressiveStatus.Analysis.Phase)
+			}
+
+			// Check failed spec
+			assert.Equal(t, tc.expectedFailedSpecHash, rollout.Status.ProgressiveStatus.FailedSpecHash)
 
 			// Check the new Pipeline spec
 			resultPipeline, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Get(ctx, newPipelineName, metav1.GetOptions{})
-			assert.NoError(t, err)
-			assert.NotNil(t, resultPipeline)
-			assert.True(t, tc.expectedPipelineSpecResult(resultPipeline.Spec), "result spec", fmt.Sprint(resultPipeline.Spec))
+			if tc.expectedPipelineSpecResult == nil {
+				assert.True(t, errors.IsNotFound(err))
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, resultPipeline)
+				assert.True(t, tc.expectedPipelineSpecResult(resultPipeline.Spec), "result spec", fmt.Sprint(resultPipeline.Spec))
+			}
 
 			// Check the existing Pipeline state
 			resultExistingPipeline, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Get(ctx, defaultPipelineName, metav1.GetOptions{})
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// A PipelineRollout's spec.dependsOn names the PipelineRollouts which must be upgraded before it: for example, a Pipeline
+// writing to a topic may depend on the Pipeline which reads from it, so that the reader is ready for the new data first.
+
+// find a cycle of dependencies which includes this PipelineRollout, if there is one
+// return the names of the PipelineRollouts in the cycle, starting and ending with this one, or nil if there's no cycle
+// (PipelineRollouts which don't exist are treated as having no dependencies)
+func findDependencyCycle(ctx context.Context, c client.Client, pipelineRollout *apiv1.PipelineRollout) ([]string, error) {
+	if len(pipelineRollout.Spec.DependsOn) == 0 {
+		return nil, nil
+	}
+
+	dependencies := map[string][]string{pipelineRollout.Name: pipelineRollout.Spec.DependsOn}
+	getDependencies := func(name string) ([]string, error) {
+		if dependsOn, found := dependencies[name]; found {
+			return dependsOn, nil
+		}
+		dependency := &apiv1.PipelineRollout{}
+		if err := c.Get(ctx, k8stypes.NamespacedName{Namespace: pipelineRollout.Namespace, Name: name}, dependency); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("error getting PipelineRollout %s/%s: %w", pipelineRollout.Namespace, name, err)
+			}
+		}
+		dependencies[name] = dependency.Spec.DependsOn
+		return dependency.Spec.DependsOn, nil
+	}
+
+	// depth-first search for a path leading back to this PipelineRollout
+	visited := map[string]bool{}
+	var visit func(path []string) ([]string, error)
+	visit = func(path []string) ([]string, error) {
+		dependsOn, err := getDependencies(path[len(path)-1])
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range dependsOn {
+			if name == pipelineRollout.Name {
+				return append(path, name), nil
+			}
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+			cycle, err := visit(append(path, name))
+			if cycle != nil || err != nil {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+	return visit([]string{pipelineRollout.Name})
+}
+
+// determine whether the PipelineRollouts this one depends on have all been upgraded: each must be Deployed with healthy
+// children at its current generation
+// return whether they have, and if not, a description of what's being waited for
+func dependenciesDeployed(ctx context.Context, c client.Client, pipelineRollout *apiv1.PipelineRollout) (bool, string, error) {
+	numaLogger := logger.FromContext(ctx)
+
+	for _, name := range pipelineRollout.Spec.DependsOn {
+		dependency := &apiv1.PipelineRollout{}
+		if err := c.Get(ctx, k8stypes.NamespacedName{Namespace: pipelineRollout.Namespace, Name: name}, dependency); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, fmt.Sprintf("PipelineRollout %s, which it depends on, to be created", name), nil
+			}
+			return false, "", fmt.Errorf("error getting PipelineRollout %s/%s: %w", pipelineRollout.Namespace, name, err)
+		}
+
+		status := dependency.Status.Status
+		healthyCondition := status.GetCondition(apiv1.ConditionChildResourceHealthy)
+		if status.ObservedGeneration != dependency.Generation || status.Phase != apiv1.PhaseDeployed ||
+			healthyCondition == nil || healthyCondition.Status != metav1.ConditionTrue || healthyCondition.ObservedGeneration != dependency.Generation {
+
+			numaLogger.Debugf("PipelineRollout %s, which this one depends on, isn't deployed and healthy yet at generation %d", name, dependency.Generation)
+			return false, fmt.Sprintf("PipelineRollout %s, which it depends on, to be deployed and healthy", name), nil
+		}
+	}
+	return true, "", nil
+}
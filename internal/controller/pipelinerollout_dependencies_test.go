@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	commontest "github.com/numaproj/numaplane/tests/common"
+)
+
+func Test_pipelineRolloutDependencies(t *testing.T) {
+	_, _, numaplaneClient, _, err := commontest.PrepareK8SEnvironment()
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+
+	// "writer" depends on "reader", which depends on "sink"
+	makePipelineRollout := func(name string, dependsOn ...string) *apiv1.PipelineRollout {
+		pipelineRollout := createPipelineRollout(pipelineSpec, map[string]string{}, map[string]string{})
+		pipelineRollout.Name = name
+		pipelineRollout.Spec.DependsOn = dependsOn
+		_ = numaplaneClient.Delete(ctx, &apiv1.PipelineRollout{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: name}})
+		createPipelineRolloutInK8S(ctx, t, numaplaneClient, pipelineRollout)
+		return pipelineRollout
+	}
+	writer := makePipelineRollout("writer", "reader")
+	reader := makePipelineRollout("reader", "sink")
+	defer func() {
+		_ = numaplaneClient.Delete(ctx, writer)
+		_ = numaplaneClient.Delete(ctx, reader)
+	}()
+
+	// no cycle
+	cycle, err := findDependencyCycle(ctx, numaplaneClient, writer)
+	assert.NoError(t, err)
+	assert.Nil(t, cycle)
+
+	// the dependency must be deployed and healthy at its current generation
+	deployed, waitingFor, err := dependenciesDeployed(ctx, numaplaneClient, writer)
+	assert.NoError(t, err)
+	assert.False(t, deployed)
+	assert.Contains(t, waitingFor, "PipelineRollout reader")
+
+	reader.Status.Init(reader.Generation)
+	reader.Status.MarkDeployed(reader.Generation)
+	reader.Status.MarkChildResourcesUnhealthy("Progressing", "Pipeline is progressing", reader.Generation)
+	assert.NoError(t, numaplaneClient.Status().Update(ctx, reader))
+	deployed, _, err = dependenciesDeployed(ctx, numaplaneClient, writer)
+	assert.NoError(t, err)
+	assert.False(t, deployed)
+
+	reader.Status.MarkChildResourcesHealthy(reader.Generation)
+	assert.NoError(t, numaplaneClient.Status().Update(ctx, reader))
+	deployed, _, err = dependenciesDeployed(ctx, numaplaneClient, writer)
+	assert.NoError(t, err)
+	assert.True(t, deployed)
+
+	// a dependency which doesn't exist is waited for
+	deployed, waitingFor, err = dependenciesDeployed(ctx, numaplaneClient, reader)
+	assert.NoError(t, err)
+	assert.False(t, deployed)
+	assert.Contains(t, waitingFor, "PipelineRollout sink")
+
+	// once "sink" depends on "writer", they form a cycle
+	sink := makePipelineRollout("sink", "writer")
+	defer func() {
+		_ = numaplaneClient.Delete(ctx, sink)
+	}()
+	cycle, err = findDependencyCycle(ctx, numaplaneClient, writer)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"writer", "reader", "sink", "writer"}, cycle)
+
+	// as does depending on oneself
+	writer.Spec.DependsOn = []string{"writer"}
+	cycle, err = findDependencyCycle(ctx, numaplaneClient, writer)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"writer", "writer"}, cycle)
+}
@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/usde"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// isDryRun returns whether the Rollout is annotated to have its changes planned rather than made
+func isDryRun(rolloutObject RolloutObject) bool {
+	return rolloutObject.GetObjectMeta().Annotations[common.AnnotationKeyDryRun] == "true"
+}
+
+// planPipelineRollout determines what reconciling the PipelineRollout would do, following the same decisions as
+// reconcile() and processExistingPipeline(), but without modifying anything in Kubernetes
+// return the plan, the existing promoted Pipeline if there is one, and error if any
+func (r *PipelineRolloutReconciler) planPipelineRollout(ctx context.Context, pipelineRollout *apiv1.PipelineRollout) (*apiv1.RolloutPlan, *kubernetes.GenericObject, error) {
+	numaLogger := logger.FromContext(ctx)
+
+	plan := &apiv1.RolloutPlan{ObservedGeneration: pipelineRollout.Generation}
+
+	// unlike getChildName(), don't reserve a name for a Pipeline which doesn't exist yet
+	pipelineName, found, err := findChildName(ctx, pipelineRollout, r, string(common.LabelValueUpgradePromoted))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !found {
+		pipelineName = nextChildName(pipelineRollout, r)
+	}
+	metadata, err := getBasePipelineMetadata(pipelineRollout)
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata.Labels[common.LabelKeyUpgradeState] = string(common.LabelValueUpgradePromoted)
+	newPipelineDef, err := r.makePipelineDefinition(ctx, pipelineRollout, pipelineName, metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	existingPipelineDef, err := kubernetes.GetResource(ctx, r.client, newPipelineDef.GroupVersionKind(),
+		k8stypes.NamespacedName{Name: newPipelineDef.Name, Namespace: newPipelineDef.Namespace})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("error getting Pipeline: %v", err)
+		}
+		plan.ChildrenToCreate = []string{newPipelineDef.Name}
+		plan.Message = fmt.Sprintf("Pipeline %s would be created", newPipelineDef.Name)
+		plan.Diff, err = specDiff(nil, newPipelineDef)
+		if err != nil {
+			return nil, nil, err
+		}
+		return plan, nil, nil
+	}
+
+	if !checkOwnerRef(existingPipelineDef.OwnerReferences, pipelineRollout.UID) {
+		errStr := fmt.Sprintf("Pipeline %s already exists in namespace, not owned by a PipelineRollout", existingPipelineDef.Name)
+		return nil, existingPipelineDef, errors.New(errStr)
+	}
+	newPipelineDef, err = r.merge(existingPipelineDef, newPipelineDef)
+	if err != nil {
+		return nil, existingPipelineDef, err
+	}
+	plan.Diff, err = specDiff(existingPipelineDef, newPipelineDef)
+	if err != nil {
+		return nil, existingPipelineDef, err
+	}
+
+	userPreferredStrategy, err := usde.GetUserStrategy(ctx, newPipelineDef.Namespace, pipelineRollout.GetUserUpgradeStrategy())
+	if err != nil {
+		return nil, existingPipelineDef, err
+	}
+	pipelineNeedsToUpdate, upgradeStrategyType, err := usde.ResourceNeedsUpdating(ctx, newPipelineDef, existingPipelineDef, userPreferredStrategy)
+	if err != nil {
+		return nil, existingPipelineDef, err
+	}
+	guardrailStrategy, err := checkPipelineGuardrails(ctx, r.client, pipelineRollout, existingPipelineDef, newPipelineDef)
+	if err != nil {
+		return nil, existingPipelineDef, err
+	}
+	if guardrailStrategy != apiv1.UpgradeStrategyNoOp {
+		pipelineNeedsToUpdate = true
+		upgradeStrategyType = guardrailStrategy
+	}
+	numaLogger.
+		WithValues("pipelineNeedsToUpdate", pipelineNeedsToUpdate, "upgradeStrategyType", upgradeStrategyType).
+		Debug("Dry-run upgrade decision result")
+
+	// an upgrade already in progress takes precedence over any new decision
+	strategy := r.inProgressStrategyMgr.getStrategy(ctx, pipelineRollout)
+	if strategy != apiv1.UpgradeStrategyNoOp {
+		plan.UpgradeStrategy = strategy
+		plan.Message = fmt.Sprintf("%s upgrade already in progress; it won't continue while in dry-run mode", strategy)
+		return plan, existingPipelineDef, nil
+	}
+
+	if userPreferredStrategy == config.PPNDStrategyID || upgradeStrategyType == apiv1.UpgradeStrategyPPND {
+		ppndRequired, err := r.needPPND(ctx, pipelineRollout, newPipelineDef, upgradeStrategyType == apiv1.UpgradeStrategyPPND)
+		if err != nil {
+			return nil, existingPipelineDef, err
+		}
+		if ppndRequired == nil {
+			plan.Message = "unable to determine whether the Pipeline would be paused until the NumaflowControllerRollout and ISBServiceRollout are reconciled"
+			return plan, existingPipelineDef, nil
+		}
+		if *ppndRequired {
+			strategy = apiv1.UpgradeStrategyPPND
+		}
+	}
+	if strategy == apiv1.UpgradeStrategyNoOp && upgradeStrategyType == apiv1.UpgradeStrategyProgressive {
+		previouslyFailed, err := progressiveUpgradePreviouslyFailed(ctx, pipelineRollout, r)
+		if err != nil {
+			return nil, existingPipelineDef, err
+		}
+		if previouslyFailed {
+			plan.Message = "Progressive upgrade to this spec previously failed and was rolled back, so it wouldn't be retried"
+			return plan, existingPipelineDef, nil
+		}
+		strategy = apiv1.UpgradeStrategyProgressive
+	}
+	if strategy == apiv1.UpgradeStrategyNoOp && pipelineNeedsToUpdate {
+		strategy = upgradeStrategyType
+	}
+	plan.UpgradeStrategy = strategy
+
+	switch strategy {
+	case apiv1.UpgradeStrategyPPND:
+		plan.PausePipelines = true
+		if pipelineNeedsToUpdate {
+			plan.ChildrenToUpdate = []string{existingPipelineDef.Name}
+			plan.Message = fmt.Sprintf("Pipeline %s would be paused, updated, and resumed", existingPipelineDef.Name)
+		} else {
+			plan.Message = fmt.Sprintf("Pipeline %s would be paused", existingPipelineDef.Name)
+		}
+	case apiv1.UpgradeStrategyProgressive:
+		upgradingPipelineName := nextChildName(pipelineRollout, r)
+		plan.ChildrenToCreate = []string{upgradingPipelineName}
+		plan.ChildrenToDelete = []string{existingPipelineDef.Name}
+		plan.Message = fmt.Sprintf("Pipeline %s would be created and, once promoted, would replace Pipeline %s", upgradingPipelineName, existingPipelineDef.Name)
+	case apiv1.UpgradeStrategyApply:
+		plan.ChildrenToUpdate = []string{existingPipelineDef.Name}
+		plan.Message = fmt.Sprintf("Pipeline %s would be updated in place", existingPipelineDef.Name)
+	case apiv1.UpgradeStrategyRecreate:
+		plan.ChildrenToDelete = []string{existingPipelineDef.Name}
+		plan.ChildrenToCreate = []string{existingPipelineDef.Name}
+		plan.Message = fmt.Sprintf("Pipeline %s would be deleted and created again", existingPipelineDef.Name)
+	case apiv1.UpgradeStrategyNoOp:
+		plan.Message = "no changes"
+	default:
+		return nil, existingPipelineDef, fmt.Errorf("unexpected upgrade strategy: %s", strategy)
+	}
+
+	return plan, existingPipelineDef, nil
+}
+
+// specDiff returns a unified diff from the spec of the existing object (if any) to the spec of the new object
+func specDiff(existingDef *kubernetes.GenericObject, newDef *kubernetes.GenericObject) (string, error) {
+	var existingLines []string
+	if existingDef != nil {
+		existingSpec, err := indentedSpec(existingDef)
+		if err != nil {
+			return "", err
+		}
+		existingLines = difflib.SplitLines(existingSpec)
+	}
+	newSpec, err := indentedSpec(newDef)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        existingLines,
+		B:        difflib.SplitLines(newSpec),
+		FromFile: "existing",
+		ToFile:   "desired",
+		Context:  3,
+	})
+}
+
+func indentedSpec(obj *kubernetes.GenericObject) (string, error) {
+	var spec map[string]interface{}
+	if err := json.Unmarshal(obj.Spec.Raw, &spec); err != nil {
+		return "", fmt.Errorf("failed to unmarshal spec of %s/%s: %v", obj.Namespace, obj.Name, err)
+	}
+	// map keys are marshaled in sorted order, so the output is stable
+	indented, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(indented), nil
+}
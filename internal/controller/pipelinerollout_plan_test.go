@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/metrics"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	commontest "github.com/numaproj/numaplane/tests/common"
+)
+
+// In dry-run mode, reconciliation should report what it would do in the Status, without modifying any Pipeline
+func Test_reconcile_DryRun(t *testing.T) {
+	restConfig, numaflowClientSet, numaplaneClient, _, err := commontest.PrepareK8SEnvironment()
+	assert.Nil(t, err)
+	assert.Nil(t, kubernetes.SetDynamicClient(restConfig))
+
+	ctx := context.Background()
+
+	// other tests may call this, but it fails if called more than once
+	if customMetrics == nil {
+		customMetrics = metrics.RegisterCustomMetrics()
+	}
+
+	r := NewPipelineRolloutReconciler(numaplaneClient, scheme.Scheme, customMetrics, record.NewFakeRecorder(64))
+
+	dryRunAnnotations := map[string]string{common.AnnotationKeyDryRun: "true"}
+
+	testCases := []struct {
+		name                   string
+		newPipelineSpec        numaflowv1.PipelineSpec
+		existingPipelineSpec   *numaflowv1.PipelineSpec
+		defaultStrategy        config.USDEUserStrategy
+		expectedStrategy       apiv1.UpgradeStrategy
+		expectedCreate         []string
+		expectedUpdate         []string
+		expectedDelete         []string
+		expectedDiffContaining string
+	}{
+		{
+			name:                   "no existing Pipeline",
+			newPipelineSpec:        pipelineSpec,
+			existingPipelineSpec:   nil,
+			defaultStrategy:        config.PPNDStrategyID,
+			expectedStrategy:       apiv1.UpgradeStrategyNoOp,
+			expectedCreate:         []string{defaultPipelineName},
+			expectedDiffContaining: `+  "interStepBufferServiceName": "my-isbsvc",`,
+		},
+		{
+			name:                   "direct apply",
+			newPipelineSpec:        pipelineSpecWithWatermarkDisabled,
+			existingPipelineSpec:   &pipelineSpec,
+			defaultStrategy:        config.PPNDStrategyID,
+			expectedStrategy:       apiv1.UpgradeStrategyApply,
+			expectedUpdate:         []string{defaultPipelineName},
+			expectedDiffContaining: `+    "disabled": true`,
+		},
+		{
+			name:                   "progressive",
+			newPipelineSpec:        pipelineSpecWithTopologyChange,
+			existingPipelineSpec:   &pipelineSpec,
+			defaultStrategy:        config.ProgressiveStrategyID,
+			expectedStrategy:       apiv1.UpgradeStrategyProgressive,
+			expectedCreate:         []string{newPipelineName},
+			expectedDelete:         []string{defaultPipelineName},
+			expectedDiffContaining: `+      "name": "cat-2",`,
+		},
+		{
+			name:                 "no change",
+			newPipelineSpec:      pipelineSpec,
+			existingPipelineSpec: &pipelineSpec,
+			defaultStrategy:      config.PPNDStrategyID,
+			expectedStrategy:     apiv1.UpgradeStrategyNoOp,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config.GetConfigManagerInstance().UpdateUSDEConfig(config.USDEConfig{
+				DefaultUpgradeStrategy:    tc.defaultStrategy,
+				PipelineSpecExcludedPaths: []string{"watermark", "lifecycle"},
+			})
+
+			// no pause requests from the Numaflow Controller or ISBService
+			GetPauseModule().pauseRequests = map[string]*bool{
+				GetPauseModule().getNumaflowControllerKey(defaultNamespace):      ptr.To(false),
+				GetPauseModule().getISBServiceKey(defaultNamespace, "my-isbsvc"): ptr.To(false),
+			}
+
+			// first delete Pipelines and PipelineRollout in case they already exist, in Kubernetes
+			_ = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+
+			rollout := createPipelineRollout(tc.newPipelineSpec, dryRunAnnotations, map[string]string{})
+			_ = numaplaneClient.Delete(ctx, rollout)
+			rollout.Status.Phase = apiv1.PhaseDeployed
+			rolloutStatus := rollout.Status
+			assert.NoError(t, numaplaneClient.Create(ctx, rollout))
+			rollout.Status = rolloutStatus
+			assert.NoError(t, numaplaneClient.Status().Update(ctx, rollout))
+
+			var existingPipeline *numaflowv1.Pipeline
+			if tc.existingPipelineSpec != nil {
+				rollout.Status.NameCount = new(int32)
+				*rollout.Status.NameCount++
+
+				existingPipeline = createPipelineOfSpec(*tc.existingPipelineSpec, defaultPipelineName, numaflowv1.PipelinePhaseRunning, numaflowv1.Status{}, false, map[string]string{
+					common.LabelKeyUpgradeState:              string(common.LabelValueUpgradePromoted),
+					common.LabelKeyParentRollout:             defaultPipelineRolloutName,
+					common.LabelKeyISBServiceNameForPipeline: "my-isbsvc",
+				})
+				existingPipeline.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(rollout.GetObjectMeta(), apiv1.PipelineRolloutGroupVersionKind)}
+				createPipelineInK8S(ctx, t, numaflowClientSet, existingPipeline)
+			}
+
+			rollout.Status.Init(rollout.Generation)
+			_, _, err = r.reconcile(ctx, rollout, time.Now())
+			assert.NoError(t, err)
+
+			////// check results:
+			plan := rollout.Status.Plan
+			if assert.NotNil(t, plan) {
+				assert.Equal(t, tc.expectedStrategy, plan.UpgradeStrategy)
+				assert.Equal(t, tc.expectedCreate, plan.ChildrenToCreate)
+				assert.Equal(t, tc.expectedUpdate, plan.ChildrenToUpdate)
+				assert.Equal(t, tc.expectedDelete, plan.ChildrenToDelete)
+				if tc.expectedDiffContaining == "" {
+					assert.Empty(t, plan.Diff)
+				} else {
+					assert.Contains(t, plan.Diff, tc.expectedDiffContaining)
+				}
+			}
+
+			// nothing should have changed in Kubernetes
+			pipelineList, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).List(ctx, metav1.ListOptions{})
+			assert.NoError(t, err)
+			if existingPipeline == nil {
+				assert.Len(t, pipelineList.Items, 0)
+			} else if assert.Len(t, pipelineList.Items, 1) {
+				assert.True(t, reflect.DeepEqual(*tc.existingPipelineSpec, pipelineList.Items[0].Spec), "result pipeline spec", fmt.Sprint(pipelineList.Items[0].Spec))
+			}
+			assert.False(t, progressiveUpgradeStarted(rollout))
+		})
+	}
+}
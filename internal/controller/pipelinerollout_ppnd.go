@@ -7,6 +7,8 @@ import (
 	"fmt"
 
 	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
 	"github.com/numaproj/numaplane/internal/common"
 	"github.com/numaproj/numaplane/internal/util/kubernetes"
 	"github.com/numaproj/numaplane/internal/util/logger"
@@ -69,6 +71,8 @@ func (r *PipelineRolloutReconciler) processExistingPipelineWithPPND(ctx context.
 				return false, err
 			}
 			pipelineRollout.Status.MarkDeployed(pipelineRollout.Generation)
+		} else {
+			pipelineRollout.Status.MarkWaitingFor("Pipeline %s to pause before it's updated", existingPipelineDef.Name)
 		}
 	} else {
 		pipelineRollout.Status.MarkDeployed(pipelineRollout.Generation)
@@ -107,7 +111,11 @@ func (r *PipelineRolloutReconciler) shouldBePaused(ctx context.Context, pipeline
 	}
 
 	// Is either Numaflow Controller or ISBService trying to update (such that we need to pause)?
-	externalPauseRequest, pauseRequestsKnown, err := r.checkForPauseRequest(ctx, pipelineRollout, newPipelineSpec.getISBSvcName())
+	isbServiceRolloutName, err := getPipelineISBServiceRolloutName(newPipelineDef)
+	if err != nil {
+		return nil, err
+	}
+	externalPauseRequest, pauseRequestsKnown, err := r.checkForPauseRequest(ctx, pipelineRollout, isbServiceRolloutName)
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +154,11 @@ func (r *PipelineRolloutReconciler) needPPND(ctx context.Context, pipelineRollou
 	}
 
 	// Is either Numaflow Controller or ISBService trying to update (such that we need to pause)?
-	externalPauseRequest, pauseRequestsKnown, err := r.checkForPauseRequest(ctx, pipelineRollout, newPipelineSpec.getISBSvcName())
+	isbServiceRolloutName, err := getPipelineISBServiceRolloutName(newPipelineDef)
+	if err != nil {
+		return nil, err
+	}
+	externalPauseRequest, pauseRequestsKnown, err := r.checkForPauseRequest(ctx, pipelineRollout, isbServiceRolloutName)
 	if err != nil {
 		return nil, err
 	}
@@ -164,6 +176,44 @@ func (r *PipelineRolloutReconciler) needPPND(ctx context.Context, pipelineRollou
 	return &needPPND, nil
 }
 
+// register the PipelineRollout's spec.paused with the PauseModule as one of the Pause Requests for its Pipeline,
+// so that the Pipeline doesn't resume while either the user or the system requires it to be paused
+func (r *PipelineRolloutReconciler) updateUserPauseRequest(ctx context.Context, pipelineRollout *apiv1.PipelineRollout) error {
+	pm := GetPauseModule()
+	updated, err := pm.updatePauseRequest(ctx, pm.getPipelineRolloutKey(pipelineRollout.Namespace, pipelineRollout.Name), pipelineRollout.Spec.Paused)
+	if err != nil {
+		return err
+	}
+	if updated {
+		logger.FromContext(ctx).Infof("user pause request set to %t", pipelineRollout.Spec.Paused)
+	}
+	return nil
+}
+
+// report all of the active Pause Requests for the Pipeline in the PipelineRollout's Status
+func (r *PipelineRolloutReconciler) setPauseRequestsStatus(pipelineRollout *apiv1.PipelineRollout) error {
+	var pipelineSpec PipelineSpec
+	if err := json.Unmarshal(pipelineRollout.Spec.Pipeline.Spec.Raw, &pipelineSpec); err != nil {
+		return fmt.Errorf("failed to convert PipelineRollout spec %q into PipelineSpec type, err=%v", string(pipelineRollout.Spec.Pipeline.Spec.Raw), err)
+	}
+	isbsvcName := pipelineSpec.getISBSvcName()
+
+	pm := GetPauseModule()
+	requesters := []namedPauseRequester{
+		{key: pm.getPipelineRolloutKey(pipelineRollout.Namespace, pipelineRollout.Name), name: userPauseRequester},
+		{key: pm.getNumaflowControllerKeyForPipeline(pipelineRollout.Namespace, getPipelineRolloutInstanceID(pipelineRollout)), name: "NumaflowControllerRollout"},
+		{key: pm.getISBServiceKey(pipelineRollout.Namespace, isbsvcName), name: fmt.Sprintf("ISBServiceRollout/%s", isbsvcName)},
+	}
+	pipelineRolloutKey := k8stypes.NamespacedName{Namespace: pipelineRollout.Namespace, Name: pipelineRollout.Name}
+	pipelineRollout.Status.PauseRequests = pm.getActivePauseRequests(pipelineRolloutKey, requesters, pipelineRollout.Status.PauseRequests)
+	return nil
+}
+
+// get the InstanceID of the Numaflow Controller which manages the PipelineRollout's Pipeline
+func getPipelineRolloutInstanceID(pipelineRollout *apiv1.PipelineRollout) string {
+	return pipelineRollout.Spec.Pipeline.Annotations[common.AnnotationKeyNumaflowInstanceID]
+}
+
 func (r *PipelineRolloutReconciler) isSpecBasedPause(pipelineSpec PipelineSpec) bool {
 	return (pipelineSpec.Lifecycle.DesiredPhase == string(numaflowv1.PipelinePhasePaused) || pipelineSpec.Lifecycle.DesiredPhase == string(numaflowv1.PipelinePhasePausing))
 }
@@ -179,20 +229,22 @@ func (r *PipelineRolloutReconciler) checkForPauseRequest(ctx context.Context, pi
 	pm := GetPauseModule()
 
 	// Is either Numaflow Controller or ISBService trying to update (such that we need to pause)?
-	controllerPauseRequest, found := pm.getPauseRequest(pm.getNumaflowControllerKey(pipelineRollout.Namespace))
+	// (if they're pausing Pipelines in batches, this only applies once our Pipeline's batch is reached)
+	pipelineRolloutKey := k8stypes.NamespacedName{Namespace: pipelineRollout.Namespace, Name: pipelineRollout.Name}
+	// (the Numaflow Controller may be a cluster-scoped one managing this namespace)
+	controllerKey := pm.getNumaflowControllerKeyForPipeline(pipelineRollout.Namespace, getPipelineRolloutInstanceID(pipelineRollout))
+	controllerRequestsPause, found := pm.getPauseRequestForPipeline(controllerKey, pipelineRolloutKey)
 	if !found {
-		numaLogger.Debugf("No pause request found for numaflow controller on namespace %q", pipelineRollout.Namespace)
+		numaLogger.Debugf("No pause request found for numaflow controller %q managing namespace %q", controllerKey, pipelineRollout.Namespace)
 		return false, false, nil
 
 	}
-	controllerRequestsPause := controllerPauseRequest != nil && *controllerPauseRequest
 
-	isbsvcPauseRequest, found := pm.getPauseRequest(pm.getISBServiceKey(pipelineRollout.Namespace, isbsvcName))
+	isbsvcRequestsPause, found := pm.getPauseRequestForPipeline(pm.getISBServiceKey(pipelineRollout.Namespace, isbsvcName), pipelineRolloutKey)
 	if !found {
 		numaLogger.Debugf("No pause request found for isbsvc %q on namespace %q", isbsvcName, pipelineRollout.Namespace)
 		return false, false, nil
 	}
-	isbsvcRequestsPause := (isbsvcPauseRequest != nil && *isbsvcPauseRequest)
 
 	return controllerRequestsPause || isbsvcRequestsPause, true, nil
 }
@@ -5,14 +5,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/numaproj/numaplane/internal/common"
 	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
 )
 
+// name reported in a Rollout's Status for the Pause Request of the Rollout's own spec.paused
+const userPauseRequester = "user"
+
 var (
 	once                sync.Once
 	pauseModuleInstance *PauseModule
@@ -20,16 +27,38 @@ var (
 
 func GetPauseModule() *PauseModule {
 	once.Do(func() {
-		pauseModuleInstance = &PauseModule{pauseRequests: make(map[string]*bool)}
+		pauseModuleInstance = newPauseModule()
 	})
 
 	return pauseModuleInstance
 }
 
+func newPauseModule() *PauseModule {
+	return &PauseModule{
+		pauseRequests:            make(map[string]*bool),
+		pauseRequestTimes:        make(map[string]time.Time),
+		pauseBatches:             make(map[string]map[string]struct{}),
+		clusterScopedControllers: make(map[string]clusterScopedController),
+	}
+}
+
 type PauseModule struct {
 	lock sync.RWMutex
 	// map of pause requester to Pause Request
 	pauseRequests map[string]*bool // having *bool gives us 3 states: [true=pause-required, false=pause-not-required, nil=unknown]
+	// map of pause requester to the time its Pause Request became true, for those which are true
+	pauseRequestTimes map[string]time.Time
+	// map of pause requester to the PipelineRollouts (as "namespace/name") which its Pause Request applies to, for a requester
+	// pausing Pipelines in batches; otherwise the Pause Request applies to all of the requester's Pipelines
+	pauseBatches map[string]map[string]struct{}
+	// store persists the Pause Requests whenever they change, so that they survive a restart (nil if they aren't persisted)
+	store pauseRequestStore
+	// snapshotVersion is incremented each time the Pause Requests are snapshotted to be persisted
+	snapshotVersion uint64
+	// writer writes the snapshots to the store one at a time, outside of the lock
+	writer pauseRequestWriter
+	// map of cluster-scoped NumaflowControllerRollout (as "namespace/name") to the Pipelines its Numaflow Controller manages
+	clusterScopedControllers map[string]clusterScopedController
 }
 
 func (pm *PauseModule) newPauseRequest(requester string) {
@@ -41,29 +70,97 @@ func (pm *PauseModule) newPauseRequest(requester string) {
 	}
 }
 
-func (pm *PauseModule) deletePauseRequest(requester string) {
-	pm.lock.Lock()
-	defer pm.lock.Unlock()
-	delete(pm.pauseRequests, requester)
+func (pm *PauseModule) deletePauseRequest(ctx context.Context, requester string) error {
+	_, err := pm.updateRequesterState(ctx, requester, func() bool {
+		_, known := pm.pauseRequests[requester]
+		_, batched := pm.pauseBatches[requester]
+		delete(pm.pauseRequests, requester)
+		delete(pm.pauseRequestTimes, requester)
+		delete(pm.pauseBatches, requester)
+		return known || batched
+	})
+	return err
+}
+
+// if the requester isn't already requesting a pause, make its next Pause Request apply to no PipelineRollouts until
+// they're added to its batch
+func (pm *PauseModule) startPauseBatch(ctx context.Context, requester string) error {
+	_, err := pm.updateRequesterState(ctx, requester, func() bool {
+		pauseRequest := pm.pauseRequests[requester]
+		if pauseRequest != nil && *pauseRequest {
+			return false
+		}
+		pm.pauseBatches[requester] = map[string]struct{}{}
+		return true
+	})
+	return err
+}
+
+// set the PipelineRollouts which the requester's Pause Request applies to, or nil for all of them
+func (pm *PauseModule) setPauseBatch(ctx context.Context, requester string, pipelineRollouts map[string]struct{}) error {
+	_, err := pm.updateRequesterState(ctx, requester, func() bool {
+		_, batched := pm.pauseBatches[requester]
+		if !batched && pipelineRollouts == nil {
+			return false
+		}
+		if pipelineRollouts == nil {
+			delete(pm.pauseBatches, requester)
+		} else {
+			pm.pauseBatches[requester] = pipelineRollouts
+		}
+		return true
+	})
+	return err
+}
+
+// get the PipelineRollouts which the requester's Pause Request applies to, if it only applies to some
+func (pm *PauseModule) getPauseBatch(requester string) (map[string]struct{}, bool) {
+	pm.lock.RLock()
+	defer pm.lock.RUnlock()
+	pipelineRollouts, batched := pm.pauseBatches[requester]
+	return pipelineRollouts, batched
+}
+
+// return whether the requester is requesting this PipelineRollout's Pipeline to pause (caller must hold the lock)
+func (pm *PauseModule) requestsPause(requester string, pipelineRollout k8stypes.NamespacedName) bool {
+	pauseRequest := pm.pauseRequests[requester]
+	if pauseRequest == nil || !*pauseRequest {
+		return false
+	}
+	pipelineRollouts, batched := pm.pauseBatches[requester]
+	if !batched {
+		return true
+	}
+	_, inBatch := pipelineRollouts[pipelineRollout.String()]
+	return inBatch
 }
 
 // update and return whether the value changed
-func (pm *PauseModule) updatePauseRequest(requester string, pause bool) bool {
+func (pm *PauseModule) updatePauseRequest(ctx context.Context, requester string, pause bool) (bool, error) {
 	// first check to see if the same using read lock
 	pm.lock.RLock()
 	entry := pm.pauseRequests[requester]
 	if entry != nil && *entry == pause {
 		// nothing to do
 		pm.lock.RUnlock()
-		return false
+		return false, nil
 	}
 	pm.lock.RUnlock()
 
 	// if not the same, use write lock to modify
-	pm.lock.Lock()
-	defer pm.lock.Unlock()
-	pm.pauseRequests[requester] = &pause
-	return true
+	return pm.updateRequesterState(ctx, requester, func() bool {
+		entry := pm.pauseRequests[requester]
+		if entry != nil && *entry == pause {
+			return false
+		}
+		pm.pauseRequests[requester] = &pause
+		if pause {
+			pm.pauseRequestTimes[requester] = time.Now()
+		} else {
+			delete(pm.pauseRequestTimes, requester)
+		}
+		return true
+	})
 }
 
 func (pm *PauseModule) getPauseRequest(requester string) (*bool, bool) {
@@ -73,6 +170,55 @@ func (pm *PauseModule) getPauseRequest(requester string) (*bool, bool) {
 	return entry, exists
 }
 
+// return whether the requester is requesting this PipelineRollout's Pipeline to pause, and whether its Pause Request is known
+func (pm *PauseModule) getPauseRequestForPipeline(requester string, pipelineRollout k8stypes.NamespacedName) (bool, bool) {
+	pm.lock.RLock()
+	defer pm.lock.RUnlock()
+	_, exists := pm.pauseRequests[requester]
+	return pm.requestsPause(requester, pipelineRollout), exists
+}
+
+// return when the requester began requesting this PipelineRollout's Pipeline (or MonoVertexRollout's MonoVertex) to pause,
+// and whether it currently is
+func (pm *PauseModule) getPauseRequestTime(requester string, rollout k8stypes.NamespacedName) (time.Time, bool) {
+	pm.lock.RLock()
+	defer pm.lock.RUnlock()
+	if !pm.requestsPause(requester, rollout) {
+		return time.Time{}, false
+	}
+	return pm.pauseRequestTimes[requester], true
+}
+
+// a party which may request a child to pause, along with the name reported for it in the Rollout's Status
+type namedPauseRequester struct {
+	key  string
+	name string
+}
+
+// get the active Pause Requests for the Rollout's child among the given requesters
+// the time of a request which was already reported in previousRequests is kept, since the PauseModule's times don't
+// survive a restart
+func (pm *PauseModule) getActivePauseRequests(rollout k8stypes.NamespacedName, requesters []namedPauseRequester, previousRequests []apiv1.PauseRequest) []apiv1.PauseRequest {
+	var activeRequests []apiv1.PauseRequest
+	for _, requester := range requesters {
+		since, active := pm.getPauseRequestTime(requester.key, rollout)
+		if !active {
+			continue
+		}
+		if since.IsZero() {
+			// not known when the request began
+			since = time.Now()
+		}
+		for _, previousRequest := range previousRequests {
+			if previousRequest.Requester == requester.name && previousRequest.Since.Time.Before(since) {
+				since = previousRequest.Since.Time
+			}
+		}
+		activeRequests = append(activeRequests, apiv1.PauseRequest{Requester: requester.name, Since: metav1.NewTime(since)})
+	}
+	return activeRequests
+}
+
 // pause pipeline
 func (pm *PauseModule) pausePipeline(ctx context.Context, c client.Client, pipeline *kubernetes.GenericObject) error {
 	var existingPipelineSpec PipelineSpec
@@ -90,20 +236,21 @@ func (pm *PauseModule) runPipelineIfSafe(ctx context.Context, c client.Client, p
 	pm.lock.RLock()
 	defer pm.lock.RUnlock()
 
-	// verify that all requests are still to pause, if not we can't run right now
-	controllerPauseRequest := pm.pauseRequests[pm.getNumaflowControllerKey(pipeline.Namespace)]
-	var existingPipelineSpec PipelineSpec
-	if err := json.Unmarshal(pipeline.Spec.Raw, &existingPipelineSpec); err != nil {
+	// verify that no requests are to pause, if not we can't run right now
+	pipelineRollout := k8stypes.NamespacedName{Namespace: pipeline.Namespace, Name: getPipelineRolloutName(pipeline.Name)}
+	isbsvcName, err := getPipelineISBServiceRolloutName(pipeline)
+	if err != nil {
 		return false, err
 	}
-	isbsvcName := existingPipelineSpec.getISBSvcName()
-	isbsvcPauseRequest := pm.pauseRequests[pm.getISBServiceKey(pipeline.Namespace, isbsvcName)]
-	if (controllerPauseRequest != nil && *controllerPauseRequest) || (isbsvcPauseRequest != nil && *isbsvcPauseRequest) {
+	controllerKey := pm.numaflowControllerKeyForPipeline(pipeline.Namespace, pipeline.Annotations[common.AnnotationKeyNumaflowInstanceID])
+	if pm.requestsPause(controllerKey, pipelineRollout) ||
+		pm.requestsPause(pm.getISBServiceKey(pipeline.Namespace, isbsvcName), pipelineRollout) ||
+		pm.requestsPause(pm.getPipelineRolloutKey(pipelineRollout.Namespace, pipelineRollout.Name), pipelineRollout) {
 		// somebody is requesting to pause - can't run
 		return false, nil
 	}
 
-	err := pm.updatePipelineLifecycle(ctx, c, pipeline, "Running")
+	err = pm.updatePipelineLifecycle(ctx, c, pipeline, "Running")
 	if err != nil {
 		return false, err
 	}
@@ -144,6 +291,18 @@ func (pm *PauseModule) getNumaflowControllerKey(namespace string) string {
 	return fmt.Sprintf("NC:%s", namespace)
 }
 
+func (pm *PauseModule) getClusterScopedNumaflowControllerKey(instanceID string) string {
+	return fmt.Sprintf("NC:*/%s", instanceID)
+}
+
 func (pm *PauseModule) getISBServiceKey(namespace string, name string) string {
 	return fmt.Sprintf("I:%s/%s", namespace, name)
 }
+
+func (pm *PauseModule) getPipelineRolloutKey(namespace string, name string) string {
+	return fmt.Sprintf("P:%s/%s", namespace, name)
+}
+
+func (pm *PauseModule) getMonoVertexRolloutKey(namespace string, name string) string {
+	return fmt.Sprintf("MV:%s/%s", namespace, name)
+}
@@ -0,0 +1,274 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// pausingPipeline is a Pipeline which a PauseRequester pauses, along with its PipelineRollout
+type pausingPipeline struct {
+	pipeline        *kubernetes.GenericObject
+	pipelineRollout *apiv1.PipelineRollout
+	// pause priority of the PipelineRollout, if pausing by priority
+	pausePriority int
+}
+
+// key of the PipelineRollout, as used by the PauseModule's pause batches
+func (p pausingPipeline) key() string {
+	return k8stypes.NamespacedName{Namespace: p.pipelineRollout.Namespace, Name: p.pipelineRollout.Name}.String()
+}
+
+// return whether Pipelines are configured to be paused in batches rather than all at once
+func pauseInBatches() bool {
+	usdeConfig := config.GetConfigManagerInstance().GetUSDEConfig()
+	return usdeConfig.PauseByPriority || usdeConfig.PauseBatchSize > 0
+}
+
+// get the Pipelines corresponding to this Rollout along with their PipelineRollouts, in the order in which they're paused
+func getPausingPipelines(ctx context.Context, k8sClient client.Client, pauseRequester PauseRequester, rolloutNamespace string, rolloutName string) ([]pausingPipeline, error) {
+	pipelines, err := pauseRequester.getPipelineList(ctx, rolloutNamespace, rolloutName)
+	if err != nil {
+		return nil, err
+	}
+
+	byPriority := config.GetConfigManagerInstance().GetUSDEConfig().PauseByPriority
+	pausingPipelines := make([]pausingPipeline, 0, len(pipelines))
+	for _, pipeline := range pipelines {
+		// Get PipelineRollout CR
+		pipelineRolloutName := getPipelineRolloutName(pipeline.Name)
+		pipelineRollout := &apiv1.PipelineRollout{}
+		if err := k8sClient.Get(ctx, k8stypes.NamespacedName{Namespace: pipeline.Namespace, Name: pipelineRolloutName}, pipelineRollout); err != nil {
+			return nil, err
+		}
+		pausing := pausingPipeline{pipeline: pipeline, pipelineRollout: pipelineRollout}
+		if byPriority {
+			pausing.pausePriority = getPausePriority(ctx, pipelineRollout)
+		}
+		pausingPipelines = append(pausingPipelines, pausing)
+	}
+
+	sort.SliceStable(pausingPipelines, func(i, j int) bool {
+		if pausingPipelines[i].pausePriority != pausingPipelines[j].pausePriority {
+			return pausingPipelines[i].pausePriority < pausingPipelines[j].pausePriority
+		}
+		return pausingPipelines[i].pipeline.Name < pausingPipelines[j].pipeline.Name
+	})
+	return pausingPipelines, nil
+}
+
+// get the pause priority of the PipelineRollout from its label, or 0 if it's not set
+func getPausePriority(ctx context.Context, pipelineRollout *apiv1.PipelineRollout) int {
+	priorityLabel, found := pipelineRollout.Labels[common.LabelKeyPausePriority]
+	if !found {
+		return 0
+	}
+	priority, err := strconv.Atoi(priorityLabel)
+	if err != nil {
+		logger.FromContext(ctx).Warnf("ignoring invalid %s label %q on PipelineRollout %s", common.LabelKeyPausePriority, priorityLabel, pipelineRollout.Name)
+		return 0
+	}
+	return priority
+}
+
+// divide the Pipelines, which are in pause order, into the batches in which they're paused
+// (or return nil if they're paused all at once)
+func getPauseBatches(pipelines []pausingPipeline) [][]pausingPipeline {
+	usdeConfig := config.GetConfigManagerInstance().GetUSDEConfig()
+
+	var batches [][]pausingPipeline
+	switch {
+	case usdeConfig.PauseBatchSize > 0:
+		for start := 0; start < len(pipelines); start += usdeConfig.PauseBatchSize {
+			end := min(start+usdeConfig.PauseBatchSize, len(pipelines))
+			batches = append(batches, pipelines[start:end])
+		}
+	case usdeConfig.PauseByPriority:
+		for i, pipeline := range pipelines {
+			if i == 0 || pipeline.pausePriority != pipelines[i-1].pausePriority {
+				batches = append(batches, []pausingPipeline{})
+			}
+			batches[len(batches)-1] = append(batches[len(batches)-1], pipeline)
+		}
+	}
+	return batches
+}
+
+// if this Rollout is pausing Pipelines in batches, add the next batches to its pause request once the batches before them
+// have paused
+func admitPauseBatches(ctx context.Context, pauseRequester PauseRequester, rollout client.Object, pipelines []pausingPipeline) error {
+	numaLogger := logger.FromContext(ctx)
+	pm := GetPauseModule()
+	requester := pauseRequester.getRolloutKey(rollout.GetNamespace(), rollout.GetName())
+
+	admitted, batched := pm.getPauseBatch(requester)
+	if !batched {
+		return nil
+	}
+	batches := getPauseBatches(pipelines)
+	if batches == nil {
+		// no longer configured to pause in batches, so the pause request applies to all Pipelines
+		numaLogger.Info("pausing all remaining Pipelines")
+		if err := pm.setPauseBatch(ctx, requester, nil); err != nil {
+			return err
+		}
+		enqueuePausingPipelines(pipelines)
+		return nil
+	}
+
+	admitted = maps.Clone(admitted)
+	var newlyAdmitted []pausingPipeline
+	for batchIndex, batch := range batches {
+		batchPaused := true
+		for _, pipeline := range batch {
+			if _, found := admitted[pipeline.key()]; !found {
+				admitted[pipeline.key()] = struct{}{}
+				newlyAdmitted = append(newlyAdmitted, pipeline)
+			}
+			if !isPipelinePausedOrWontPause(ctx, pipeline.pipeline, pipeline.pipelineRollout) {
+				batchPaused = false
+			}
+		}
+		if !batchPaused {
+			if len(newlyAdmitted) > 0 {
+				numaLogger.Infof("pausing batch %d of %d of Pipelines", batchIndex+1, len(batches))
+			}
+			break
+		}
+	}
+
+	if len(newlyAdmitted) > 0 {
+		if err := pm.setPauseBatch(ctx, requester, admitted); err != nil {
+			return err
+		}
+		enqueuePausingPipelines(newlyAdmitted)
+	}
+	return nil
+}
+
+// if this Rollout paused Pipelines in batches, release them from its pause request in reverse order: a batch is released
+// once the batches after it have resumed
+// return whether all batches have been released
+func releasePauseBatches(ctx context.Context, k8sClient client.Client, pauseRequester PauseRequester, rollout PauseRequestingRollout) (bool, error) {
+	numaLogger := logger.FromContext(ctx)
+	pm := GetPauseModule()
+	requester := pauseRequester.getRolloutKey(rollout.GetNamespace(), rollout.GetName())
+
+	admitted, batched := pm.getPauseBatch(requester)
+	if !batched || len(admitted) == 0 {
+		return true, nil
+	}
+	pauseRequest, _ := pm.getPauseRequest(requester)
+	if pauseRequest == nil || !*pauseRequest {
+		return true, nil
+	}
+	pipelines, err := getPausingPipelines(ctx, k8sClient, pauseRequester, rollout.GetNamespace(), rollout.GetName())
+	if err != nil {
+		return false, err
+	}
+	updatePauseProgress(ctx, rollout, pipelines)
+	batches := getPauseBatches(pipelines)
+
+	for batchIndex := len(batches) - 1; batchIndex >= 0; batchIndex-- {
+		batch := batches[batchIndex]
+		var release []pausingPipeline
+		for _, pipeline := range batch {
+			if _, found := admitted[pipeline.key()]; found {
+				release = append(release, pipeline)
+			}
+		}
+		if len(release) == 0 {
+			// this batch was already released (or never paused): wait for it to resume before releasing the next one
+			for _, pipeline := range batch {
+				resumed, err := isPipelineResumedOrWontResume(pipeline)
+				if err != nil {
+					return false, err
+				}
+				if !resumed {
+					numaLogger.Debugf("waiting for pipeline %q to resume", pipeline.pipeline.Name)
+					return false, nil
+				}
+			}
+			continue
+		}
+
+		numaLogger.Infof("resuming batch %d of %d of Pipelines", batchIndex+1, len(batches))
+		admitted = maps.Clone(admitted)
+		for _, pipeline := range release {
+			delete(admitted, pipeline.key())
+		}
+		if batchIndex == 0 {
+			// the caller removes the pause request altogether
+			return true, nil
+		}
+		if err := pm.setPauseBatch(ctx, requester, admitted); err != nil {
+			return false, err
+		}
+		enqueuePausingPipelines(release)
+		return false, nil
+	}
+	return true, nil
+}
+
+// return whether all Pipelines are covered by this Rollout's pause request, including if it's not pausing them in batches
+func allPipelinesAdmitted(pauseRequester PauseRequester, rollout client.Object, pipelines []pausingPipeline) bool {
+	admitted, batched := GetPauseModule().getPauseBatch(pauseRequester.getRolloutKey(rollout.GetNamespace(), rollout.GetName()))
+	if !batched {
+		return true
+	}
+	for _, pipeline := range pipelines {
+		if _, found := admitted[pipeline.key()]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// set the Rollout's pause progress and return the number of Pipelines which are paused (or won't pause)
+func updatePauseProgress(ctx context.Context, rollout PauseRequestingRollout, pipelines []pausingPipeline) int {
+	paused := 0
+	for _, pipeline := range pipelines {
+		if isPipelinePausedOrWontPause(ctx, pipeline.pipeline, pipeline.pipelineRollout) {
+			paused++
+		}
+	}
+	rollout.GetPauseRequestStatus().Progress = fmt.Sprintf("%d/%d pipelines paused", paused, len(pipelines))
+	return paused
+}
+
+// return whether a Pipeline released from a pause request has been set to run again, or won't be because its
+// PipelineRollout says to pause it (either in the Pipeline spec or through spec.paused)
+func isPipelineResumedOrWontResume(pipeline pausingPipeline) (bool, error) {
+	var pipelineSpec PipelineSpec
+	if err := json.Unmarshal(pipeline.pipeline.Spec.Raw, &pipelineSpec); err != nil {
+		return false, fmt.Errorf("failed to convert Pipeline spec %q into PipelineSpec type, err=%v", string(pipeline.pipeline.Spec.Raw), err)
+	}
+	if pipelineSpec.Lifecycle.DesiredPhase != string(numaflowv1.PipelinePhasePaused) {
+		return true, nil
+	}
+
+	var rolloutPipelineSpec PipelineSpec
+	if err := json.Unmarshal(pipeline.pipelineRollout.Spec.Pipeline.Spec.Raw, &rolloutPipelineSpec); err != nil {
+		return false, fmt.Errorf("failed to convert PipelineRollout spec %q into PipelineSpec type, err=%v", string(pipeline.pipelineRollout.Spec.Pipeline.Spec.Raw), err)
+	}
+	return pipelineROReconciler.isSpecBasedPause(rolloutPipelineSpec) || pipeline.pipelineRollout.Spec.Paused, nil
+}
+
+func enqueuePausingPipelines(pipelines []pausingPipeline) {
+	for _, pipeline := range pipelines {
+		pipelineROReconciler.enqueuePipeline(k8stypes.NamespacedName{Namespace: pipeline.pipelineRollout.Namespace, Name: pipeline.pipelineRollout.Name})
+	}
+}
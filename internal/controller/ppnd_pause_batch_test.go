@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/util"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func newPausingPipeline(name string, priority string) pausingPipeline {
+	labels := map[string]string{}
+	if priority != "" {
+		labels[common.LabelKeyPausePriority] = priority
+	}
+	pipelineRollout := &apiv1.PipelineRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: name, Labels: labels},
+		Spec:       apiv1.PipelineRolloutSpec{Pipeline: apiv1.Pipeline{Spec: runtime.RawExtension{Raw: []byte(`{}`)}}},
+	}
+	return pausingPipeline{
+		pipeline: &kubernetes.GenericObject{
+			ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: name + "-0"},
+			Spec:       runtime.RawExtension{Raw: []byte(`{}`)},
+		},
+		pipelineRollout: pipelineRollout,
+		pausePriority:   getPausePriority(context.Background(), pipelineRollout),
+	}
+}
+
+func Test_getPauseBatches(t *testing.T) {
+	configManager := config.GetConfigManagerInstance()
+	defer configManager.UpdateUSDEConfig(config.USDEConfig{})
+
+	// pipelines in pause order
+	pipelines := []pausingPipeline{
+		newPausingPipeline("pipeline-a", "-1"),
+		newPausingPipeline("pipeline-b", ""),
+		newPausingPipeline("pipeline-c", "not-a-number"),
+		newPausingPipeline("pipeline-d", "5"),
+		newPausingPipeline("pipeline-e", "5"),
+	}
+
+	testCases := []struct {
+		name            string
+		pauseByPriority bool
+		pauseBatchSize  int
+		expectedBatches [][]string
+	}{
+		{
+			name:            "all at once",
+			expectedBatches: nil,
+		},
+		{
+			name:            "by batch size",
+			pauseBatchSize:  2,
+			expectedBatches: [][]string{{"pipeline-a", "pipeline-b"}, {"pipeline-c", "pipeline-d"}, {"pipeline-e"}},
+		},
+		{
+			name:            "by priority",
+			pauseByPriority: true,
+			expectedBatches: [][]string{{"pipeline-a"}, {"pipeline-b", "pipeline-c"}, {"pipeline-d", "pipeline-e"}},
+		},
+		{
+			name:            "batch size takes precedence over priority",
+			pauseByPriority: true,
+			pauseBatchSize:  3,
+			expectedBatches: [][]string{{"pipeline-a", "pipeline-b", "pipeline-c"}, {"pipeline-d", "pipeline-e"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configManager.UpdateUSDEConfig(config.USDEConfig{PauseByPriority: tc.pauseByPriority, PauseBatchSize: tc.pauseBatchSize})
+			assert.Equal(t, tc.pauseByPriority || tc.pauseBatchSize > 0, pauseInBatches())
+
+			var batchNames [][]string
+			for _, batch := range getPauseBatches(pipelines) {
+				names := []string{}
+				for _, pipeline := range batch {
+					names = append(names, pipeline.pipelineRollout.Name)
+				}
+				batchNames = append(batchNames, names)
+			}
+			assert.Equal(t, tc.expectedBatches, batchNames)
+		})
+	}
+}
+
+func Test_PauseModule_pauseBatch(t *testing.T) {
+	ctx := context.Background()
+	pm := GetPauseModule()
+	requester := pm.getISBServiceKey(defaultNamespace, "my-isbsvc")
+	pipelineA := newPausingPipeline("pipeline-a", "")
+	pipelineB := newPausingPipeline("pipeline-b", "")
+	defer func() { _ = pm.deletePauseRequest(ctx, requester) }()
+
+	// the batch is set up before the pause request so that no Pipeline is paused before it's admitted
+	assert.NoError(t, pm.startPauseBatch(ctx, requester))
+	_, err := pm.updatePauseRequest(ctx, requester, true)
+	assert.NoError(t, err)
+	paused, found := pm.getPauseRequestForPipeline(requester, k8stypes.NamespacedName{Namespace: defaultNamespace, Name: pipelineA.pipelineRollout.Name})
+	assert.True(t, found)
+	assert.False(t, paused)
+
+	assert.NoError(t, pm.setPauseBatch(ctx, requester, map[string]struct{}{pipelineA.key(): {}}))
+	paused, _ = pm.getPauseRequestForPipeline(requester, k8stypes.NamespacedName{Namespace: defaultNamespace, Name: pipelineA.pipelineRollout.Name})
+	assert.True(t, paused)
+	paused, _ = pm.getPauseRequestForPipeline(requester, k8stypes.NamespacedName{Namespace: defaultNamespace, Name: pipelineB.pipelineRollout.Name})
+	assert.False(t, paused)
+
+	// without a batch, the pause request applies to all Pipelines
+	assert.NoError(t, pm.setPauseBatch(ctx, requester, nil))
+	paused, _ = pm.getPauseRequestForPipeline(requester, k8stypes.NamespacedName{Namespace: defaultNamespace, Name: pipelineB.pipelineRollout.Name})
+	assert.True(t, paused)
+}
+
+// fakePauseRequester requests a fixed list of Pipelines to pause
+type fakePauseRequester struct {
+	pipelines []pausingPipeline
+}
+
+func (f *fakePauseRequester) getPipelineList(ctx context.Context, rolloutNamespace string, rolloutName string) ([]*kubernetes.GenericObject, error) {
+	pipelines := []*kubernetes.GenericObject{}
+	for _, pipeline := range f.pipelines {
+		pipelines = append(pipelines, pipeline.pipeline)
+	}
+	return pipelines, nil
+}
+
+func (f *fakePauseRequester) markRolloutPaused(ctx context.Context, rollout client.Object, paused bool) error {
+	return nil
+}
+
+func (f *fakePauseRequester) getRolloutKey(rolloutNamespace string, rolloutName string) string {
+	return GetPauseModule().getISBServiceKey(rolloutNamespace, rolloutName)
+}
+
+func (f *fakePauseRequester) getChildTypeString() string {
+	return "InterStepBufferService"
+}
+
+func (f *fakePauseRequester) recordPauseTimeout(rollout client.Object, policy apiv1.PauseTimeoutPolicy, message string) {
+}
+
+func setPipelinePhase(pipeline pausingPipeline, phase string) {
+	pipeline.pipeline.Status = runtime.RawExtension{Raw: []byte(`{"phase":"` + phase + `"}`)}
+}
+
+func setPipelineDesiredPhase(pipeline pausingPipeline, desiredPhase string) {
+	pipeline.pipeline.Spec = runtime.RawExtension{Raw: []byte(`{"lifecycle":{"desiredPhase":"` + desiredPhase + `"}}`)}
+}
+
+// return the names of the PipelineRollouts which the requester's pause request currently applies to
+func getAdmittedPipelines(requester string) []string {
+	admitted, _ := GetPauseModule().getPauseBatch(requester)
+	names := []string{}
+	for _, name := range []string{"pipeline-a", "pipeline-b", "pipeline-c"} {
+		if _, found := admitted[k8stypes.NamespacedName{Namespace: defaultNamespace, Name: name}.String()]; found {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func Test_pauseBatches_admitAndRelease(t *testing.T) {
+	ctx := context.Background()
+	configManager := config.GetConfigManagerInstance()
+	configManager.UpdateUSDEConfig(config.USDEConfig{PauseBatchSize: 1})
+	defer configManager.UpdateUSDEConfig(config.USDEConfig{})
+	pipelineROReconciler = &PipelineRolloutReconciler{queue: util.NewWorkQueue("fake_queue")}
+
+	pipelines := []pausingPipeline{
+		newPausingPipeline("pipeline-a", ""),
+		newPausingPipeline("pipeline-b", ""),
+		newPausingPipeline("pipeline-c", ""),
+	}
+	pauseRequester := &fakePauseRequester{pipelines: pipelines}
+	rollout := &apiv1.ISBServiceRollout{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "my-isbsvc"}}
+	requester := pauseRequester.getRolloutKey(rollout.Namespace, rollout.Name)
+	pm := GetPauseModule()
+	defer func() { _ = pm.deletePauseRequest(ctx, requester) }()
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, apiv1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(pipelines[0].pipelineRollout, pipelines[1].pipelineRollout, pipelines[2].pipelineRollout).Build()
+
+	_, err := requestPipelinesPause(ctx, pauseRequester, rollout, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, getAdmittedPipelines(requester))
+
+	// each batch is admitted once the batches before it have paused
+	assert.NoError(t, admitPauseBatches(ctx, pauseRequester, rollout, pipelines))
+	assert.Equal(t, []string{"pipeline-a"}, getAdmittedPipelines(requester))
+	assert.NoError(t, admitPauseBatches(ctx, pauseRequester, rollout, pipelines))
+	assert.Equal(t, []string{"pipeline-a"}, getAdmittedPipelines(requester))
+	names := []string{"pipeline-a", "pipeline-b", "pipeline-c"}
+	for i, pipeline := range pipelines {
+		assert.Equal(t, names[:i+1], getAdmittedPipelines(requester))
+		assert.Equal(t, i == len(pipelines)-1, allPipelinesAdmitted(pauseRequester, rollout, pipelines))
+		setPipelineDesiredPhase(pipeline, "Paused")
+		setPipelinePhase(pipeline, "Paused")
+		assert.NoError(t, admitPauseBatches(ctx, pauseRequester, rollout, pipelines))
+	}
+	assert.Equal(t, names, getAdmittedPipelines(requester))
+	assert.True(t, allPipelinesAdmitted(pauseRequester, rollout, pipelines))
+
+	// the batches are released in reverse order, each once the batches after it have resumed
+	resumed, err := resumePipelines(ctx, k8sClient, pauseRequester, rollout)
+	assert.NoError(t, err)
+	assert.False(t, resumed)
+	assert.Equal(t, []string{"pipeline-a", "pipeline-b"}, getAdmittedPipelines(requester))
+	resumed, err = resumePipelines(ctx, k8sClient, pauseRequester, rollout)
+	assert.NoError(t, err)
+	assert.False(t, resumed)
+	assert.Equal(t, []string{"pipeline-a", "pipeline-b"}, getAdmittedPipelines(requester))
+
+	setPipelineDesiredPhase(pipelines[2], "Running")
+	resumed, err = resumePipelines(ctx, k8sClient, pauseRequester, rollout)
+	assert.NoError(t, err)
+	assert.False(t, resumed)
+	assert.Equal(t, []string{"pipeline-a"}, getAdmittedPipelines(requester))
+
+	// releasing the first batch removes the pause request altogether
+	setPipelineDesiredPhase(pipelines[1], "Running")
+	resumed, err = resumePipelines(ctx, k8sClient, pauseRequester, rollout)
+	assert.NoError(t, err)
+	assert.True(t, resumed)
+	pauseRequest, _ := pm.getPauseRequest(requester)
+	assert.False(t, *pauseRequest)
+	_, batched := pm.getPauseBatch(requester)
+	assert.False(t, batched)
+}
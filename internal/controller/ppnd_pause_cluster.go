@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"sort"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// clusterScopedController is a Numaflow Controller which manages the Pipelines with its InstanceID across namespaces
+type clusterScopedController struct {
+	instanceID string
+	namespaces map[string]struct{}
+}
+
+// register the Numaflow Controller of a cluster-scoped NumaflowControllerRollout along with the namespaces it manages
+func (pm *PauseModule) setClusterScopedController(controllerRollout k8stypes.NamespacedName, instanceID string, namespaces map[string]struct{}) {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	pm.clusterScopedControllers[controllerRollout.String()] = clusterScopedController{instanceID: instanceID, namespaces: namespaces}
+}
+
+func (pm *PauseModule) deleteClusterScopedController(controllerRollout k8stypes.NamespacedName) {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	delete(pm.clusterScopedControllers, controllerRollout.String())
+}
+
+// get the Numaflow Controller of the NumaflowControllerRollout, if it's registered as cluster-scoped
+func (pm *PauseModule) getClusterScopedController(controllerRollout k8stypes.NamespacedName) (clusterScopedController, bool) {
+	pm.lock.RLock()
+	defer pm.lock.RUnlock()
+	controller, found := pm.clusterScopedControllers[controllerRollout.String()]
+	return controller, found
+}
+
+// get the key of the Numaflow Controller which manages the Pipelines in this namespace with this InstanceID:
+// a cluster-scoped one if any manages the namespace, otherwise the one in the namespace itself
+func (pm *PauseModule) getNumaflowControllerKeyForPipeline(namespace string, instanceID string) string {
+	pm.lock.RLock()
+	defer pm.lock.RUnlock()
+	return pm.numaflowControllerKeyForPipeline(namespace, instanceID)
+}
+
+// same as getNumaflowControllerKeyForPipeline (caller must hold the lock)
+func (pm *PauseModule) numaflowControllerKeyForPipeline(namespace string, instanceID string) string {
+	// if more than one claims the namespace, consistently use the first one
+	controllerRollouts := make([]string, 0, len(pm.clusterScopedControllers))
+	for controllerRollout := range pm.clusterScopedControllers {
+		controllerRollouts = append(controllerRollouts, controllerRollout)
+	}
+	sort.Strings(controllerRollouts)
+	for _, controllerRollout := range controllerRollouts {
+		controller := pm.clusterScopedControllers[controllerRollout]
+		if _, managed := controller.namespaces[namespace]; managed && controller.instanceID == instanceID {
+			return pm.getClusterScopedNumaflowControllerKey(instanceID)
+		}
+	}
+	return pm.getNumaflowControllerKey(namespace)
+}
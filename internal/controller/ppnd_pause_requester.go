@@ -3,10 +3,13 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/numaproj/numaplane/internal/controller/config"
 	"github.com/numaproj/numaplane/internal/util/kubernetes"
 	"github.com/numaproj/numaplane/internal/util/logger"
 	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -24,13 +27,32 @@ type PauseRequester interface {
 
 	// just a free form string to describe what we're deploying, for logging
 	getChildTypeString() string
+
+	// record that the Pipelines didn't all pause within the pause timeout, and the policy applied
+	recordPauseTimeout(rollout client.Object, policy apiv1.PauseTimeoutPolicy, message string)
+}
+
+// PauseRequestingRollout is a Rollout which requests Pipelines to pause while its child is updated
+type PauseRequestingRollout interface {
+	client.Object
+
+	GetStatus() *apiv1.Status
+
+	GetPauseRequestStatus() *apiv1.PauseStatus
 }
 
+const (
+	// reasons for the PauseTimedOut Condition, corresponding to the policy applied
+	pauseTimedOutReasonAborted  = "Aborted"
+	pauseTimedOutReasonForced   = "Forced"
+	pauseTimedOutReasonNotified = "Notified"
+)
+
 // process a child object, pausing pipelines or resuming pipelines if needed
 // return:
 // - true if done with PPND
 // - error if any (note we'll automatically reuqueue if there's an error anyway)
-func processChildObjectWithPPND(ctx context.Context, k8sclient client.Client, rollout client.Object, pauseRequester PauseRequester,
+func processChildObjectWithPPND(ctx context.Context, k8sclient client.Client, rollout PauseRequestingRollout, pauseRequester PauseRequester,
 	resourceNeedsUpdating bool, resourceIsUpdating bool, updateFunc func() error) (bool, error) {
 	numaLogger := logger.FromContext(ctx)
 
@@ -41,41 +63,98 @@ func processChildObjectWithPPND(ctx context.Context, k8sclient client.Client, ro
 		numaLogger.Infof("%s either needs to or is in the process of updating", pauseRequester.getChildTypeString())
 		// TODO: maybe only pause if the update requires pausing
 
+		if resourceNeedsUpdating && pauseAborted(rollout) {
+			// pausing timed out and was aborted for this generation of the Rollout: leave the Pipelines running until the Rollout changes
+			numaLogger.Debugf("pausing Pipelines was aborted, so %s won't be updated until the Rollout changes", pauseRequester.getChildTypeString())
+			_, err := resumePipelines(ctx, k8sclient, pauseRequester, rollout)
+			if err != nil {
+				return false, fmt.Errorf("error requesting Pipelines resume: %w", err)
+			}
+			rollout.GetStatus().MarkWaitingFor("the Rollout to change, since pausing Pipelines for the %s update timed out and was aborted",
+				pauseRequester.getChildTypeString())
+			return false, nil
+		}
+
 		// request pause if we haven't already
 		pauseRequestUpdated, err := requestPipelinesPause(ctx, pauseRequester, rollout, true)
 		if err != nil {
 			return false, fmt.Errorf("error requesting Pipelines pause: %w", err)
 		}
+		_, rollout.GetPauseRequestStatus().TimeoutPolicy = getPauseTimeout()
+
+		pipelines, err := getPausingPipelines(ctx, k8sclient, pauseRequester, rolloutNamespace, rolloutName)
+		if err != nil {
+			return false, fmt.Errorf("error getting Pipelines to pause: %w", err)
+		}
+		// if pausing in batches, pause the next batch once the previous ones have paused
+		if err := admitPauseBatches(ctx, pauseRequester, rollout, pipelines); err != nil {
+			return false, fmt.Errorf("error pausing next batch of Pipelines: %w", err)
+		}
+		pausedCount := updatePauseProgress(ctx, rollout, pipelines)
+		if resourceNeedsUpdating {
+			rollout.GetStatus().MarkWaitingFor("Pipelines to pause for the %s update (%d of %d paused)",
+				pauseRequester.getChildTypeString(), pausedCount, len(pipelines))
+		} else {
+			rollout.GetStatus().MarkWaitingFor("%s to finish updating", pauseRequester.getChildTypeString())
+		}
+
 		// If we need to update the child, pause the pipelines
 		// Don't do this yet if we just made a request - it's too soon for anything to have happened
 		if !pauseRequestUpdated && resourceNeedsUpdating {
 
 			// check if the pipelines are all paused (or can't be paused)
-			allPaused, err := areAllPipelinesPausedOrWontPause(ctx, k8sclient, pauseRequester, rolloutNamespace, rolloutName)
-			if err != nil {
-				return false, fmt.Errorf("error checking if all Pipelines are paused: %w", err)
-			}
+			allPaused := pausedCount == len(pipelines) && allPipelinesAdmitted(pauseRequester, rollout, pipelines)
 			if allPaused {
 				numaLogger.Infof("confirmed all Pipelines have paused (or can't pause) so %s can safely update", pauseRequester.getChildTypeString())
+				status := rollout.GetStatus()
+				if status.GetCondition(apiv1.ConditionPauseTimedOut) != nil {
+					status.MarkFalse(apiv1.ConditionPauseTimedOut, "PipelinesPaused", "Pipelines paused", rollout.GetGeneration())
+				}
 				err = updateFunc()
 				if err != nil {
 					return false, fmt.Errorf("error updating %s: %w", pauseRequester.getChildTypeString(), err)
 				}
 			} else {
 				numaLogger.Debugf("not all Pipelines have paused")
+				err = processPauseTimeout(ctx, k8sclient, rollout, pauseRequester, updateFunc)
+				if err != nil {
+					return false, err
+				}
 			}
 
 		}
 		return false, nil
 
 	} else {
-		// remove any pause requirement if necessary
-		_, err := requestPipelinesPause(ctx, pauseRequester, rollout, false)
+		resumed, err := resumePipelines(ctx, k8sclient, pauseRequester, rollout)
 		if err != nil {
 			return false, fmt.Errorf("error requesting Pipelines resume: %w", err)
 		}
+		if !resumed {
+			rollout.GetStatus().MarkWaitingFor("Pipelines to resume after the %s update", pauseRequester.getChildTypeString())
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// remove the Rollout's request for its Pipelines to pause; if they were paused in batches, they're first resumed in
+// reverse order
+// return whether the pause request has been removed
+func resumePipelines(ctx context.Context, k8sclient client.Client, pauseRequester PauseRequester, rollout PauseRequestingRollout) (bool, error) {
+	released, err := releasePauseBatches(ctx, k8sclient, pauseRequester, rollout)
+	if err != nil {
+		return false, err
+	}
+	if !released {
+		return false, nil
 	}
 
+	// remove any pause requirement if necessary
+	if _, err = requestPipelinesPause(ctx, pauseRequester, rollout, false); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
@@ -85,8 +164,23 @@ func requestPipelinesPause(ctx context.Context, pauseRequester PauseRequester, r
 	numaLogger := logger.FromContext(ctx)
 
 	pm := GetPauseModule()
+	requester := pauseRequester.getRolloutKey(rollout.GetNamespace(), rollout.GetName())
 
-	updated := pm.updatePauseRequest(pauseRequester.getRolloutKey(rollout.GetNamespace(), rollout.GetName()), pause)
+	if pause && pauseInBatches() {
+		// a new pause request initially applies to no Pipelines: batches are added to it in order
+		if err := pm.startPauseBatch(ctx, requester); err != nil {
+			return false, err
+		}
+	}
+	updated, err := pm.updatePauseRequest(ctx, requester, pause)
+	if err != nil {
+		return false, err
+	}
+	if !pause {
+		if err := pm.setPauseBatch(ctx, requester, nil); err != nil {
+			return updated, err
+		}
+	}
 	if updated { // if the value is different from what it was then make sure we queue the pipelines to be processed
 		numaLogger.Infof("updated pause request = %t", pause)
 		pipelines, err := pauseRequester.getPipelineList(ctx, rollout.GetNamespace(), rollout.GetName())
@@ -105,27 +199,73 @@ func requestPipelinesPause(ctx context.Context, pauseRequester PauseRequester, r
 	return updated, nil
 }
 
-// check if all Pipelines corresponding to this Rollout have paused or are otherwise not pausible (contract with Numaflow is that this is Pipelines which are "Failed")
-// or have an exception for allowing data loss
-func areAllPipelinesPausedOrWontPause(ctx context.Context, k8sClient client.Client, pauseRequester PauseRequester, rolloutNamespace string, rolloutName string) (bool, error) {
+// get the pause timeout and the policy applied once it's exceeded, or zero and no policy if there's no timeout
+func getPauseTimeout() (time.Duration, apiv1.PauseTimeoutPolicy) {
+	usdeConfig := config.GetConfigManagerInstance().GetUSDEConfig()
+	if usdeConfig.PauseTimeout <= 0 {
+		return 0, ""
+	}
+	if usdeConfig.PauseTimeoutPolicy == "" {
+		return usdeConfig.PauseTimeout, apiv1.PauseTimeoutPolicyNotify
+	}
+	return usdeConfig.PauseTimeout, usdeConfig.PauseTimeoutPolicy
+}
+
+// if the Pipelines have been requested to pause for longer than the pause timeout, apply the pause timeout policy:
+// - abort: stop requesting the Pipelines to pause, and don't update the child until the Rollout changes
+// - force: update the child anyway
+// - notify: keep waiting for the Pipelines to pause
+func processPauseTimeout(ctx context.Context, k8sclient client.Client, rollout PauseRequestingRollout, pauseRequester PauseRequester,
+	updateFunc func() error) error {
 	numaLogger := logger.FromContext(ctx)
-	pipelines, err := pauseRequester.getPipelineList(ctx, rolloutNamespace, rolloutName)
-	if err != nil {
-		return false, err
+
+	pauseTimeout, policy := getPauseTimeout()
+	if pauseTimeout == 0 {
+		return nil
+	}
+	pauseStatus := rollout.GetPauseRequestStatus()
+	if time.Since(pauseStatus.LastPauseBeginTime.Time) <= pauseTimeout {
+		return nil
 	}
-	for _, pipeline := range pipelines {
 
-		// Get PipelineRollout CR
-		pipelineRolloutName := getPipelineRolloutName(pipeline.Name)
-		pipelineRollout := &apiv1.PipelineRollout{}
-		if err := k8sClient.Get(ctx, k8stypes.NamespacedName{Namespace: rolloutNamespace, Name: pipelineRolloutName}, pipelineRollout); err != nil {
-			return false, err
-		}
+	var reason string
+	switch policy {
+	case apiv1.PauseTimeoutPolicyAbort:
+		reason = pauseTimedOutReasonAborted
+	case apiv1.PauseTimeoutPolicyForce:
+		reason = pauseTimedOutReasonForced
+	default:
+		reason = pauseTimedOutReasonNotified
+	}
+	message := fmt.Sprintf("Pipelines didn't all pause within %s of %s for the %s update; applied policy %q",
+		pauseTimeout, pauseStatus.LastPauseBeginTime.UTC().Format(time.RFC3339), pauseRequester.getChildTypeString(), policy)
 
-		if !isPipelinePausedOrWontPause(ctx, pipeline, pipelineRollout) {
-			numaLogger.Debugf("pipeline %q not paused or won't pause", pipeline.Name)
-			return false, nil
+	// only record the timeout once per pause
+	status := rollout.GetStatus()
+	condition := status.GetCondition(apiv1.ConditionPauseTimedOut)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Message != message {
+		numaLogger.Warn(message)
+		pauseRequester.recordPauseTimeout(rollout, policy, message)
+	}
+	status.MarkTrueWithReason(apiv1.ConditionPauseTimedOut, reason, message, rollout.GetGeneration())
+
+	switch policy {
+	case apiv1.PauseTimeoutPolicyAbort:
+		// if the Pipelines were paused in batches, later reconciliations resume the rest of them in reverse order
+		if _, err := resumePipelines(ctx, k8sclient, pauseRequester, rollout); err != nil {
+			return fmt.Errorf("error requesting Pipelines resume: %w", err)
+		}
+	case apiv1.PauseTimeoutPolicyForce:
+		if err := updateFunc(); err != nil {
+			return fmt.Errorf("error updating %s: %w", pauseRequester.getChildTypeString(), err)
 		}
 	}
-	return true, nil
+	return nil
+}
+
+// return whether pausing the Pipelines timed out and was aborted for the current generation of the Rollout
+func pauseAborted(rollout PauseRequestingRollout) bool {
+	condition := rollout.GetStatus().GetCondition(apiv1.ConditionPauseTimedOut)
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.Reason == pauseTimedOutReasonAborted &&
+		condition.ObservedGeneration == rollout.GetGeneration()
 }
@@ -0,0 +1,336 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	k8sclientset "k8s.io/client-go/kubernetes"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+const (
+	// name of the ConfigMap in which the PauseModule's Pause Requests are persisted
+	pauseRequestsConfigMapName = "numaplane-pause-requests"
+	// key of the ConfigMap data holding the Pause Requests
+	pauseRequestsConfigMapKey = "pauseRequests"
+)
+
+// pauseRequestRecord is how the Pause Request of one requester is persisted
+type pauseRequestRecord struct {
+	Pause bool `json:"pause"`
+	// Since is when the Pause Request became true, if it is
+	Since *metav1.Time `json:"since,omitempty"`
+	// Batch is set if the Pause Request only applies to these PipelineRollouts ("namespace/name")
+	Batch *[]string `json:"batch,omitempty"`
+}
+
+// pauseRequestStore persists the PauseModule's Pause Requests so that they survive a restart
+type pauseRequestStore interface {
+	load(ctx context.Context) (map[string]pauseRequestRecord, error)
+	save(ctx context.Context, records map[string]pauseRequestRecord) error
+}
+
+// configMapPauseRequestStore persists the Pause Requests in a ConfigMap
+type configMapPauseRequestStore struct {
+	client    k8sclientset.Interface
+	namespace string
+}
+
+func (s *configMapPauseRequestStore) load(ctx context.Context) (map[string]pauseRequestRecord, error) {
+	records := map[string]pauseRequestRecord{}
+	configMap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, pauseRequestsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return records, nil
+		}
+		return nil, fmt.Errorf("failed to get ConfigMap %s/%s: %w", s.namespace, pauseRequestsConfigMapName, err)
+	}
+	if data, found := configMap.Data[pauseRequestsConfigMapKey]; found {
+		if err := json.Unmarshal([]byte(data), &records); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal pause requests from ConfigMap %s/%s: %w", s.namespace, pauseRequestsConfigMapName, err)
+		}
+	}
+	return records, nil
+}
+
+func (s *configMapPauseRequestStore) save(ctx context.Context, records map[string]pauseRequestRecord) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pause requests: %w", err)
+	}
+
+	configMaps := s.client.CoreV1().ConfigMaps(s.namespace)
+	configMap, err := configMaps.Get(ctx, pauseRequestsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get ConfigMap %s/%s: %w", s.namespace, pauseRequestsConfigMapName, err)
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: pauseRequestsConfigMapName, Namespace: s.namespace},
+			Data:       map[string]string{pauseRequestsConfigMapKey: string(data)},
+		}
+		if _, err := configMaps.Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create ConfigMap %s/%s: %w", s.namespace, pauseRequestsConfigMapName, err)
+		}
+		return nil
+	}
+
+	configMap.Data = map[string]string{pauseRequestsConfigMapKey: string(data)}
+	if _, err := configMaps.Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update ConfigMap %s/%s: %w", s.namespace, pauseRequestsConfigMapName, err)
+	}
+	return nil
+}
+
+// LoadPauseModule rebuilds the PauseModule from the Pause Requests persisted in the given namespace before a restart,
+// and persists them there from now on.
+// This must be called before any Rollout is reconciled.
+func LoadPauseModule(ctx context.Context, client k8sclientset.Interface, reader ctrlclient.Reader, namespace string) error {
+	return GetPauseModule().load(ctx, &configMapPauseRequestStore{client: client, namespace: namespace}, reader)
+}
+
+// rebuild the Pause Requests from the store, leaving out those of Rollouts which no longer exist, and persist them there
+// from now on
+// the cluster-scoped Numaflow Controllers are registered again too, since their Pause Requests are keyed by InstanceID
+// rather than by namespace
+func (pm *PauseModule) load(ctx context.Context, store pauseRequestStore, reader ctrlclient.Reader) error {
+	numaLogger := logger.FromContext(ctx)
+
+	records, err := store.load(ctx)
+	if err != nil {
+		return err
+	}
+	controllerRollouts := &apiv1.NumaflowControllerRolloutList{}
+	if err := reader.List(ctx, controllerRollouts); err != nil {
+		return fmt.Errorf("failed to list NumaflowControllerRollouts: %w", err)
+	}
+	pruned, err := prunePauseRequestRecords(ctx, reader, records, controllerRollouts.Items)
+	if err != nil {
+		return err
+	}
+	if pruned {
+		if err := store.save(ctx, records); err != nil {
+			return err
+		}
+	}
+
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	for requester, record := range records {
+		pause := record.Pause
+		pm.pauseRequests[requester] = &pause
+		if record.Since != nil {
+			pm.pauseRequestTimes[requester] = record.Since.Time
+		}
+		if record.Batch != nil {
+			pipelineRollouts := make(map[string]struct{}, len(*record.Batch))
+			for _, pipelineRollout := range *record.Batch {
+				pipelineRollouts[pipelineRollout] = struct{}{}
+			}
+			pm.pauseBatches[requester] = pipelineRollouts
+		}
+		numaLogger.Infof("restored pause request for %q: pause=%t", requester, pause)
+	}
+	for _, controllerRollout := range controllerRollouts.Items {
+		if !controllerRollout.Spec.ClusterScoped {
+			continue
+		}
+		namespaces := make(map[string]struct{}, len(controllerRollout.Status.ManagedNamespaces))
+		for _, namespace := range controllerRollout.Status.ManagedNamespaces {
+			namespaces[namespace] = struct{}{}
+		}
+		controllerRolloutName := k8stypes.NamespacedName{Namespace: controllerRollout.Namespace, Name: controllerRollout.Name}
+		pm.clusterScopedControllers[controllerRolloutName.String()] = clusterScopedController{
+			instanceID: controllerRollout.Spec.Controller.InstanceID,
+			namespaces: namespaces,
+		}
+		numaLogger.Infof("restored cluster-scoped Numaflow Controller %q managing namespaces %v", controllerRolloutName, controllerRollout.Status.ManagedNamespaces)
+	}
+	pm.store = store
+	return nil
+}
+
+// remove the records of the requesters whose Rollouts no longer exist, which may be left over if they were deleted
+// without their Pause Requests being removed
+// return whether any were removed
+func prunePauseRequestRecords(ctx context.Context, reader ctrlclient.Reader, records map[string]pauseRequestRecord,
+	controllerRollouts []apiv1.NumaflowControllerRollout) (bool, error) {
+	numaLogger := logger.FromContext(ctx)
+
+	pm := GetPauseModule()
+	controllerKeys := map[string]struct{}{}
+	for _, controllerRollout := range controllerRollouts {
+		if controllerRollout.Spec.ClusterScoped {
+			controllerKeys[pm.getClusterScopedNumaflowControllerKey(controllerRollout.Spec.Controller.InstanceID)] = struct{}{}
+		} else {
+			controllerKeys[pm.getNumaflowControllerKey(controllerRollout.Namespace)] = struct{}{}
+		}
+	}
+
+	pruned := false
+	for requester := range records {
+		var rollout ctrlclient.Object
+		var name string
+		switch {
+		case strings.HasPrefix(requester, "NC:"):
+			if _, found := controllerKeys[requester]; found {
+				continue
+			}
+		case strings.HasPrefix(requester, "I:"):
+			rollout, name = &apiv1.ISBServiceRollout{}, strings.TrimPrefix(requester, "I:")
+		case strings.HasPrefix(requester, "P:"):
+			rollout, name = &apiv1.PipelineRollout{}, strings.TrimPrefix(requester, "P:")
+		case strings.HasPrefix(requester, "MV:"):
+			rollout, name = &apiv1.MonoVertexRollout{}, strings.TrimPrefix(requester, "MV:")
+		default:
+			continue
+		}
+		if rollout != nil {
+			namespace, name, found := strings.Cut(name, "/")
+			if !found {
+				continue
+			}
+			err := reader.Get(ctx, k8stypes.NamespacedName{Namespace: namespace, Name: name}, rollout)
+			if err == nil {
+				continue
+			}
+			if !apierrors.IsNotFound(err) {
+				return false, fmt.Errorf("failed to get Rollout of pause requester %q: %w", requester, err)
+			}
+		}
+		numaLogger.Infof("removing pause request for %q, whose Rollout no longer exists", requester)
+		delete(records, requester)
+		pruned = true
+	}
+	return pruned, nil
+}
+
+// the PauseModule's state for one requester
+type pauseRequesterState struct {
+	pauseRequest      *bool
+	pauseRequestKnown bool
+	since             time.Time
+	batch             map[string]struct{}
+	batched           bool
+}
+
+// get the state of the requester (caller must hold the lock)
+func (pm *PauseModule) getRequesterState(requester string) pauseRequesterState {
+	state := pauseRequesterState{}
+	state.pauseRequest, state.pauseRequestKnown = pm.pauseRequests[requester]
+	state.since = pm.pauseRequestTimes[requester]
+	state.batch, state.batched = pm.pauseBatches[requester]
+	return state
+}
+
+// set the state of the requester (caller must hold the lock)
+func (pm *PauseModule) setRequesterState(requester string, state pauseRequesterState) {
+	if state.pauseRequestKnown {
+		pm.pauseRequests[requester] = state.pauseRequest
+	} else {
+		delete(pm.pauseRequests, requester)
+	}
+	if !state.since.IsZero() {
+		pm.pauseRequestTimes[requester] = state.since
+	} else {
+		delete(pm.pauseRequestTimes, requester)
+	}
+	if state.batched {
+		pm.pauseBatches[requester] = state.batch
+	} else {
+		delete(pm.pauseBatches, requester)
+	}
+}
+
+// apply a change to the requester's state and persist the Pause Requests, if there's a store
+// the change is made under the lock and returns whether anything changed, but the Pause Requests are written outside of it
+// if they fail to be written, the requester's previous state is restored (unless it changed again since) so that the
+// change can be retried
+// return whether anything changed
+func (pm *PauseModule) updateRequesterState(ctx context.Context, requester string, change func() bool) (bool, error) {
+	pm.lock.Lock()
+	previousState := pm.getRequesterState(requester)
+	if !change() {
+		pm.lock.Unlock()
+		return false, nil
+	}
+	if pm.store == nil {
+		pm.lock.Unlock()
+		return true, nil
+	}
+	newState := pm.getRequesterState(requester)
+	records := pm.snapshotRecords()
+	pm.snapshotVersion++
+	version := pm.snapshotVersion
+	store := pm.store
+	pm.lock.Unlock()
+
+	if err := pm.writer.write(ctx, store, version, records); err != nil {
+		pm.lock.Lock()
+		defer pm.lock.Unlock()
+		if reflect.DeepEqual(pm.getRequesterState(requester), newState) {
+			pm.setRequesterState(requester, previousState)
+		}
+		return false, fmt.Errorf("failed to persist pause request for %q: %w", requester, err)
+	}
+	return true, nil
+}
+
+// get the records of the Pause Requests to persist (caller must hold the lock)
+func (pm *PauseModule) snapshotRecords() map[string]pauseRequestRecord {
+	records := map[string]pauseRequestRecord{}
+	for key, pauseRequest := range pm.pauseRequests {
+		if pauseRequest == nil { // unknown, so nothing worth restoring
+			continue
+		}
+		record := pauseRequestRecord{Pause: *pauseRequest}
+		if since, found := pm.pauseRequestTimes[key]; found {
+			record.Since = &metav1.Time{Time: since}
+		}
+		if pipelineRollouts, batched := pm.pauseBatches[key]; batched {
+			batch := make([]string, 0, len(pipelineRollouts))
+			for pipelineRollout := range pipelineRollouts {
+				batch = append(batch, pipelineRollout)
+			}
+			sort.Strings(batch)
+			record.Batch = &batch
+		}
+		records[key] = record
+	}
+	return records
+}
+
+// pauseRequestWriter writes snapshots of the Pause Requests to the store one at a time, so that an older snapshot never
+// overwrites a newer one
+type pauseRequestWriter struct {
+	lock sync.Mutex
+	// version of the latest snapshot written
+	writtenVersion uint64
+}
+
+func (w *pauseRequestWriter) write(ctx context.Context, store pauseRequestStore, version uint64, records map[string]pauseRequestRecord) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if version <= w.writtenVersion {
+		// a newer snapshot, taken after this one's change, was already written
+		return nil
+	}
+	if err := store.save(ctx, records); err != nil {
+		return err
+	}
+	w.writtenVersion = version
+	return nil
+}
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	commontest "github.com/numaproj/numaplane/tests/common"
+)
+
+// a restart in the middle of an ISBService upgrade mustn't let its Pipelines resume
+func Test_PauseModule_restart(t *testing.T) {
+	_, _, numaplaneClient, k8sClientSet, err := commontest.PrepareK8SEnvironment()
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+	_ = k8sClientSet.CoreV1().ConfigMaps(defaultNamespace).Delete(ctx, pauseRequestsConfigMapName, metav1.DeleteOptions{})
+
+	// the Rollouts requesting pause
+	isbServiceRollout := createISBServiceRollout(createDefaultISBServiceSpec("2.10.3"))
+	controllerRollout := createNumaflowControllerRolloutDef(defaultNamespace, "1.2.0", apiv1.PhaseDeployed, []metav1.Condition{})
+	for _, rollout := range []client.Object{isbServiceRollout, controllerRollout} {
+		_ = numaplaneClient.Delete(ctx, rollout)
+		assert.NoError(t, numaplaneClient.Create(ctx, rollout))
+		defer func() { _ = numaplaneClient.Delete(ctx, rollout) }()
+	}
+
+	// start with an empty PauseModule, and put the original one back once done
+	originalPauseModule := GetPauseModule()
+	defer func() { pauseModuleInstance = originalPauseModule }()
+	pauseModuleInstance = newPauseModule()
+	assert.NoError(t, LoadPauseModule(ctx, k8sClientSet, numaplaneClient, defaultNamespace))
+
+	pm := GetPauseModule()
+	isbsvcKey := pm.getISBServiceKey(defaultNamespace, defaultISBSvcRolloutName)
+	controllerKey := pm.getNumaflowControllerKey(defaultNamespace)
+	pipelineRolloutA := k8stypes.NamespacedName{Namespace: defaultNamespace, Name: "pipeline-a"}
+	pipelineRolloutB := k8stypes.NamespacedName{Namespace: defaultNamespace, Name: "pipeline-b"}
+
+	// the ISBService is pausing its Pipelines in batches and has only reached the first one so far
+	assert.NoError(t, pm.startPauseBatch(ctx, isbsvcKey))
+	_, err = pm.updatePauseRequest(ctx, isbsvcKey, true)
+	assert.NoError(t, err)
+	assert.NoError(t, pm.setPauseBatch(ctx, isbsvcKey, map[string]struct{}{pipelineRolloutA.String(): {}}))
+	_, err = pm.updatePauseRequest(ctx, controllerKey, false)
+	assert.NoError(t, err)
+	// this one is unknown, so it's not restored
+	pm.newPauseRequest(pm.getISBServiceKey(defaultNamespace, "other-isbsvc"))
+	// this one's Rollout no longer exists, so it's not restored either
+	_, err = pm.updatePauseRequest(ctx, pm.getISBServiceKey(defaultNamespace, "deleted-isbsvc"), true)
+	assert.NoError(t, err)
+	pauseBeginTime, _ := pm.getPauseRequestTime(isbsvcKey, pipelineRolloutA)
+
+	// restart
+	pauseModuleInstance = newPauseModule()
+	assert.NoError(t, LoadPauseModule(ctx, k8sClientSet, numaplaneClient, defaultNamespace))
+	pm = GetPauseModule()
+
+	isbsvcPauseRequest, found := pm.getPauseRequest(isbsvcKey)
+	assert.True(t, found)
+	if assert.NotNil(t, isbsvcPauseRequest) {
+		assert.True(t, *isbsvcPauseRequest)
+	}
+	controllerPauseRequest, found := pm.getPauseRequest(controllerKey)
+	assert.True(t, found)
+	if assert.NotNil(t, controllerPauseRequest) {
+		assert.False(t, *controllerPauseRequest)
+	}
+	_, found = pm.getPauseRequest(pm.getISBServiceKey(defaultNamespace, "other-isbsvc"))
+	assert.False(t, found)
+	_, found = pm.getPauseRequest(pm.getISBServiceKey(defaultNamespace, "deleted-isbsvc"))
+	assert.False(t, found)
+
+	paused, _ := pm.getPauseRequestForPipeline(isbsvcKey, pipelineRolloutA)
+	assert.True(t, paused)
+	paused, _ = pm.getPauseRequestForPipeline(isbsvcKey, pipelineRolloutB)
+	assert.False(t, paused)
+	restoredBeginTime, _ := pm.getPauseRequestTime(isbsvcKey, pipelineRolloutA)
+	assert.WithinDuration(t, pauseBeginTime, restoredBeginTime, time.Second)
+
+	// so the Pipeline in the first batch can't be resumed
+	pipeline := &kubernetes.GenericObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "pipeline-a-0"},
+		Spec:       runtime.RawExtension{Raw: []byte(`{"interStepBufferServiceName": "` + defaultISBSvcRolloutName + `"}`)},
+	}
+	resumed, err := pm.runPipelineIfSafe(ctx, numaplaneClient, pipeline)
+	assert.NoError(t, err)
+	assert.False(t, resumed)
+
+	// once the ISBService is done, that's persisted too
+	_, err = pm.updatePauseRequest(ctx, isbsvcKey, false)
+	assert.NoError(t, err)
+	assert.NoError(t, pm.setPauseBatch(ctx, isbsvcKey, nil))
+	pauseModuleInstance = newPauseModule()
+	assert.NoError(t, LoadPauseModule(ctx, k8sClientSet, numaplaneClient, defaultNamespace))
+	paused, _ = GetPauseModule().getPauseRequestForPipeline(isbsvcKey, pipelineRolloutA)
+	assert.False(t, paused)
+	_, batched := GetPauseModule().getPauseBatch(isbsvcKey)
+	assert.False(t, batched)
+}
+
+type failingPauseRequestStore struct{}
+
+func (s *failingPauseRequestStore) load(ctx context.Context) (map[string]pauseRequestRecord, error) {
+	return map[string]pauseRequestRecord{}, nil
+}
+
+func (s *failingPauseRequestStore) save(ctx context.Context, records map[string]pauseRequestRecord) error {
+	return errors.New("unavailable")
+}
+
+// a Pause Request which fails to be persisted is rolled back so that it's retried
+func Test_PauseModule_persistFailure(t *testing.T) {
+	ctx := context.Background()
+	pm := newPauseModule()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, apiv1.AddToScheme(scheme))
+	assert.NoError(t, pm.load(ctx, &failingPauseRequestStore{}, fake.NewClientBuilder().WithScheme(scheme).Build()))
+	requester := pm.getISBServiceKey(defaultNamespace, defaultISBSvcRolloutName)
+	pm.newPauseRequest(requester)
+
+	updated, err := pm.updatePauseRequest(ctx, requester, true)
+	assert.Error(t, err)
+	assert.False(t, updated)
+	pauseRequest, found := pm.getPauseRequest(requester)
+	assert.True(t, found)
+	assert.Nil(t, pauseRequest)
+	_, active := pm.getPauseRequestTime(requester, k8stypes.NamespacedName{Namespace: defaultNamespace, Name: "pipeline-a"})
+	assert.False(t, active)
+}
+
+type memoryPauseRequestStore struct {
+	records map[string]pauseRequestRecord
+}
+
+func (s *memoryPauseRequestStore) load(ctx context.Context) (map[string]pauseRequestRecord, error) {
+	return s.records, nil
+}
+
+func (s *memoryPauseRequestStore) save(ctx context.Context, records map[string]pauseRequestRecord) error {
+	s.records = records
+	return nil
+}
+
+// after a restart, the Pipelines of a cluster-scoped Numaflow Controller must still be covered by its Pause Request
+func Test_PauseModule_loadClusterScopedController(t *testing.T) {
+	ctx := context.Background()
+	pm := newPauseModule()
+	requester := pm.getClusterScopedNumaflowControllerKey("shared")
+	store := &memoryPauseRequestStore{records: map[string]pauseRequestRecord{
+		requester: {Pause: true},
+		// from a cluster-scoped Numaflow Controller which no longer exists
+		pm.getClusterScopedNumaflowControllerKey("deleted"): {Pause: true},
+	}}
+
+	controllerRollout := &apiv1.NumaflowControllerRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "numaplane-system", Name: "numaflow-controller"},
+		Spec: apiv1.NumaflowControllerRolloutSpec{
+			Controller:    apiv1.Controller{Version: "1.2.0", InstanceID: "shared"},
+			ClusterScoped: true,
+		},
+		Status: apiv1.NumaflowControllerRolloutStatus{ManagedNamespaces: []string{"team-a"}},
+	}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, apiv1.AddToScheme(scheme))
+	assert.NoError(t, pm.load(ctx, store, fake.NewClientBuilder().WithScheme(scheme).WithObjects(controllerRollout).Build()))
+
+	assert.Equal(t, requester, pm.getNumaflowControllerKeyForPipeline("team-a", "shared"))
+	assert.Equal(t, pm.getNumaflowControllerKey("team-b"), pm.getNumaflowControllerKeyForPipeline("team-b", "shared"))
+	paused, found := pm.getPauseRequestForPipeline(requester, k8stypes.NamespacedName{Namespace: "team-a", Name: "pipeline-a"})
+	assert.True(t, found)
+	assert.True(t, paused)
+	_, found = pm.getPauseRequest(pm.getClusterScopedNumaflowControllerKey("deleted"))
+	assert.False(t, found)
+	assert.NotContains(t, store.records, pm.getClusterScopedNumaflowControllerKey("deleted"))
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	"github.com/numaproj/numaplane/internal/util/metrics"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+const (
+	// reasons for the ProgressDeadlineExceeded Condition
+	progressDeadlineReasonExceeded   = "DeadlineExceeded"
+	progressDeadlineReasonProgressed = "Progressed"
+)
+
+// get the progress deadline of a Rollout: its own progressDeadlineSeconds if set, or else the global one
+// return zero if there's no deadline
+func getProgressDeadline(rolloutDeadlineSeconds *int32) time.Duration {
+	if rolloutDeadlineSeconds != nil {
+		return time.Duration(*rolloutDeadlineSeconds) * time.Second
+	}
+	globalConfig, err := config.GetConfigManagerInstance().GetConfig()
+	if err != nil || globalConfig.ProgressDeadlineSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(globalConfig.ProgressDeadlineSeconds) * time.Second
+}
+
+// if the Rollout has been progressing towards deploying its child for longer than its progress deadline, set the
+// ProgressDeadlineExceeded Condition with what it's waiting for, and the first time, emit a Warning event and increment
+// the metric; once it's deployed, the Condition is set back to false
+// Time spent waiting for a maintenance window doesn't count towards the deadline, and the deadline isn't enforced while a
+// Progressive upgrade is waiting to be promoted manually.
+// return the result with a requeue no later than the deadline, so that it's detected even if nothing else changes
+func processProgressDeadline(ctx context.Context, rollout revisionedRollout, rolloutDeadlineSeconds *int32,
+	controllerType string, recorder record.EventRecorder, customMetrics *metrics.CustomMetrics, result ctrl.Result) ctrl.Result {
+
+	numaLogger := logger.FromContext(ctx)
+	status := rollout.GetStatus()
+
+	condition := status.GetCondition(apiv1.ConditionProgressDeadlineExceeded)
+	if status.Phase != apiv1.PhasePending || status.ProgressingSince == nil {
+		if condition != nil && condition.Status == metav1.ConditionTrue {
+			status.MarkFalse(apiv1.ConditionProgressDeadlineExceeded, progressDeadlineReasonProgressed, "Rollout is no longer progressing", rollout.GetGeneration())
+		}
+		return result
+	}
+
+	progressDeadline := getProgressDeadline(rolloutDeadlineSeconds)
+	if progressDeadline == 0 {
+		return result
+	}
+
+	if progressiveRollout, ok := rollout.(ProgressiveRolloutObject); ok &&
+		progressiveRollout.GetProgressiveStatus().State == apiv1.ProgressiveStateAwaitingPromotion {
+		return result
+	}
+
+	progressingSince := status.ProgressingSince.Time
+	windowCondition := status.GetCondition(apiv1.ConditionAwaitingMaintenanceWindow)
+	if windowCondition != nil {
+		if windowCondition.Status == metav1.ConditionTrue {
+			return result
+		}
+		if windowCondition.LastTransitionTime.After(progressingSince) {
+			progressingSince = windowCondition.LastTransitionTime.Time
+		}
+	}
+
+	if remaining := time.Until(progressingSince.Add(progressDeadline)); remaining > 0 {
+		if result.RequeueAfter == 0 || remaining < result.RequeueAfter {
+			result.RequeueAfter = remaining
+		}
+		return result
+	}
+
+	waitingFor := status.WaitingFor
+	if waitingFor == "" {
+		waitingFor = "the child to be deployed"
+	}
+	message := fmt.Sprintf("Rollout has been progressing since %s, exceeding its progress deadline of %s; waiting for %s",
+		progressingSince.UTC().Format(time.RFC3339), progressDeadline, waitingFor)
+
+	// only record exceeding the deadline once each time it's exceeded
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		numaLogger.Warn(message)
+		recorder.Event(rollout, corev1.EventTypeWarning, "ProgressDeadlineExceeded", message)
+		customMetrics.ProgressDeadlineExceeded.WithLabelValues(controllerType, rollout.GetNamespace(), rollout.GetName()).Inc()
+	}
+	status.MarkTrueWithReason(apiv1.ConditionProgressDeadlineExceeded, progressDeadlineReasonExceeded, message, rollout.GetGeneration())
+	return result
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/numaproj/numaplane/internal/util/metrics"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_processProgressDeadline(t *testing.T) {
+	ctx := context.Background()
+
+	// other tests may call this, but it fails if called more than once
+	if customMetrics == nil {
+		customMetrics = metrics.RegisterCustomMetrics()
+	}
+	recorder := record.NewFakeRecorder(64)
+
+	deadlineSeconds := int32(60)
+	pipelineRollout := createPipelineRollout(pipelineSpec, map[string]string{}, map[string]string{})
+	pipelineRollout.Spec.ProgressDeadlineSeconds = &deadlineSeconds
+	exceededCount := func() float64 {
+		return testutil.ToFloat64(customMetrics.ProgressDeadlineExceeded.WithLabelValues(ControllerPipelineRollout, defaultNamespace, defaultPipelineRolloutName))
+	}
+	initialCount := exceededCount()
+
+	// within the deadline: requeue for when it's reached
+	pipelineRollout.Status.MarkPending()
+	pipelineRollout.Status.MarkWaitingFor("Pipeline %s to pause before it's updated", pipelineRollout.Name)
+	result := processProgressDeadline(ctx, pipelineRollout, pipelineRollout.Spec.ProgressDeadlineSeconds, ControllerPipelineRollout, recorder, customMetrics, ctrl.Result{})
+	assert.Nil(t, pipelineRollout.Status.GetCondition(apiv1.ConditionProgressDeadlineExceeded))
+	assert.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= time.Minute)
+	// (an earlier requeue is kept)
+	result = processProgressDeadline(ctx, pipelineRollout, pipelineRollout.Spec.ProgressDeadlineSeconds, ControllerPipelineRollout, recorder, customMetrics, ctrl.Result{RequeueAfter: time.Second})
+	assert.Equal(t, time.Second, result.RequeueAfter)
+
+	// time waiting for a maintenance window doesn't count
+	pipelineRollout.Status.ProgressingSince = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+	pipelineRollout.Status.MarkTrueWithReason(apiv1.ConditionAwaitingMaintenanceWindow, "OutsideMaintenanceWindow", "waiting", pipelineRollout.Generation)
+	_ = processProgressDeadline(ctx, pipelineRollout, pipelineRollout.Spec.ProgressDeadlineSeconds, ControllerPipelineRollout, recorder, customMetrics, ctrl.Result{})
+	assert.Nil(t, pipelineRollout.Status.GetCondition(apiv1.ConditionProgressDeadlineExceeded))
+	pipelineRollout.Status.Conditions = nil
+
+	// past the deadline: the Condition says what it's waiting for, and it's only recorded once
+	for i := 0; i < 2; i++ {
+		_ = processProgressDeadline(ctx, pipelineRollout, pipelineRollout.Spec.ProgressDeadlineSeconds, ControllerPipelineRollout, recorder, customMetrics, ctrl.Result{})
+	}
+	condition := pipelineRollout.Status.GetCondition(apiv1.ConditionProgressDeadlineExceeded)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Contains(t, condition.Message, "waiting for Pipeline "+pipelineRollout.Name+" to pause before it's updated")
+	}
+	assert.Equal(t, initialCount+1, exceededCount())
+	assert.Len(t, recorder.Events, 1)
+
+	// a Rollout with no deadline is never stuck
+	noDeadline := int32(0)
+	pipelineRollout.Status.Conditions = nil
+	_ = processProgressDeadline(ctx, pipelineRollout, &noDeadline, ControllerPipelineRollout, recorder, customMetrics, ctrl.Result{})
+	assert.Nil(t, pipelineRollout.Status.GetCondition(apiv1.ConditionProgressDeadlineExceeded))
+
+	// once deployed, it's no longer exceeded
+	_ = processProgressDeadline(ctx, pipelineRollout, pipelineRollout.Spec.ProgressDeadlineSeconds, ControllerPipelineRollout, recorder, customMetrics, ctrl.Result{})
+	pipelineRollout.Status.MarkDeployed(pipelineRollout.Generation)
+	assert.Nil(t, pipelineRollout.Status.ProgressingSince)
+	assert.Empty(t, pipelineRollout.Status.WaitingFor)
+	_ = processProgressDeadline(ctx, pipelineRollout, pipelineRollout.Spec.ProgressDeadlineSeconds, ControllerPipelineRollout, recorder, customMetrics, ctrl.Result{})
+	condition = pipelineRollout.Status.GetCondition(apiv1.ConditionProgressDeadlineExceeded)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	}
+}
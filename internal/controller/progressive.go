@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -10,8 +11,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/util"
 	"github.com/numaproj/numaplane/internal/util/kubernetes"
 	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
 )
 
 // progressiveController describes a Controller that can progressively roll out a second child alongside the original child,
@@ -21,7 +24,10 @@ type progressiveController interface {
 	listChildren(ctx context.Context, rolloutObject RolloutObject, labelSelector string, fieldSelector string) ([]*kubernetes.GenericObject, error)
 
 	// createBaseChildDefinition creates a Kubernetes definition for a child resource of the Rollout with the given name
-	createBaseChildDefinition(rolloutObject RolloutObject, name string) (*kubernetes.GenericObject, error)
+	createBaseChildDefinition(ctx context.Context, rolloutObject RolloutObject, name string) (*kubernetes.GenericObject, error)
+
+	// getCurrentChildCount returns the index that will be used for the next child, and whether it's been set yet
+	getCurrentChildCount(rolloutObject RolloutObject) (int32, bool)
 
 	// incrementChildCount updates the count of children for the Resource in Kubernetes and returns the index that should be used for the next child
 	incrementChildCount(ctx context.Context, rolloutObject RolloutObject) (int32, error)
@@ -39,40 +45,421 @@ type progressiveController interface {
 	merge(existingObj *kubernetes.GenericObject, newObj *kubernetes.GenericObject) (*kubernetes.GenericObject, error)
 }
 
+// processResourceWithProgressive drives the Progressive upgrade state machine, whose state is persisted in the Rollout's Status:
+// Creating -> Assessing -> [AwaitingPromotion ->] Promoting -> Draining -> Done
+// (AwaitingPromotion only if the strategy requires manual promotion)
+// Every step is idempotent, so if the controller restarts (or fails to update the Status) partway through a step, the step can
+// just be repeated.
 // return whether we're done, and error if any
-func processResourceWithProgressive(ctx context.Context, rolloutObject RolloutObject,
+func processResourceWithProgressive(ctx context.Context, rolloutObject ProgressiveRolloutObject,
 	existingPromotedChild *kubernetes.GenericObject, controller progressiveController, c client.Client) (bool, error) {
 
 	numaLogger := logger.FromContext(ctx)
 
-	newUpgradingChildDef, err := makeUpgradingObjectDefinition(ctx, rolloutObject, controller)
+	progressiveStatus := rolloutObject.GetProgressiveStatus()
+	if !progressiveUpgradeStarted(rolloutObject) {
+		numaLogger.Debugf("Starting Progressive upgrade from child %s/%s", existingPromotedChild.Namespace, existingPromotedChild.Name)
+		progressiveStatus.State = apiv1.ProgressiveStateCreating
+		progressiveStatus.PromotedChildName = existingPromotedChild.Name
+		progressiveStatus.UpgradingChildName = ""
+		progressiveStatus.Canary = nil
+		progressiveStatus.AwaitingPromotionSince = nil
+
+		// record that we've started before modifying any children, so that if we're interrupted we pick up where we left off
+		if err := persistProgressiveStatus(ctx, rolloutObject, c); err != nil {
+			return false, fmt.Errorf("error starting Progressive upgrade: %v", err)
+		}
+	}
+
+	for {
+		state := progressiveStatus.State
+		numaLogger.Debugf("Progressive upgrade state: %s", state)
+
+		var nextState apiv1.ProgressiveState
+		var err error
+		switch state {
+		case apiv1.ProgressiveStateCreating:
+			nextState, err = createUpgradingChild(ctx, rolloutObject, controller, c)
+		case apiv1.ProgressiveStateAssessing:
+			nextState, err = assessUpgradingChild(ctx, rolloutObject, controller, c)
+		case apiv1.ProgressiveStateAwaitingPromotion:
+			nextState, err = awaitPromotion(ctx, rolloutObject, controller, c)
+		case apiv1.ProgressiveStatePromoting:
+			nextState, err = promoteUpgradingChild(ctx, rolloutObject, controller, c)
+		case apiv1.ProgressiveStateDraining:
+			nextState, err = drainPromotedChild(ctx, rolloutObject, controller)
+		default:
+			return false, fmt.Errorf("invalid Progressive upgrade state %q", state)
+		}
+		if err != nil {
+			return false, err
+		}
+
+		progressiveStatus.State = nextState
+		if nextState == apiv1.ProgressiveStateDone {
+			// any request to promote or abort applied to this upgrade, not the next one
+			return true, clearProgressiveRequests(ctx, rolloutObject, c)
+		}
+		if nextState == state {
+			markProgressiveWaitingFor(rolloutObject, state)
+			//continue (re-enqueue)
+			return false, nil
+		}
+	}
+}
+
+// record in the Rollout's Status what the Progressive upgrade is waiting for in the given state
+func markProgressiveWaitingFor(rolloutObject ProgressiveRolloutObject, state apiv1.ProgressiveState) {
+	progressiveStatus := rolloutObject.GetProgressiveStatus()
+	status := rolloutObject.GetStatus()
+	switch state {
+	case apiv1.ProgressiveStateCreating:
+		status.MarkWaitingFor("upgrading child %s to be created", progressiveStatus.UpgradingChildName)
+	case apiv1.ProgressiveStateAssessing:
+		status.MarkWaitingFor("upgrading child %s to be assessed as healthy", progressiveStatus.UpgradingChildName)
+	case apiv1.ProgressiveStateAwaitingPromotion:
+		waiting := "0s"
+		if progressiveStatus.AwaitingPromotionSince != nil {
+			waiting = time.Since(progressiveStatus.AwaitingPromotionSince.Time).Round(time.Second).String()
+		}
+		status.MarkWaitingFor("upgrading child %s to be promoted manually (waiting %s): annotate the Rollout with %s=true to promote it or %s=true to abort",
+			progressiveStatus.UpgradingChildName, waiting, common.AnnotationKeyPromote, common.AnnotationKeyAbort)
+	case apiv1.ProgressiveStatePromoting:
+		status.MarkWaitingFor("upgrading child %s to be promoted", progressiveStatus.UpgradingChildName)
+	case apiv1.ProgressiveStateDraining:
+		status.MarkWaitingFor("previously promoted child %s to be drained", progressiveStatus.PromotedChildName)
+	}
+}
+
+// persistProgressiveStatus records the Rollout's ProgressiveStatus in Kubernetes
+// Only the ProgressiveStatus is written, onto the Status last persisted, so that the rest of the Status, which the caller has yet
+// to update, isn't persisted partway through the reconcile. The whole Status is updated (at the caller's resourceVersion), since
+// a patch could either fail when there's no Status yet or leave behind fields which have since been cleared; the new
+// resourceVersion is copied back for the caller's update.
+func persistProgressiveStatus(ctx context.Context, rolloutObject ProgressiveRolloutObject, c client.Client) error {
+	persistedRollout := rolloutObject.DeepCopyObject().(ProgressiveRolloutObject)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(rolloutObject), persistedRollout); err != nil {
+		return err
+	}
+	*persistedRollout.GetProgressiveStatus() = *rolloutObject.GetProgressiveStatus().DeepCopy()
+	persistedRollout.SetResourceVersion(rolloutObject.GetResourceVersion())
+	if err := c.Status().Update(ctx, persistedRollout); err != nil {
+		return err
+	}
+	rolloutObject.SetResourceVersion(persistedRollout.GetResourceVersion())
+	return nil
+}
+
+// progressiveUpgradeStarted returns true if a Progressive upgrade has begun and not yet finished: once begun, it needs to run to
+// completion, even if the Rollout's promoted child no longer appears to need updating
+func progressiveUpgradeStarted(rolloutObject ProgressiveRolloutObject) bool {
+	state := rolloutObject.GetProgressiveStatus().State
+	return state != "" && state != apiv1.ProgressiveStateDone
+}
+
+// createUpgradingChild creates the upgrading child if it doesn't exist yet
+// return the next state, and error if any
+func createUpgradingChild(ctx context.Context, rolloutObject ProgressiveRolloutObject, controller progressiveController, c client.Client) (apiv1.ProgressiveState, error) {
+	numaLogger := logger.FromContext(ctx)
+
+	// if we already chose a name for the child, keep it; otherwise, if we created the child already, we'll find it here by its label
+	var newUpgradingChildDef *kubernetes.GenericObject
+	var err error
+	progressiveStatus := rolloutObject.GetProgressiveStatus()
+	if progressiveStatus.UpgradingChildName != "" {
+		newUpgradingChildDef, err = makeUpgradingObjectDefinitionWithName(ctx, rolloutObject, controller, progressiveStatus.UpgradingChildName)
+	} else {
+		newUpgradingChildDef, err = makeUpgradingObjectDefinition(ctx, rolloutObject, controller)
+	}
 	if err != nil {
-		return false, err
+		return apiv1.ProgressiveStateCreating, err
 	}
+	progressiveStatus.UpgradingChildName = newUpgradingChildDef.Name
 
-	// Get the Upgrading object to see if it exists
-	existingUpgradingChildDef, err := kubernetes.GetLiveResource(ctx, newUpgradingChildDef, rolloutObject.GetChildPluralName())
+	existingUpgradingChildDef, err := getLiveChild(ctx, rolloutObject, controller, newUpgradingChildDef.Name)
 	if err != nil {
-		// create object as it doesn't exist
-		if apierrors.IsNotFound(err) {
-			numaLogger.Debugf("Upgrading child of type %s %s/%s doesn't exist so creating", newUpgradingChildDef.Kind, newUpgradingChildDef.Namespace, newUpgradingChildDef.Name)
-			err = kubernetes.CreateResource(ctx, c, newUpgradingChildDef)
-			return false, err
-		} else {
-			return false, fmt.Errorf("error getting %s: %v", newUpgradingChildDef.Kind, err)
+		return apiv1.ProgressiveStateCreating, err
+	}
+	if existingUpgradingChildDef == nil {
+		numaLogger.Debugf("Upgrading child of type %s %s/%s doesn't exist so creating", newUpgradingChildDef.Kind, newUpgradingChildDef.Namespace, newUpgradingChildDef.Name)
+		if newUpgradingChildDef, err = withInitialCanaryReplicas(ctx, rolloutObject, controller, newUpgradingChildDef); err != nil {
+			return apiv1.ProgressiveStateCreating, err
+		}
+		if err = kubernetes.CreateResource(ctx, c, newUpgradingChildDef); err != nil {
+			return apiv1.ProgressiveStateCreating, err
 		}
 	}
-	newUpgradingChildDef, err = controller.merge(existingUpgradingChildDef, newUpgradingChildDef)
+	return apiv1.ProgressiveStateAssessing, nil
+}
+
+// assessUpgradingChild makes sure the upgrading child has the latest spec and determines if it's healthy and passes Analysis
+// return the next state, and error if any
+func assessUpgradingChild(ctx context.Context, rolloutObject ProgressiveRolloutObject, controller progressiveController, c client.Client) (apiv1.ProgressiveState, error) {
+	numaLogger := logger.FromContext(ctx)
+	upgradingChildName := rolloutObject.GetProgressiveStatus().UpgradingChildName
+
+	existingUpgradingChildDef, err := getLiveChild(ctx, rolloutObject, controller, upgradingChildName)
 	if err != nil {
-		return false, err
+		return apiv1.ProgressiveStateAssessing, err
+	}
+	if existingUpgradingChildDef == nil {
+		numaLogger.Infof("Upgrading child %s no longer exists, recreating", upgradingChildName)
+		return apiv1.ProgressiveStateCreating, nil
+	}
+
+	if progressiveRequested(rolloutObject, common.AnnotationKeyAbort) {
+		numaLogger.Infof("Progressive upgrade aborted by %s annotation", common.AnnotationKeyAbort)
+		rolloutObject.GetStatus().MarkProgressiveUpgradeFailed("Progressive upgrade aborted", rolloutObject.GetObjectMeta().Generation)
+		return rollBackUpgradingChild(ctx, rolloutObject, controller, existingUpgradingChildDef, c)
 	}
 
-	done, err := processUpgradingChild(ctx, rolloutObject, controller, existingPromotedChild, newUpgradingChildDef, existingUpgradingChildDef, c)
+	desiredUpgradingChildDef, err := makeUpgradingObjectDefinitionWithName(ctx, rolloutObject, controller, upgradingChildName)
 	if err != nil {
-		return false, err
+		return apiv1.ProgressiveStateAssessing, err
+	}
+	desiredUpgradingChildDef, err = controller.merge(existingUpgradingChildDef, desiredUpgradingChildDef)
+	if err != nil {
+		return apiv1.ProgressiveStateAssessing, err
+	}
+
+	upgradingObjectStatus, err := kubernetes.ParseStatus(existingUpgradingChildDef)
+	if err != nil {
+		return apiv1.ProgressiveStateAssessing, err
+	}
+
+	numaLogger.Debugf("Upgrading child %s/%s is in phase %s", existingUpgradingChildDef.Namespace, existingUpgradingChildDef.Name, upgradingObjectStatus.Phase)
+
+	switch string(upgradingObjectStatus.Phase) {
+	case "Failed":
+
+		rolloutObject.GetStatus().MarkProgressiveUpgradeFailed("New Child Object Failed", rolloutObject.GetObjectMeta().Generation)
+		if rolloutObject.GetProgressiveStrategy().AutoRollback {
+			return rollBackUpgradingChild(ctx, rolloutObject, controller, existingUpgradingChildDef, c)
+		}
+		// without rolling back, the upgrade waits for the user to fix the spec: once they do, apply it to the upgrading child so
+		// that it can recover
+		childNeedsToUpdate, err := controller.childNeedsUpdating(ctx, existingUpgradingChildDef, desiredUpgradingChildDef)
+		if err != nil {
+			return apiv1.ProgressiveStateAssessing, err
+		}
+		if childNeedsToUpdate {
+			numaLogger.Infof("Applying updated spec to failed upgrading child %s/%s", existingUpgradingChildDef.Namespace, existingUpgradingChildDef.Name)
+			return apiv1.ProgressiveStateAssessing, kubernetes.UpdateResource(ctx, c, desiredUpgradingChildDef)
+		}
+		return apiv1.ProgressiveStateAssessing, nil
+
+	case "Running":
+		if !isNumaflowChildReady(&upgradingObjectStatus) {
+			return apiv1.ProgressiveStateAssessing, nil
+		}
+		canarySteps := getCanarySteps(rolloutObject, controller)
+		if rolloutObject.GetProgressiveStrategy().Analysis != nil || len(canarySteps) > 0 {
+			// make sure we assess the latest spec: if the user changed it, update the child, which restarts the Analysis
+			childNeedsToUpdate, err := controller.childNeedsUpdating(ctx, existingUpgradingChildDef, desiredUpgradingChildDef)
+			if err != nil {
+				return apiv1.ProgressiveStateAssessing, err
+			}
+			if childNeedsToUpdate {
+				numaLogger.Debugf("Upgrading child %s/%s has a new update", existingUpgradingChildDef.Namespace, existingUpgradingChildDef.Name)
+				return apiv1.ProgressiveStateAssessing, kubernetes.UpdateResource(ctx, c, desiredUpgradingChildDef)
+			}
+		}
+
+		// the child is healthy, but it can only be promoted once it completes any canary steps and passes Analysis
+		analysisDone, analysisPassed := true, true
+		if len(canarySteps) > 0 {
+			canaryController, ok := controller.(canaryController)
+			if !ok {
+				return apiv1.ProgressiveStateAssessing, fmt.Errorf("canary steps are defined but %T doesn't support canary upgrades", controller)
+			}
+			analysisDone, analysisPassed, err = runCanarySteps(ctx, rolloutObject, canaryController, canarySteps, existingUpgradingChildDef)
+			if err != nil {
+				return apiv1.ProgressiveStateAssessing, err
+			}
+		}
+		if analysisDone && analysisPassed {
+			analysisDone, analysisPassed, err = analyzeUpgradingChild(ctx, rolloutObject.GetProgressiveStrategy().Analysis, rolloutObject.GetProgressiveStatus(), existingUpgradingChildDef)
+			if err != nil {
+				return apiv1.ProgressiveStateAssessing, err
+			}
+		}
+		if !analysisDone {
+			return apiv1.ProgressiveStateAssessing, nil
+		}
+		if !analysisPassed {
+			// keep the promoted child as is
+			rolloutObject.GetStatus().MarkProgressiveUpgradeFailed(analysisFailureMessage(rolloutObject.GetProgressiveStatus().Analysis), rolloutObject.GetObjectMeta().Generation)
+			if rolloutObject.GetProgressiveStrategy().AutoRollback {
+				return rollBackUpgradingChild(ctx, rolloutObject, controller, existingUpgradingChildDef, c)
+			}
+			return apiv1.ProgressiveStateAssessing, nil
+		}
+		if rolloutObject.GetProgressiveStrategy().ManualPromotion {
+			numaLogger.Infof("Upgrading child %s/%s passed assessment, awaiting manual promotion", existingUpgradingChildDef.Namespace, existingUpgradingChildDef.Name)
+			now := metav1.Now()
+			rolloutObject.GetProgressiveStatus().AwaitingPromotionSince = &now
+			return apiv1.ProgressiveStateAwaitingPromotion, nil
+		}
+		return apiv1.ProgressiveStatePromoting, nil
+
+	default:
+		// Ensure the latest spec is applied
+		// TODO: this needs revisiting - a race condition means we could deem this "Running" prior to latest version
+		// being reconciled
+
+		childNeedsToUpdate, err := controller.childNeedsUpdating(ctx, existingUpgradingChildDef, desiredUpgradingChildDef) // TODO: if we decide not to drain the upgrading one on failure, I think we can change this to DeepEqual() check
+		if err != nil {
+			return apiv1.ProgressiveStateAssessing, err
+		}
+		if childNeedsToUpdate {
+			numaLogger.Debugf("Upgrading child %s/%s has a new update", existingUpgradingChildDef.Namespace, existingUpgradingChildDef.Name)
+
+			err = kubernetes.UpdateResource(ctx, c, desiredUpgradingChildDef)
+			if err != nil {
+				return apiv1.ProgressiveStateAssessing, err
+			}
+		}
+		return apiv1.ProgressiveStateAssessing, nil
+	}
+}
+
+// awaitPromotion holds the upgrading child, which has passed assessment, until the user annotates the Rollout to promote it or
+// to abort the upgrade; if meanwhile the user changes the child spec, or the child stops being healthy, it's assessed again
+// return the next state, and error if any
+func awaitPromotion(ctx context.Context, rolloutObject ProgressiveRolloutObject, controller progressiveController, c client.Client) (apiv1.ProgressiveState, error) {
+	numaLogger := logger.FromContext(ctx)
+	progressiveStatus := rolloutObject.GetProgressiveStatus()
+
+	upgradingChild, err := getLiveChild(ctx, rolloutObject, controller, progressiveStatus.UpgradingChildName)
+	if err != nil {
+		return apiv1.ProgressiveStateAwaitingPromotion, err
+	}
+	if upgradingChild == nil {
+		numaLogger.Infof("Upgrading child %s no longer exists, recreating", progressiveStatus.UpgradingChildName)
+		progressiveStatus.AwaitingPromotionSince = nil
+		return apiv1.ProgressiveStateCreating, nil
+	}
+
+	if progressiveRequested(rolloutObject, common.AnnotationKeyAbort) {
+		numaLogger.Infof("Progressive upgrade aborted by %s annotation", common.AnnotationKeyAbort)
+		rolloutObject.GetStatus().MarkProgressiveUpgradeFailed("Progressive upgrade aborted", rolloutObject.GetObjectMeta().Generation)
+		nextState, err := rollBackUpgradingChild(ctx, rolloutObject, controller, upgradingChild, c)
+		if err != nil {
+			return apiv1.ProgressiveStateAwaitingPromotion, err
+		}
+		progressiveStatus.AwaitingPromotionSince = nil
+		return nextState, nil
+	}
+
+	desiredUpgradingChildDef, err := makeUpgradingObjectDefinitionWithName(ctx, rolloutObject, controller, progressiveStatus.UpgradingChildName)
+	if err != nil {
+		return apiv1.ProgressiveStateAwaitingPromotion, err
+	}
+	desiredUpgradingChildDef, err = controller.merge(upgradingChild, desiredUpgradingChildDef)
+	if err != nil {
+		return apiv1.ProgressiveStateAwaitingPromotion, err
+	}
+	childNeedsToUpdate, err := controller.childNeedsUpdating(ctx, upgradingChild, desiredUpgradingChildDef)
+	if err != nil {
+		return apiv1.ProgressiveStateAwaitingPromotion, err
+	}
+	if childNeedsToUpdate {
+		numaLogger.Infof("Upgrading child %s/%s has a new update, assessing it again", upgradingChild.Namespace, upgradingChild.Name)
+		if err = kubernetes.UpdateResource(ctx, c, desiredUpgradingChildDef); err != nil {
+			return apiv1.ProgressiveStateAwaitingPromotion, err
+		}
+		progressiveStatus.AwaitingPromotionSince = nil
+		return apiv1.ProgressiveStateAssessing, nil
+	}
+
+	upgradingObjectStatus, err := kubernetes.ParseStatus(upgradingChild)
+	if err != nil {
+		return apiv1.ProgressiveStateAwaitingPromotion, err
+	}
+	if upgradingObjectStatus.Phase != "Running" || !isNumaflowChildReady(&upgradingObjectStatus) {
+		numaLogger.Infof("Upgrading child %s/%s is no longer healthy, assessing it again", upgradingChild.Namespace, upgradingChild.Name)
+		progressiveStatus.AwaitingPromotionSince = nil
+		return apiv1.ProgressiveStateAssessing, nil
+	}
+
+	if progressiveRequested(rolloutObject, common.AnnotationKeyPromote) {
+		numaLogger.Infof("Promoting upgrading child %s/%s as requested by %s annotation", upgradingChild.Namespace, upgradingChild.Name, common.AnnotationKeyPromote)
+		progressiveStatus.AwaitingPromotionSince = nil
+		return apiv1.ProgressiveStatePromoting, nil
+	}
+	if progressiveStatus.AwaitingPromotionSince == nil {
+		now := metav1.Now()
+		progressiveStatus.AwaitingPromotionSince = &now
+	}
+	return apiv1.ProgressiveStateAwaitingPromotion, nil
+}
+
+// promoteUpgradingChild labels the upgrading child "promoted" and then the previously promoted child "recyclable"
+// (note that if we're interrupted in between, both children are labeled "promoted" until this step is repeated - see getChildName())
+// return the next state, and error if any
+func promoteUpgradingChild(ctx context.Context, rolloutObject ProgressiveRolloutObject, controller progressiveController, c client.Client) (apiv1.ProgressiveState, error) {
+	progressiveStatus := rolloutObject.GetProgressiveStatus()
+
+	upgradingChild, err := getLiveChild(ctx, rolloutObject, controller, progressiveStatus.UpgradingChildName)
+	if err != nil {
+		return apiv1.ProgressiveStatePromoting, err
+	}
+	if upgradingChild == nil {
+		return apiv1.ProgressiveStatePromoting, fmt.Errorf("upgrading child %s not found", progressiveStatus.UpgradingChildName)
+	}
+	if err = completeCanary(ctx, rolloutObject, controller, upgradingChild); err != nil {
+		return apiv1.ProgressiveStatePromoting, err
 	}
+	if err = updateUpgradeState(ctx, c, common.LabelValueUpgradePromoted, upgradingChild, rolloutObject); err != nil {
+		return apiv1.ProgressiveStatePromoting, err
+	}
+
+	promotedChild, err := getLiveChild(ctx, rolloutObject, controller, progressiveStatus.PromotedChildName)
+	if err != nil {
+		return apiv1.ProgressiveStatePromoting, err
+	}
+	if promotedChild != nil {
+		if err = updateUpgradeState(ctx, c, common.LabelValueUpgradeRecyclable, promotedChild, rolloutObject); err != nil {
+			return apiv1.ProgressiveStatePromoting, err
+		}
+	}
+
+	rolloutObject.GetStatus().MarkProgressiveUpgradeSucceeded("New Child Object Running", rolloutObject.GetObjectMeta().Generation)
+	rolloutObject.GetStatus().MarkDeployed(rolloutObject.GetObjectMeta().Generation)
+	progressiveStatus.FailedSpecHash = ""
+
+	return apiv1.ProgressiveStateDraining, nil
+}
+
+// drainPromotedChild drains the previously promoted child, after which it will be garbage collected
+// return the next state, and error if any
+func drainPromotedChild(ctx context.Context, rolloutObject ProgressiveRolloutObject, controller progressiveController) (apiv1.ProgressiveState, error) {
+	promotedChild, err := getLiveChild(ctx, rolloutObject, controller, rolloutObject.GetProgressiveStatus().PromotedChildName)
+	if err != nil {
+		return apiv1.ProgressiveStateDraining, err
+	}
+	if promotedChild != nil {
+		if err = controller.drain(ctx, promotedChild); err != nil {
+			return apiv1.ProgressiveStateDraining, err
+		}
+	}
+	return apiv1.ProgressiveStateDone, nil
+}
 
-	return done, nil
+// getLiveChild gets the child of the Rollout with the given name from Kubernetes, or nil if it doesn't exist
+func getLiveChild(ctx context.Context, rolloutObject RolloutObject, controller progressiveController, name string) (*kubernetes.GenericObject, error) {
+	childDef, err := controller.createBaseChildDefinition(ctx, rolloutObject, name)
+	if err != nil {
+		return nil, err
+	}
+	child, err := kubernetes.GetLiveResource(ctx, childDef, rolloutObject.GetChildPluralName())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting %s %s/%s: %v", childDef.Kind, childDef.Namespace, name, err)
+	}
+	return child, nil
 }
 
 // create the definition for the child of the Rollout which is the one labeled "upgrading"
@@ -85,7 +472,11 @@ func makeUpgradingObjectDefinition(ctx context.Context, rolloutObject RolloutObj
 		return nil, err
 	}
 	numaLogger.Debugf("Upgrading child: %s", childName)
-	upgradingChild, err := controller.createBaseChildDefinition(rolloutObject, childName)
+	return makeUpgradingObjectDefinitionWithName(ctx, rolloutObject, controller, childName)
+}
+
+func makeUpgradingObjectDefinitionWithName(ctx context.Context, rolloutObject RolloutObject, controller progressiveController, childName string) (*kubernetes.GenericObject, error) {
+	upgradingChild, err := controller.createBaseChildDefinition(ctx, rolloutObject, childName)
 	if err != nil {
 		return nil, err
 	}
@@ -96,95 +487,133 @@ func makeUpgradingObjectDefinition(ctx context.Context, rolloutObject RolloutObj
 }
 
 func getChildName(ctx context.Context, rolloutObject RolloutObject, controller progressiveController, upgradeState string) (string, error) {
+	childName, found, err := findChildName(ctx, rolloutObject, controller, upgradeState)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		index, err := controller.incrementChildCount(ctx, rolloutObject)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s-%d", rolloutObject.GetObjectMeta().Name, index), nil
+	}
+	return childName, nil
+}
+
+// findChildName returns the name of the existing child in the given upgrade state, if there is one
+func findChildName(ctx context.Context, rolloutObject RolloutObject, controller progressiveController, upgradeState string) (string, bool, error) {
 	children, err := controller.listChildren(ctx, rolloutObject, fmt.Sprintf(
 		"%s=%s,%s=%s", common.LabelKeyParentRollout, rolloutObject.GetObjectMeta().Name,
 		common.LabelKeyUpgradeState, upgradeState,
 	), "")
 
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 	if len(children) > 1 {
-		return "", fmt.Errorf("there should only be one promoted or upgrade in progress pipeline")
-	} else if len(children) == 0 {
-		index, err := controller.incrementChildCount(ctx, rolloutObject)
-		if err != nil {
-			return "", err
+		// if a Progressive upgrade was interrupted while promoting, both the old and the new child may be labeled "promoted":
+		// the new one is the one that's staying
+		if progressiveRolloutObject, ok := rolloutObject.(ProgressiveRolloutObject); ok && upgradeState == string(common.LabelValueUpgradePromoted) {
+			upgradingChildName := progressiveRolloutObject.GetProgressiveStatus().UpgradingChildName
+			for _, child := range children {
+				if child.Name == upgradingChildName {
+					return child.Name, true, nil
+				}
+			}
 		}
-		return fmt.Sprintf("%s-%d", rolloutObject.GetObjectMeta().Name, index), nil
+		return "", false, fmt.Errorf("there should only be one promoted or upgrade in progress pipeline")
+	} else if len(children) == 0 {
+		return "", false, nil
 	}
-	return children[0].Name, nil
+	return children[0].Name, true, nil
 }
 
-// return whether we're done, and error if any
-func processUpgradingChild(
+// nextChildName returns the name that getChildName would give a new child, without reserving it
+func nextChildName(rolloutObject RolloutObject, controller progressiveController) string {
+	index, _ := controller.getCurrentChildCount(rolloutObject)
+	return fmt.Sprintf("%s-%d", rolloutObject.GetObjectMeta().Name, index)
+}
+
+// rollBackUpgradingChild drains the failed upgrading child and marks it "recyclable" so that it gets garbage collected, leaving the
+// promoted child in place. The failed child spec is recorded so that it won't be retried until the user changes it.
+// return the next state, and error if any
+func rollBackUpgradingChild(
 	ctx context.Context,
-	rolloutObject RolloutObject,
+	rolloutObject ProgressiveRolloutObject,
 	controller progressiveController,
-	existingPromotedChildDef, desiredUpgradingChildDef, existingUpgradingChildDef *kubernetes.GenericObject,
+	upgradingChildDef *kubernetes.GenericObject,
 	c client.Client,
-) (bool, error) {
+) (apiv1.ProgressiveState, error) {
 	numaLogger := logger.FromContext(ctx)
-	upgradingObjectStatus, err := kubernetes.ParseStatus(existingUpgradingChildDef)
+
+	specHash, err := getChildSpecHash(ctx, rolloutObject, controller)
 	if err != nil {
-		return false, err
+		return apiv1.ProgressiveStateAssessing, err
 	}
 
-	numaLogger.Debugf("Upgrading child %s/%s is in phase %s", existingUpgradingChildDef.Namespace, existingUpgradingChildDef.Name, upgradingObjectStatus.Phase)
-
-	switch string(upgradingObjectStatus.Phase) {
-	case "Failed":
-
-		rolloutObject.GetStatus().MarkProgressiveUpgradeFailed("New Child Object Failed", rolloutObject.GetObjectMeta().Generation)
-		return false, nil
-
-	case "Running":
-		if !isNumaflowChildReady(&upgradingObjectStatus) {
-			//continue (re-enqueue)
-			return false, nil
-		}
-		// Label the new child as promoted and then remove the label from the old one
-		err := updateUpgradeState(ctx, c, common.LabelValueUpgradePromoted, existingUpgradingChildDef, rolloutObject)
-		if err != nil {
-			return false, err
-		}
-
-		// TODO: if any of the below items fail, then we return false which keeps us in UpgradeStrategyInProgress=Progressive, but due to "if pipelineNeedsToUpdate" check in pipelinerollout_controller.go, we never come back here
-		// to complete the below logic
-		// Consider upgrading to "promoted" as the last thing?
+	numaLogger.Infof("Rolling back failed upgrade: removing child %s/%s", upgradingChildDef.Namespace, upgradingChildDef.Name)
+	if err := abortCanary(ctx, rolloutObject, controller); err != nil {
+		return apiv1.ProgressiveStateAssessing, err
+	}
+	if err := controller.drain(ctx, upgradingChildDef); err != nil {
+		return apiv1.ProgressiveStateAssessing, err
+	}
+	if err := updateUpgradeState(ctx, c, common.LabelValueUpgradeRecyclable, upgradingChildDef, rolloutObject); err != nil {
+		return apiv1.ProgressiveStateAssessing, err
+	}
 
-		err = updateUpgradeState(ctx, c, common.LabelValueUpgradeRecyclable, existingPromotedChildDef, rolloutObject)
-		if err != nil {
-			return false, err
-		}
+	rolloutObject.GetProgressiveStatus().FailedSpecHash = specHash
+	return apiv1.ProgressiveStateDone, nil
+}
 
-		rolloutObject.GetStatus().MarkProgressiveUpgradeSucceeded("New Child Object Running", rolloutObject.GetObjectMeta().Generation)
-		rolloutObject.GetStatus().MarkDeployed(rolloutObject.GetObjectMeta().Generation)
+// progressiveRequested returns whether the given annotation, requesting to promote or abort the Progressive upgrade, is set
+// to "true" on the Rollout
+func progressiveRequested(rolloutObject ProgressiveRolloutObject, annotation string) bool {
+	return rolloutObject.GetAnnotations()[annotation] == "true"
+}
 
-		if err := controller.drain(ctx, existingPromotedChildDef); err != nil {
-			return false, err
+// clearProgressiveRequests removes any annotations requesting to promote or abort the Progressive upgrade from the Rollout
+// The Rollout is patched rather than updated so that its Status, which the caller has yet to update, isn't overwritten.
+func clearProgressiveRequests(ctx context.Context, rolloutObject ProgressiveRolloutObject, c client.Client) error {
+	annotations := rolloutObject.GetAnnotations()
+	if _, found := annotations[common.AnnotationKeyPromote]; !found {
+		if _, found := annotations[common.AnnotationKeyAbort]; !found {
+			return nil
 		}
-		return true, nil
-	default:
-		// Ensure the latest spec is applied
-		// TODO: this needs revisiting - a race condition means we could deem this "Running" prior to latest version
-		// being reconciled
+	}
 
-		childNeedsToUpdate, err := controller.childNeedsUpdating(ctx, existingUpgradingChildDef, desiredUpgradingChildDef) // TODO: if we decide not to drain the upgrading one on failure, I think we can change this to DeepEqual() check
-		if err != nil {
-			return false, err
-		}
-		if childNeedsToUpdate {
-			numaLogger.Debugf("Upgrading child %s/%s has a new update", existingUpgradingChildDef.Namespace, existingUpgradingChildDef.Name)
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:null,%q:null}}}`, common.AnnotationKeyPromote, common.AnnotationKeyAbort))
+	patchedRollout := rolloutObject.DeepCopyObject().(client.Object)
+	if err := c.Patch(ctx, patchedRollout, client.RawPatch(k8stypes.MergePatchType, patch)); err != nil {
+		return fmt.Errorf("error removing promote and abort annotations from %s: %w", rolloutObject.GetName(), err)
+	}
+	rolloutObject.SetAnnotations(patchedRollout.GetAnnotations())
+	rolloutObject.SetResourceVersion(patchedRollout.GetResourceVersion())
+	return nil
+}
 
-			err = kubernetes.UpdateResource(ctx, c, desiredUpgradingChildDef)
-			if err != nil {
-				return false, err
-			}
-		}
-		//continue (re-enqueue)
+// progressiveUpgradePreviouslyFailed determines if the child spec currently defined by the Rollout is the one whose upgrade
+// last failed and was rolled back, in which case it shouldn't be retried until the user changes it
+func progressiveUpgradePreviouslyFailed(ctx context.Context, rolloutObject ProgressiveRolloutObject, controller progressiveController) (bool, error) {
+	failedSpecHash := rolloutObject.GetProgressiveStatus().FailedSpecHash
+	if failedSpecHash == "" {
 		return false, nil
 	}
+	specHash, err := getChildSpecHash(ctx, rolloutObject, controller)
+	if err != nil {
+		return false, err
+	}
+	return specHash == failedSpecHash, nil
+}
+
+// getChildSpecHash returns a hash of the child spec defined by the Rollout
+func getChildSpecHash(ctx context.Context, rolloutObject RolloutObject, controller progressiveController) (string, error) {
+	childDef, err := controller.createBaseChildDefinition(ctx, rolloutObject, rolloutObject.GetObjectMeta().Name)
+	if err != nil {
+		return "", err
+	}
+	return util.JSONHash(childDef.Spec.Raw)
 }
 
 // update the in-memory object with the new Label and patch the object in K8S
@@ -249,7 +678,12 @@ func recycle(ctx context.Context,
 	if err != nil {
 		return err
 	}
-	if isDrained {
+	// a child which failed may never finish draining, so there's no reason to wait for it
+	childStatus, err := kubernetes.ParseStatus(childObject)
+	if err != nil {
+		return err
+	}
+	if isDrained || childStatus.Phase == "Failed" {
 		err = kubernetes.DeleteResource(ctx, c, childObject)
 		if err != nil {
 			return err
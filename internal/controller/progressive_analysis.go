@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/numaproj/numaplane/internal/analysis"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// newMetricsProvider creates the MetricsProvider used to evaluate an Analysis
+// (this is a variable so that unit tests can substitute a fake provider)
+var newMetricsProvider = func(analysisSpec *apiv1.Analysis) (analysis.MetricsProvider, error) {
+	return analysis.NewPrometheusProvider(analysisSpec.Prometheus.Address)
+}
+
+// analyzeUpgradingChild runs the given Analysis, if any, against the upgrading child, which is assumed to be healthy.
+// It returns whether the assessment is complete and, if so, whether it passed.
+// Progress is recorded in the Rollout's ProgressiveStatus so that the assessment continues across reconciliations.
+func analyzeUpgradingChild(ctx context.Context, analysisSpec *apiv1.Analysis, progressiveStatus *apiv1.ProgressiveStatus, upgradingChild *kubernetes.GenericObject) (bool, bool, error) {
+	numaLogger := logger.FromContext(ctx)
+
+	if analysisSpec == nil {
+		progressiveStatus.Analysis = nil
+		return true, true, nil
+	}
+
+	// (re)start the Analysis if this is a different child than the last one analyzed or if the child has since been updated
+	analysisStatus := progressiveStatus.Analysis
+	if analysisStatus == nil || analysisStatus.ChildName != upgradingChild.Name || analysisStatus.ChildGeneration != upgradingChild.Generation {
+		numaLogger.Debugf("starting Analysis of child %s/%s", upgradingChild.Namespace, upgradingChild.Name)
+		analysisStatus = &apiv1.AnalysisStatus{
+			ChildName:       upgradingChild.Name,
+			ChildGeneration: upgradingChild.Generation,
+			Phase:           apiv1.AnalysisPhaseRunning,
+			StartTime:       metav1.NewTime(time.Now()),
+		}
+		progressiveStatus.Analysis = analysisStatus
+	}
+	if analysisStatus.IsDone() {
+		return true, analysisStatus.Phase == apiv1.AnalysisPhaseSuccessful, nil
+	}
+
+	window := time.Duration(0)
+	if analysisSpec.Window != nil {
+		window = analysisSpec.Window.Duration
+	}
+
+	provider, err := newMetricsProvider(analysisSpec)
+	if err != nil {
+		completeAnalysis(analysisStatus, apiv1.AnalysisPhaseFailed, err.Error())
+		return true, false, nil
+	}
+	results, passed, err := analysis.Evaluate(ctx, provider, analysisSpec.Metrics,
+		analysis.NewTemplateArgs(upgradingChild.Namespace, upgradingChild.Name, window))
+	if err != nil {
+		return false, false, err
+	}
+	analysisStatus.MetricResults = results
+
+	if !passed {
+		completeAnalysis(analysisStatus, apiv1.AnalysisPhaseFailed, "one or more metrics did not satisfy their threshold")
+		return true, false, nil
+	}
+
+	if elapsed := time.Since(analysisStatus.StartTime.Time); elapsed < window {
+		numaLogger.Debugf("Analysis of child %s/%s passing so far, %s remaining", upgradingChild.Namespace, upgradingChild.Name, window-elapsed)
+		return false, false, nil
+	}
+
+	completeAnalysis(analysisStatus, apiv1.AnalysisPhaseSuccessful, "all metrics satisfied their threshold")
+	return true, true, nil
+}
+
+func completeAnalysis(analysisStatus *apiv1.AnalysisStatus, phase apiv1.AnalysisPhase, message string) {
+	now := metav1.NewTime(time.Now())
+	analysisStatus.Phase = phase
+	analysisStatus.Message = message
+	analysisStatus.EndTime = &now
+}
+
+// analysisFailureMessage describes the failed Analysis for the Rollout's Conditions
+func analysisFailureMessage(analysisStatus *apiv1.AnalysisStatus) string {
+	if analysisStatus == nil {
+		return "Analysis failed"
+	}
+	return fmt.Sprintf("Analysis of child %s failed: %s", analysisStatus.ChildName, analysisStatus.Message)
+}
@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// canaryController describes a progressiveController that can divide the replicas of the promoted child between the promoted
+// and upgrading children in steps, so that the upgrading child gradually takes on the load
+type canaryController interface {
+	progressiveController
+
+	// canarySteps returns the canary steps defined for the Rollout, if any
+	canarySteps(rolloutObject RolloutObject) []apiv1.CanaryStep
+
+	// getReplicas returns the number of replicas defined in the child's spec
+	getReplicas(child *kubernetes.GenericObject) (int32, error)
+
+	// withReplicas returns a copy of the child definition with the given number of replicas
+	withReplicas(child *kubernetes.GenericObject, replicas int32) (*kubernetes.GenericObject, error)
+
+	// scale updates the number of replicas of the child in Kubernetes
+	scale(ctx context.Context, child *kubernetes.GenericObject, replicas int32) error
+}
+
+// getCanarySteps returns the canary steps for the Rollout, or nil if the controller doesn't support canary
+func getCanarySteps(rolloutObject RolloutObject, controller progressiveController) []apiv1.CanaryStep {
+	if canary, ok := controller.(canaryController); ok {
+		return canary.canarySteps(rolloutObject)
+	}
+	return nil
+}
+
+// runCanarySteps moves the upgrading child, which is assumed to be healthy, through the canary steps.
+// It returns whether the steps are complete and, if so, whether they passed.
+// Progress is recorded in the Rollout's ProgressiveStatus so that the steps continue across reconciliations.
+func runCanarySteps(ctx context.Context, rolloutObject ProgressiveRolloutObject, controller canaryController, steps []apiv1.CanaryStep,
+	upgradingChild *kubernetes.GenericObject) (bool, bool, error) {
+	numaLogger := logger.FromContext(ctx)
+	progressiveStatus := rolloutObject.GetProgressiveStatus()
+
+	// the canary is normally started when the upgrading child is created, but it may have been created before we recorded that
+	canaryStatus := progressiveStatus.Canary
+	if canaryStatus == nil || canaryStatus.ChildName != upgradingChild.Name {
+		return false, false, startCanary(ctx, rolloutObject, controller, upgradingChild.Name)
+	}
+
+	for int(canaryStatus.CurrentStepIndex) < len(steps) {
+		step := steps[canaryStatus.CurrentStepIndex]
+
+		if canaryStatus.StepStartTime == nil {
+			numaLogger.Debugf("canary step %d: setting weight of child %s/%s to %d%%", canaryStatus.CurrentStepIndex, upgradingChild.Namespace, upgradingChild.Name, step.Weight)
+			if err := setCanaryWeight(ctx, rolloutObject, controller, upgradingChild, step.Weight); err != nil {
+				return false, false, err
+			}
+			now := metav1.NewTime(time.Now())
+			canaryStatus.StepStartTime = &now
+			canaryStatus.Weight = step.Weight
+			progressiveStatus.Analysis = nil
+			// let the children settle at their new scale before assessing
+			return false, false, nil
+		}
+
+		analysisDone, analysisPassed, err := analyzeUpgradingChild(ctx, step.Analysis, progressiveStatus, upgradingChild)
+		if err != nil || !analysisDone || !analysisPassed {
+			return analysisDone, analysisPassed, err
+		}
+		if step.Pause != nil && time.Since(canaryStatus.StepStartTime.Time) < step.Pause.Duration {
+			return false, false, nil
+		}
+
+		canaryStatus.CurrentStepIndex++
+		canaryStatus.StepStartTime = nil
+	}
+
+	return true, true, nil
+}
+
+// startCanary records in the Rollout's ProgressiveStatus the replicas to divide between the children, before changing anything,
+// so that they're not based on a promoted child we already scaled down; if the upgrading child is being recreated, its canary
+// starts over from the first step
+func startCanary(ctx context.Context, rolloutObject ProgressiveRolloutObject, controller canaryController, upgradingChildName string) error {
+	numaLogger := logger.FromContext(ctx)
+	progressiveStatus := rolloutObject.GetProgressiveStatus()
+
+	if canaryStatus := progressiveStatus.Canary; canaryStatus != nil && canaryStatus.ChildName == upgradingChildName {
+		progressiveStatus.Canary = &apiv1.CanaryStatus{ChildName: upgradingChildName, TotalReplicas: canaryStatus.TotalReplicas}
+		return nil
+	}
+
+	promotedChild, err := getLiveChild(ctx, rolloutObject, controller, progressiveStatus.PromotedChildName)
+	if err != nil {
+		return err
+	}
+	if promotedChild == nil {
+		return fmt.Errorf("promoted child %s not found", progressiveStatus.PromotedChildName)
+	}
+	totalReplicas, err := controller.getReplicas(promotedChild)
+	if err != nil {
+		return err
+	}
+	numaLogger.Debugf("starting canary of child %s with %d total replicas", upgradingChildName, totalReplicas)
+	progressiveStatus.Canary = &apiv1.CanaryStatus{ChildName: upgradingChildName, TotalReplicas: totalReplicas}
+	return nil
+}
+
+// withInitialCanaryReplicas starts the canary, if the Rollout defines canary steps, and returns the definition of the upgrading
+// child to create with its share of the replicas for the first step, so that it never runs with more than its weight
+func withInitialCanaryReplicas(ctx context.Context, rolloutObject ProgressiveRolloutObject, controller progressiveController,
+	upgradingChild *kubernetes.GenericObject) (*kubernetes.GenericObject, error) {
+	canary, ok := controller.(canaryController)
+	if !ok {
+		return upgradingChild, nil
+	}
+	steps := canary.canarySteps(rolloutObject)
+	if len(steps) == 0 {
+		return upgradingChild, nil
+	}
+	if err := startCanary(ctx, rolloutObject, canary, upgradingChild.Name); err != nil {
+		return nil, err
+	}
+	_, upgradingReplicas := canaryReplicas(rolloutObject.GetProgressiveStatus().Canary.TotalReplicas, steps[0].Weight)
+	return canary.withReplicas(upgradingChild, upgradingReplicas)
+}
+
+// setCanaryWeight gives the upgrading child the given percentage of the total replicas, and the promoted child the rest
+func setCanaryWeight(ctx context.Context, rolloutObject ProgressiveRolloutObject, controller canaryController, upgradingChild *kubernetes.GenericObject, weight int32) error {
+	progressiveStatus := rolloutObject.GetProgressiveStatus()
+	promotedReplicas, upgradingReplicas := canaryReplicas(progressiveStatus.Canary.TotalReplicas, weight)
+
+	promotedChild, err := getLiveChild(ctx, rolloutObject, controller, progressiveStatus.PromotedChildName)
+	if err != nil {
+		return err
+	}
+	if promotedChild != nil {
+		if err = controller.scale(ctx, promotedChild, promotedReplicas); err != nil {
+			return err
+		}
+	}
+	return controller.scale(ctx, upgradingChild, upgradingReplicas)
+}
+
+// completeCanary gives the upgrading child all of the replicas once it's being promoted
+func completeCanary(ctx context.Context, rolloutObject ProgressiveRolloutObject, controller progressiveController, upgradingChild *kubernetes.GenericObject) error {
+	canary, ok := controller.(canaryController)
+	canaryStatus := rolloutObject.GetProgressiveStatus().Canary
+	if !ok || canaryStatus == nil || canaryStatus.ChildName != upgradingChild.Name {
+		return nil
+	}
+	return canary.scale(ctx, upgradingChild, canaryStatus.TotalReplicas)
+}
+
+// abortCanary gives the promoted child back all of the replicas when the upgrade is rolled back
+func abortCanary(ctx context.Context, rolloutObject ProgressiveRolloutObject, controller progressiveController) error {
+	canary, ok := controller.(canaryController)
+	progressiveStatus := rolloutObject.GetProgressiveStatus()
+	if !ok || progressiveStatus.Canary == nil || progressiveStatus.Canary.ChildName != progressiveStatus.UpgradingChildName {
+		return nil
+	}
+	promotedChild, err := getLiveChild(ctx, rolloutObject, controller, progressiveStatus.PromotedChildName)
+	if err != nil || promotedChild == nil {
+		return err
+	}
+	return canary.scale(ctx, promotedChild, progressiveStatus.Canary.TotalReplicas)
+}
+
+// canaryReplicas divides the total replicas between the promoted and upgrading children according to the upgrading child's weight:
+// the upgrading child's share is rounded up, so that it gets at least one replica unless its weight is 0, but it never gets more
+// than the total
+func canaryReplicas(totalReplicas int32, weight int32) (int32, int32) {
+	upgradingReplicas := min((totalReplicas*weight+99)/100, totalReplicas)
+	if weight <= 0 {
+		upgradingReplicas = 0
+	}
+	return totalReplicas - upgradingReplicas, upgradingReplicas
+}
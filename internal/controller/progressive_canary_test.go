@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/metrics"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	commontest "github.com/numaproj/numaplane/tests/common"
+)
+
+func Test_canaryReplicas(t *testing.T) {
+	tests := []struct {
+		name                      string
+		totalReplicas             int32
+		weight                    int32
+		expectedPromotedReplicas  int32
+		expectedUpgradingReplicas int32
+	}{
+		{name: "weight 0", totalReplicas: 10, weight: 0, expectedPromotedReplicas: 10, expectedUpgradingReplicas: 0},
+		{name: "even split", totalReplicas: 10, weight: 50, expectedPromotedReplicas: 5, expectedUpgradingReplicas: 5},
+		{name: "rounds up for upgrading child", totalReplicas: 3, weight: 50, expectedPromotedReplicas: 1, expectedUpgradingReplicas: 2},
+		{name: "small weight still gets a replica", totalReplicas: 4, weight: 10, expectedPromotedReplicas: 3, expectedUpgradingReplicas: 1},
+		{name: "weight 100", totalReplicas: 4, weight: 100, expectedPromotedReplicas: 0, expectedUpgradingReplicas: 4},
+		{name: "no replicas", totalReplicas: 0, weight: 10, expectedPromotedReplicas: 0, expectedUpgradingReplicas: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promotedReplicas, upgradingReplicas := canaryReplicas(tt.totalReplicas, tt.weight)
+			assert.Equal(t, tt.expectedPromotedReplicas, promotedReplicas)
+			assert.Equal(t, tt.expectedUpgradingReplicas, upgradingReplicas)
+		})
+	}
+}
+
+func Test_canarySteps(t *testing.T) {
+	restConfig, numaflowClientSet, numaplaneClient, _, err := commontest.PrepareK8SEnvironment()
+	assert.Nil(t, err)
+	assert.Nil(t, kubernetes.SetDynamicClient(restConfig))
+	ctx := context.Background()
+
+	// other tests may call this, but it fails if called more than once
+	if customMetrics == nil {
+		customMetrics = metrics.RegisterCustomMetrics()
+	}
+	r := NewMonoVertexRolloutReconciler(numaplaneClient, scheme.Scheme, customMetrics, record.NewFakeRecorder(64))
+
+	const (
+		rolloutName        = "canary-test"
+		promotedChildName  = "canary-test-0"
+		upgradingChildName = "canary-test-1"
+	)
+
+	getReplicas := func(t *testing.T, name string) int32 {
+		monoVertex, err := numaflowClientSet.NumaflowV1alpha1().MonoVertices(defaultNamespace).Get(ctx, name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		if monoVertex.Spec.Replicas == nil {
+			return 1
+		}
+		return *monoVertex.Spec.Replicas
+	}
+
+	// create the MonoVertexRollout with a promoted MonoVertex of 4 replicas, in the Creating state of a Progressive upgrade
+	setUp := func(t *testing.T) *apiv1.MonoVertexRollout {
+		_ = numaflowClientSet.NumaflowV1alpha1().MonoVertices(defaultNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+
+		monoVertexSpec := fakeMonoVertexSpec(t)
+		monoVertexSpec.Replicas = ptr.To(int32(4))
+		monoVertexSpecRaw, err := json.Marshal(monoVertexSpec)
+		assert.NoError(t, err)
+		rollout := &apiv1.MonoVertexRollout{
+			ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: rolloutName},
+			Spec: apiv1.MonoVertexRolloutSpec{
+				MonoVertex: apiv1.MonoVertex{Spec: runtime.RawExtension{Raw: monoVertexSpecRaw}},
+				Strategy: &apiv1.MonoVertexRolloutStrategy{
+					Canary: &apiv1.CanaryStrategy{Steps: []apiv1.CanaryStep{{Weight: 25}, {Weight: 50}}},
+				},
+			},
+		}
+		_ = numaplaneClient.Delete(ctx, rollout)
+		assert.NoError(t, numaplaneClient.Create(ctx, rollout))
+		rollout.Status.ProgressiveStatus = apiv1.ProgressiveStatus{
+			State:              apiv1.ProgressiveStateCreating,
+			PromotedChildName:  promotedChildName,
+			UpgradingChildName: upgradingChildName,
+		}
+
+		promotedChild, err := r.createBaseChildDefinition(ctx, rollout, promotedChildName)
+		assert.NoError(t, err)
+		promotedChild.Labels[common.LabelKeyUpgradeState] = string(common.LabelValueUpgradePromoted)
+		assert.NoError(t, kubernetes.CreateResource(ctx, numaplaneClient, promotedChild))
+		return rollout
+	}
+
+	t.Run("steps through the weights and completes", func(t *testing.T) {
+		rollout := setUp(t)
+
+		// the upgrading child is created with its share of the first step
+		nextState, err := createUpgradingChild(ctx, rollout, r, numaplaneClient)
+		assert.NoError(t, err)
+		assert.Equal(t, apiv1.ProgressiveStateAssessing, nextState)
+		if assert.NotNil(t, rollout.Status.ProgressiveStatus.Canary) {
+			assert.Equal(t, int32(4), rollout.Status.ProgressiveStatus.Canary.TotalReplicas)
+		}
+		assert.Equal(t, int32(1), getReplicas(t, upgradingChildName))
+		assert.Equal(t, int32(4), getReplicas(t, promotedChildName))
+
+		upgradingChild, err := getLiveChild(ctx, rollout, r, upgradingChildName)
+		assert.NoError(t, err)
+
+		// first step: the promoted child gives up its share
+		done, passed, err := runCanarySteps(ctx, rollout, r, r.canarySteps(rollout), upgradingChild)
+		assert.NoError(t, err)
+		assert.False(t, done)
+		assert.False(t, passed)
+		assert.Equal(t, int32(25), rollout.Status.ProgressiveStatus.Canary.Weight)
+		assert.Equal(t, int32(3), getReplicas(t, promotedChildName))
+		assert.Equal(t, int32(1), getReplicas(t, upgradingChildName))
+
+		// second step
+		done, _, err = runCanarySteps(ctx, rollout, r, r.canarySteps(rollout), upgradingChild)
+		assert.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, int32(1), rollout.Status.ProgressiveStatus.Canary.CurrentStepIndex)
+		assert.Equal(t, int32(50), rollout.Status.ProgressiveStatus.Canary.Weight)
+		assert.Equal(t, int32(2), getReplicas(t, promotedChildName))
+		assert.Equal(t, int32(2), getReplicas(t, upgradingChildName))
+
+		done, passed, err = runCanarySteps(ctx, rollout, r, r.canarySteps(rollout), upgradingChild)
+		assert.NoError(t, err)
+		assert.True(t, done)
+		assert.True(t, passed)
+
+		// promoting gives the upgrading child all of the replicas
+		assert.NoError(t, completeCanary(ctx, rollout, r, upgradingChild))
+		assert.Equal(t, int32(4), getReplicas(t, upgradingChildName))
+	})
+
+	t.Run("abort gives the promoted child back its replicas", func(t *testing.T) {
+		rollout := setUp(t)
+
+		_, err := createUpgradingChild(ctx, rollout, r, numaplaneClient)
+		assert.NoError(t, err)
+		upgradingChild, err := getLiveChild(ctx, rollout, r, upgradingChildName)
+		assert.NoError(t, err)
+		_, _, err = runCanarySteps(ctx, rollout, r, r.canarySteps(rollout), upgradingChild)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), getReplicas(t, promotedChildName))
+
+		assert.NoError(t, abortCanary(ctx, rollout, r))
+		assert.Equal(t, int32(4), getReplicas(t, promotedChildName))
+	})
+
+	t.Run("canary is started for an upgrading child created before it was recorded", func(t *testing.T) {
+		rollout := setUp(t)
+
+		upgradingChild, err := r.createBaseChildDefinition(ctx, rollout, upgradingChildName)
+		assert.NoError(t, err)
+		upgradingChild.Labels[common.LabelKeyUpgradeState] = string(common.LabelValueUpgradeInProgress)
+		assert.NoError(t, kubernetes.CreateResource(ctx, numaplaneClient, upgradingChild))
+
+		done, _, err := runCanarySteps(ctx, rollout, r, r.canarySteps(rollout), upgradingChild)
+		assert.NoError(t, err)
+		assert.False(t, done)
+		if assert.NotNil(t, rollout.Status.ProgressiveStatus.Canary) {
+			assert.Equal(t, upgradingChildName, rollout.Status.ProgressiveStatus.Canary.ChildName)
+			assert.Equal(t, int32(4), rollout.Status.ProgressiveStatus.Canary.TotalReplicas)
+		}
+
+		_, _, err = runCanarySteps(ctx, rollout, r, r.canarySteps(rollout), upgradingChild)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), getReplicas(t, promotedChildName))
+		assert.Equal(t, int32(1), getReplicas(t, upgradingChildName))
+	})
+}
+
+func Test_withReplicas(t *testing.T) {
+	r := &MonoVertexRolloutReconciler{}
+	monoVertex := fakeGenericMonoVertex(t, fakeMonoVertexSpec(t))
+
+	result, err := r.withReplicas(monoVertex, 3)
+	assert.NoError(t, err)
+	replicas, err := r.getReplicas(result)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), replicas)
+
+	// the original definition is left as is
+	replicas, err = r.getReplicas(monoVertex)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), replicas)
+}
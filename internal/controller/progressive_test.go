@@ -0,0 +1,517 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/metrics"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	commontest "github.com/numaproj/numaplane/tests/common"
+)
+
+// writeFailureInjector fails the Nth write to Kubernetes, simulating the controller crashing at that point
+type writeFailureInjector struct {
+	failAt int
+	writes int
+	fired  bool
+	// whether the write which failed was the one recording that the Progressive upgrade started
+	firedOnUpgradeStart bool
+}
+
+func (injector *writeFailureInjector) write() error {
+	injector.writes++
+	if injector.writes == injector.failAt {
+		injector.fired = true
+		return errors.New("injected failure")
+	}
+	return nil
+}
+
+func (injector *writeFailureInjector) writeStatus(obj client.Object) error {
+	err := injector.write()
+	if rollout, ok := obj.(*apiv1.PipelineRollout); ok && err != nil {
+		progressiveStatus := rollout.Status.ProgressiveStatus
+		injector.firedOnUpgradeStart = progressiveStatus.State == apiv1.ProgressiveStateCreating && progressiveStatus.UpgradingChildName == ""
+	}
+	return err
+}
+
+func (injector *writeFailureInjector) funcs() interceptor.Funcs {
+	return interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if err := injector.write(); err != nil {
+				return err
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if err := injector.write(); err != nil {
+				return err
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if err := injector.write(); err != nil {
+				return err
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			if err := injector.write(); err != nil {
+				return err
+			}
+			return c.Delete(ctx, obj, opts...)
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			if err := injector.writeStatus(obj); err != nil {
+				return err
+			}
+			return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			if err := injector.writeStatus(obj); err != nil {
+				return err
+			}
+			return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+		},
+	}
+}
+
+// Progressive upgrade should complete correctly no matter which write to Kubernetes fails along the way:
+// each test case fails a different write, after which the controller "restarts" from the Rollout Status last persisted
+func Test_processResourceWithProgressive_Resumable(t *testing.T) {
+	restConfig, numaflowClientSet, numaplaneClient, _, err := commontest.PrepareK8SEnvironment()
+	assert.Nil(t, err)
+	assert.Nil(t, kubernetes.SetDynamicClient(restConfig))
+
+	config.GetConfigManagerInstance().UpdateUSDEConfig(config.USDEConfig{
+		DefaultUpgradeStrategy:    config.ProgressiveStrategyID,
+		PipelineSpecExcludedPaths: []string{"watermark", "lifecycle"},
+	})
+	ctx := context.Background()
+
+	// other tests may call this, but it fails if called more than once
+	if customMetrics == nil {
+		customMetrics = metrics.RegisterCustomMetrics()
+	}
+
+	healthyStatus := numaflowv1.PipelineStatus{
+		Phase: numaflowv1.PipelinePhaseRunning,
+		Status: numaflowv1.Status{
+			Conditions: []metav1.Condition{
+				{
+					Type:   string(numaflowv1.PipelineConditionDaemonServiceHealthy),
+					Status: metav1.ConditionTrue,
+				},
+			},
+		},
+	}
+
+	const maxReconciliations = 10
+
+	// keep failing a later write until the upgrade completes without reaching the failure
+	failedOnUpgradeStart := false
+	for failAt := 1; ; failAt++ {
+		injector := &writeFailureInjector{failAt: failAt}
+
+		t.Run(fmt.Sprintf("fail write %d", failAt), func(t *testing.T) {
+			// first delete Pipelines and PipelineRollout in case they already exist, in Kubernetes
+			_ = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+			pipelineList, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).List(ctx, metav1.ListOptions{})
+			assert.NoError(t, err)
+			assert.Len(t, pipelineList.Items, 0)
+
+			rollout := createPipelineRollout(pipelineSpecWithTopologyChange, map[string]string{}, map[string]string{})
+			_ = numaplaneClient.Delete(ctx, rollout)
+			rollout.Status.Phase = apiv1.PhaseDeployed
+			rollout.Status.NameCount = new(int32)
+			*rollout.Status.NameCount++
+			rollout.Status.UpgradeInProgress = apiv1.UpgradeStrategyNoOp
+			rollout.Status.Init(rollout.Generation)
+			rolloutStatus := rollout.Status
+			assert.NoError(t, numaplaneClient.Create(ctx, rollout))
+			rollout.Status = rolloutStatus
+			assert.NoError(t, numaplaneClient.Status().Update(ctx, rollout))
+
+			// the original promoted Pipeline
+			existingPipeline := createPipeline(numaflowv1.PipelinePhaseRunning, numaflowv1.Status{}, false, map[string]string{
+				common.LabelKeyUpgradeState:  string(common.LabelValueUpgradePromoted),
+				common.LabelKeyParentRollout: defaultPipelineRolloutName,
+			})
+			existingPipeline.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(rollout.GetObjectMeta(), apiv1.PipelineRolloutGroupVersionKind)}
+			existingPipeline, err = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Create(ctx, existingPipeline, metav1.CreateOptions{})
+			assert.NoError(t, err)
+			existingPipeline.Status = healthyStatus
+			_, err = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).UpdateStatus(ctx, existingPipeline, metav1.UpdateOptions{})
+			assert.NoError(t, err)
+
+			watchClient, err := client.NewWithWatch(restConfig, client.Options{})
+			assert.NoError(t, err)
+			faultyClient := interceptor.NewClient(watchClient, injector.funcs())
+
+			var r *PipelineRolloutReconciler
+			for i := 0; i < maxReconciliations; i++ {
+				// (re)start the controller with no memory of what happened before
+				if r == nil {
+					r = NewPipelineRolloutReconciler(faultyClient, scheme.Scheme, customMetrics, record.NewFakeRecorder(64))
+				}
+
+				rollout = &apiv1.PipelineRollout{}
+				assert.NoError(t, numaplaneClient.Get(ctx, client.ObjectKey{Namespace: defaultNamespace, Name: defaultPipelineRolloutName}, rollout))
+				if rollout.Status.ProgressiveStatus.State == apiv1.ProgressiveStateDone && rollout.Status.UpgradeInProgress == apiv1.UpgradeStrategyNoOp {
+					break
+				}
+
+				rollout.Status.Init(rollout.Generation)
+				_, _, err = r.reconcile(ctx, rollout, time.Now())
+				if err == nil {
+					err = r.updatePipelineRolloutStatus(ctx, rollout)
+				}
+				if err != nil {
+					r = nil
+				}
+
+				// the Numaflow controller would eventually make any new Pipeline healthy
+				pipelineList, err = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).List(ctx, metav1.ListOptions{})
+				assert.NoError(t, err)
+				for _, pipeline := range pipelineList.Items {
+					if pipeline.Name != defaultPipelineName && pipeline.Status.Phase == "" {
+						pipeline.Status = healthyStatus
+						_, err = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).UpdateStatus(ctx, &pipeline, metav1.UpdateOptions{})
+						assert.NoError(t, err)
+					}
+				}
+			}
+
+			////// check results:
+			assert.Equal(t, apiv1.ProgressiveStateDone, rollout.Status.ProgressiveStatus.State)
+			assert.Equal(t, apiv1.UpgradeStrategyNoOp, rollout.Status.UpgradeInProgress)
+			assert.Equal(t, apiv1.PhaseDeployed, rollout.Status.Phase)
+
+			// there should be exactly one promoted Pipeline, with the new spec
+			pipelineList, err = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).List(ctx, metav1.ListOptions{})
+			assert.NoError(t, err)
+			promoted := 0
+			for _, pipeline := range pipelineList.Items {
+				upgradeState := pipeline.Labels[common.LabelKeyUpgradeState]
+				switch pipeline.Name {
+				case defaultPipelineName:
+					// the original Pipeline is drained and waiting to be recycled
+					assert.Equal(t, string(common.LabelValueUpgradeRecyclable), upgradeState)
+					assert.True(t, reflect.DeepEqual(pipelineWithDesiredPhase(pipelineSpec, numaflowv1.PipelinePhasePaused), pipeline.Spec),
+						"result pipeline spec", fmt.Sprint(pipeline.Spec))
+				default:
+					assert.Equal(t, string(common.LabelValueUpgradePromoted), upgradeState, "pipeline %s", pipeline.Name)
+					assert.True(t, reflect.DeepEqual(pipelineSpecWithTopologyChange, pipeline.Spec), "result pipeline spec", fmt.Sprint(pipeline.Spec))
+					assert.Equal(t, pipeline.Name, rollout.Status.ProgressiveStatus.UpgradingChildName)
+					promoted++
+				}
+			}
+			assert.Equal(t, 1, promoted)
+		})
+
+		if !injector.fired {
+			break
+		}
+		failedOnUpgradeStart = failedOnUpgradeStart || injector.firedOnUpgradeStart
+	}
+	// one of the cases should have failed to write the Status recording that the upgrade started, and resumed from there
+	assert.True(t, failedOnUpgradeStart)
+}
+
+// the promote annotation only releases an upgrading child awaiting manual promotion, so it shouldn't skip the Analysis, while the
+// abort annotation should roll the upgrade back and be removed once it's done
+func Test_processResourceWithProgressive_PromoteAndAbort(t *testing.T) {
+	restConfig, numaflowClientSet, numaplaneClient, _, err := commontest.PrepareK8SEnvironment()
+	assert.Nil(t, err)
+	assert.Nil(t, kubernetes.SetDynamicClient(restConfig))
+
+	config.GetConfigManagerInstance().UpdateUSDEConfig(config.USDEConfig{
+		DefaultUpgradeStrategy:    config.ProgressiveStrategyID,
+		PipelineSpecExcludedPaths: []string{"watermark", "lifecycle"},
+	})
+	ctx := context.Background()
+
+	// other tests may call this, but it fails if called more than once
+	if customMetrics == nil {
+		customMetrics = metrics.RegisterCustomMetrics()
+	}
+
+	healthyStatus := numaflowv1.PipelineStatus{
+		Phase: numaflowv1.PipelinePhaseRunning,
+		Status: numaflowv1.Status{
+			Conditions: []metav1.Condition{
+				{
+					Type:   string(numaflowv1.PipelineConditionDaemonServiceHealthy),
+					Status: metav1.ConditionTrue,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                    string
+		annotation              string
+		expectedState           apiv1.ProgressiveState
+		expectedNewUpgradeState common.UpgradeState
+	}{
+		{
+			name:                    "promote",
+			annotation:              common.AnnotationKeyPromote,
+			expectedState:           apiv1.ProgressiveStateAssessing,
+			expectedNewUpgradeState: common.LabelValueUpgradeInProgress,
+		},
+		{
+			name:                    "abort",
+			annotation:              common.AnnotationKeyAbort,
+			expectedState:           apiv1.ProgressiveStateDone,
+			expectedNewUpgradeState: common.LabelValueUpgradeRecyclable,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// first delete Pipelines and PipelineRollout in case they already exist, in Kubernetes
+			_ = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+
+			// the Analysis always fails, since its query can't be rendered
+			rollout := createPipelineRollout(pipelineSpecWithTopologyChange, map[string]string{tc.annotation: "true"}, map[string]string{})
+			rollout.Spec.Strategy = &apiv1.PipelineTypeRolloutStrategy{
+				Progressive: apiv1.ProgressiveStrategy{
+					Analysis: &apiv1.Analysis{
+						Prometheus: apiv1.PrometheusProvider{Address: "http://localhost:1"},
+						Metrics:    []apiv1.AnalysisMetric{{Name: "errors", Query: "{{.Missing}}", Operator: apiv1.AnalysisOperatorEqual, Threshold: "0"}},
+					},
+				},
+			}
+			_ = numaplaneClient.Delete(ctx, rollout)
+			rollout.Status.Phase = apiv1.PhaseDeployed
+			rollout.Status.NameCount = new(int32)
+			*rollout.Status.NameCount++
+			rollout.Status.Init(rollout.Generation)
+			rolloutStatus := rollout.Status
+			assert.NoError(t, numaplaneClient.Create(ctx, rollout))
+			rollout.Status = rolloutStatus
+			assert.NoError(t, numaplaneClient.Status().Update(ctx, rollout))
+
+			// the original promoted Pipeline
+			existingPipeline := createPipeline(numaflowv1.PipelinePhaseRunning, numaflowv1.Status{}, false, map[string]string{
+				common.LabelKeyUpgradeState:  string(common.LabelValueUpgradePromoted),
+				common.LabelKeyParentRollout: defaultPipelineRolloutName,
+			})
+			existingPipeline.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(rollout.GetObjectMeta(), apiv1.PipelineRolloutGroupVersionKind)}
+			existingPipeline.Status = healthyStatus
+			createPipelineInK8S(ctx, t, numaflowClientSet, existingPipeline)
+
+			r := NewPipelineRolloutReconciler(numaplaneClient, scheme.Scheme, customMetrics, record.NewFakeRecorder(64))
+			for i := 0; i < 10; i++ {
+				rollout = &apiv1.PipelineRollout{}
+				assert.NoError(t, numaplaneClient.Get(ctx, client.ObjectKey{Namespace: defaultNamespace, Name: defaultPipelineRolloutName}, rollout))
+				if rollout.Status.ProgressiveStatus.State == apiv1.ProgressiveStateDone && rollout.Status.UpgradeInProgress == apiv1.UpgradeStrategyNoOp {
+					break
+				}
+
+				rollout.Status.Init(rollout.Generation)
+				_, _, err = r.reconcile(ctx, rollout, time.Now())
+				assert.NoError(t, err)
+				assert.NoError(t, r.updatePipelineRolloutStatus(ctx, rollout))
+
+				// the Numaflow controller would eventually make any new Pipeline healthy
+				pipelineList, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).List(ctx, metav1.ListOptions{})
+				assert.NoError(t, err)
+				for _, pipeline := range pipelineList.Items {
+					if pipeline.Name != defaultPipelineName && pipeline.Status.Phase == "" {
+						pipeline.Status = healthyStatus
+						_, err = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).UpdateStatus(ctx, &pipeline, metav1.UpdateOptions{})
+						assert.NoError(t, err)
+					}
+				}
+			}
+
+			////// check results:
+			assert.Equal(t, tc.expectedState, rollout.Status.ProgressiveStatus.State)
+			if tc.expectedState == apiv1.ProgressiveStateDone {
+				assert.NotContains(t, rollout.Annotations, tc.annotation)
+				condition := rollout.Status.GetCondition(apiv1.ConditionProgressiveUpgradeSucceeded)
+				if assert.NotNil(t, condition) {
+					assert.Equal(t, metav1.ConditionFalse, condition.Status)
+				}
+				assert.NotEmpty(t, rollout.Status.ProgressiveStatus.FailedSpecHash)
+			} else {
+				// the upgrading child failed the Analysis, so it isn't promoted and the promoted Pipeline is untouched
+				assert.Contains(t, rollout.Annotations, tc.annotation)
+				if assert.NotNil(t, rollout.Status.ProgressiveStatus.Analysis) {
+					assert.Equal(t, apiv1.AnalysisPhaseFailed, rollout.Status.ProgressiveStatus.Analysis.Phase)
+				}
+				promotedPipeline, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Get(ctx, defaultPipelineName, metav1.GetOptions{})
+				assert.NoError(t, err)
+				assert.Equal(t, string(common.LabelValueUpgradePromoted), promotedPipeline.Labels[common.LabelKeyUpgradeState])
+			}
+
+			upgradingChildName := rollout.Status.ProgressiveStatus.UpgradingChildName
+			upgradingPipeline, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Get(ctx, upgradingChildName, metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, string(tc.expectedNewUpgradeState), upgradingPipeline.Labels[common.LabelKeyUpgradeState])
+		})
+	}
+}
+
+func Test_processResourceWithProgressive_ManualPromotion(t *testing.T) {
+	restConfig, numaflowClientSet, numaplaneClient, _, err := commontest.PrepareK8SEnvironment()
+	assert.Nil(t, err)
+	assert.Nil(t, kubernetes.SetDynamicClient(restConfig))
+
+	config.GetConfigManagerInstance().UpdateUSDEConfig(config.USDEConfig{
+		DefaultUpgradeStrategy:    config.ProgressiveStrategyID,
+		PipelineSpecExcludedPaths: []string{"watermark", "lifecycle"},
+	})
+	ctx := context.Background()
+
+	// other tests may call this, but it fails if called more than once
+	if customMetrics == nil {
+		customMetrics = metrics.RegisterCustomMetrics()
+	}
+
+	healthyStatus := numaflowv1.PipelineStatus{
+		Phase: numaflowv1.PipelinePhaseRunning,
+		Status: numaflowv1.Status{
+			Conditions: []metav1.Condition{
+				{
+					Type:   string(numaflowv1.PipelineConditionDaemonServiceHealthy),
+					Status: metav1.ConditionTrue,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                      string
+		annotation                string
+		expectedNewUpgradeState   common.UpgradeState
+		expectedUpgradeSuccessful bool
+	}{
+		{
+			name:                      "promote",
+			annotation:                common.AnnotationKeyPromote,
+			expectedNewUpgradeState:   common.LabelValueUpgradePromoted,
+			expectedUpgradeSuccessful: true,
+		},
+		{
+			name:                      "abort",
+			annotation:                common.AnnotationKeyAbort,
+			expectedNewUpgradeState:   common.LabelValueUpgradeRecyclable,
+			expectedUpgradeSuccessful: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// first delete Pipelines and PipelineRollout in case they already exist, in Kubernetes
+			_ = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+
+			rollout := createPipelineRollout(pipelineSpecWithTopologyChange, map[string]string{}, map[string]string{})
+			rollout.Spec.Strategy = &apiv1.PipelineTypeRolloutStrategy{
+				Progressive: apiv1.ProgressiveStrategy{ManualPromotion: true},
+			}
+			_ = numaplaneClient.Delete(ctx, rollout)
+			rollout.Status.Phase = apiv1.PhaseDeployed
+			rollout.Status.NameCount = new(int32)
+			*rollout.Status.NameCount++
+			rollout.Status.Init(rollout.Generation)
+			rolloutStatus := rollout.Status
+			assert.NoError(t, numaplaneClient.Create(ctx, rollout))
+			rollout.Status = rolloutStatus
+			assert.NoError(t, numaplaneClient.Status().Update(ctx, rollout))
+
+			// the original promoted Pipeline
+			existingPipeline := createPipeline(numaflowv1.PipelinePhaseRunning, numaflowv1.Status{}, false, map[string]string{
+				common.LabelKeyUpgradeState:  string(common.LabelValueUpgradePromoted),
+				common.LabelKeyParentRollout: defaultPipelineRolloutName,
+			})
+			existingPipeline.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(rollout.GetObjectMeta(), apiv1.PipelineRolloutGroupVersionKind)}
+			existingPipeline.Status = healthyStatus
+			createPipelineInK8S(ctx, t, numaflowClientSet, existingPipeline)
+
+			r := NewPipelineRolloutReconciler(numaplaneClient, scheme.Scheme, customMetrics, record.NewFakeRecorder(64))
+			// reconcile until the upgrade is done, or until it's waiting for manual promotion for the given number of times
+			reconcileUntil := func(awaitingPromotionReconciles int) {
+				awaitingPromotion := 0
+				for i := 0; i < 10; i++ {
+					rollout = &apiv1.PipelineRollout{}
+					assert.NoError(t, numaplaneClient.Get(ctx, client.ObjectKey{Namespace: defaultNamespace, Name: defaultPipelineRolloutName}, rollout))
+					if rollout.Status.ProgressiveStatus.State == apiv1.ProgressiveStateDone && rollout.Status.UpgradeInProgress == apiv1.UpgradeStrategyNoOp {
+						return
+					}
+					if rollout.Status.ProgressiveStatus.State == apiv1.ProgressiveStateAwaitingPromotion {
+						if awaitingPromotion == awaitingPromotionReconciles {
+							return
+						}
+						awaitingPromotion++
+					}
+
+					rollout.Status.Init(rollout.Generation)
+					_, _, err = r.reconcile(ctx, rollout, time.Now())
+					assert.NoError(t, err)
+					assert.NoError(t, r.updatePipelineRolloutStatus(ctx, rollout))
+
+					// the Numaflow controller would eventually make any new Pipeline healthy
+					pipelineList, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).List(ctx, metav1.ListOptions{})
+					assert.NoError(t, err)
+					for _, pipeline := range pipelineList.Items {
+						if pipeline.Name != defaultPipelineName && pipeline.Status.Phase == "" {
+							pipeline.Status = healthyStatus
+							_, err = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).UpdateStatus(ctx, &pipeline, metav1.UpdateOptions{})
+							assert.NoError(t, err)
+						}
+					}
+				}
+			}
+
+			// the healthy upgrading child stays where it is until the user decides
+			reconcileUntil(2)
+			assert.Equal(t, apiv1.ProgressiveStateAwaitingPromotion, rollout.Status.ProgressiveStatus.State)
+			assert.NotNil(t, rollout.Status.ProgressiveStatus.AwaitingPromotionSince)
+			assert.Contains(t, rollout.Status.WaitingFor, "to be promoted manually")
+			upgradingChildName := rollout.Status.ProgressiveStatus.UpgradingChildName
+			upgradingPipeline, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Get(ctx, upgradingChildName, metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, string(common.LabelValueUpgradeInProgress), upgradingPipeline.Labels[common.LabelKeyUpgradeState])
+			promotedPipeline, err := numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Get(ctx, defaultPipelineName, metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, string(common.LabelValueUpgradePromoted), promotedPipeline.Labels[common.LabelKeyUpgradeState])
+
+			rollout.Annotations = map[string]string{tc.annotation: "true"}
+			assert.NoError(t, numaplaneClient.Update(ctx, rollout))
+			reconcileUntil(0)
+
+			////// check results:
+			assert.Equal(t, apiv1.ProgressiveStateDone, rollout.Status.ProgressiveStatus.State)
+			assert.Nil(t, rollout.Status.ProgressiveStatus.AwaitingPromotionSince)
+			assert.NotContains(t, rollout.Annotations, tc.annotation)
+			condition := rollout.Status.GetCondition(apiv1.ConditionProgressiveUpgradeSucceeded)
+			if assert.NotNil(t, condition) {
+				assert.Equal(t, tc.expectedUpgradeSuccessful, condition.Status == metav1.ConditionTrue)
+			}
+			assert.Equal(t, tc.expectedUpgradeSuccessful, rollout.Status.ProgressiveStatus.FailedSpecHash == "")
+
+			upgradingPipeline, err = numaflowClientSet.NumaflowV1alpha1().Pipelines(defaultNamespace).Get(ctx, upgradingChildName, metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, string(tc.expectedNewUpgradeState), upgradingPipeline.Labels[common.LabelKeyUpgradeState])
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// Each Rollout keeps a revision history of the child definitions which were applied from its spec (e.g. spec.pipeline of a
+// PipelineRollout), along with when they were applied, the upgrade strategy used to apply them and their outcome.
+// The history is kept in a ConfigMap owned by the Rollout, with each revision stored as JSON under its revision number.
+// Setting the "numaplane.numaproj.io/rollback-to" annotation on the Rollout writes a revision's child definition back into
+// the Rollout's spec, from which it's applied like any other change.
+
+// number of revisions kept in a Rollout's revision history if not configured
+const defaultRevisionHistoryLimit = 10
+
+// revisionedRollout is a Rollout which keeps a revision history
+type revisionedRollout interface {
+	client.Object
+
+	GetStatus() *apiv1.Status
+}
+
+// get the name of the ConfigMap holding the Rollout's revision history
+func getRevisionHistoryConfigMapName(rollout revisionedRollout) (string, error) {
+	gvk, err := getRolloutGroupVersionKind(rollout)
+	if err != nil {
+		return "", err
+	}
+	return common.RevisionHistoryConfigMapName(gvk.Kind, rollout.GetName()), nil
+}
+
+func getRolloutGroupVersionKind(rollout revisionedRollout) (schema.GroupVersionKind, error) {
+	switch rollout.(type) {
+	case *apiv1.PipelineRollout:
+		return apiv1.PipelineRolloutGroupVersionKind, nil
+	case *apiv1.ISBServiceRollout:
+		return apiv1.ISBServiceRolloutGroupVersionKind, nil
+	case *apiv1.MonoVertexRollout:
+		return apiv1.MonoVertexRolloutGroupVersionKind, nil
+	case *apiv1.NumaflowControllerRollout:
+		return apiv1.NumaflowControllerRolloutGroupVersionKind, nil
+	default:
+		return schema.GroupVersionKind{}, fmt.Errorf("unexpected Rollout type %T", rollout)
+	}
+}
+
+// get the child definition of the Rollout's spec, which is what its revisions keep
+func getRolloutChildDefinition(rollout revisionedRollout) (runtime.RawExtension, error) {
+	var definition interface{}
+	switch typedRollout := rollout.(type) {
+	case *apiv1.PipelineRollout:
+		definition = typedRollout.Spec.Pipeline
+	case *apiv1.ISBServiceRollout:
+		definition = typedRollout.Spec.InterStepBufferService
+	case *apiv1.MonoVertexRollout:
+		definition = typedRollout.Spec.MonoVertex
+	case *apiv1.NumaflowControllerRollout:
+		definition = typedRollout.Spec.Controller
+	default:
+		return runtime.RawExtension{}, fmt.Errorf("unexpected Rollout type %T", rollout)
+	}
+	raw, err := json.Marshal(definition)
+	if err != nil {
+		return runtime.RawExtension{}, fmt.Errorf("failed to marshal child definition of %s: %w", rollout.GetName(), err)
+	}
+	return runtime.RawExtension{Raw: raw}, nil
+}
+
+// set the child definition of the Rollout's spec to the one kept in a revision
+func setRolloutChildDefinition(rollout revisionedRollout, definition runtime.RawExtension) error {
+	var err error
+	switch typedRollout := rollout.(type) {
+	case *apiv1.PipelineRollout:
+		pipeline := apiv1.Pipeline{}
+		if err = json.Unmarshal(definition.Raw, &pipeline); err == nil {
+			typedRollout.Spec.Pipeline = pipeline
+		}
+	case *apiv1.ISBServiceRollout:
+		isbService := apiv1.InterStepBufferService{}
+		if err = json.Unmarshal(definition.Raw, &isbService); err == nil {
+			typedRollout.Spec.InterStepBufferService = isbService
+		}
+	case *apiv1.MonoVertexRollout:
+		monoVertex := apiv1.MonoVertex{}
+		if err = json.Unmarshal(definition.Raw, &monoVertex); err == nil {
+			typedRollout.Spec.MonoVertex = monoVertex
+		}
+	case *apiv1.NumaflowControllerRollout:
+		controller := apiv1.Controller{}
+		if err = json.Unmarshal(definition.Raw, &controller); err == nil {
+			typedRollout.Spec.Controller = controller
+		}
+	default:
+		return fmt.Errorf("unexpected Rollout type %T", rollout)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal child definition %q: %w", string(definition.Raw), err)
+	}
+	return nil
+}
+
+// get the upgrade strategy which the Rollout is currently using to apply its child definition, if any
+func getRolloutUpgradeStrategy(rollout revisionedRollout) apiv1.UpgradeStrategy {
+	switch typedRollout := rollout.(type) {
+	case *apiv1.PipelineRollout:
+		return typedRollout.Status.UpgradeInProgress
+	case *apiv1.ISBServiceRollout:
+		return typedRollout.Status.UpgradeInProgress
+	case *apiv1.MonoVertexRollout:
+		return typedRollout.Status.UpgradeInProgress
+	case *apiv1.NumaflowControllerRollout:
+		if typedRollout.Status.ProgressiveStatus.UpgradingInstanceID != "" {
+			return apiv1.UpgradeStrategyProgressive
+		}
+		if condition := typedRollout.Status.GetCondition(apiv1.ConditionPausingPipelines); condition != nil && condition.Status == metav1.ConditionTrue {
+			return apiv1.UpgradeStrategyPPND
+		}
+	}
+	return apiv1.UpgradeStrategyNoOp
+}
+
+func getRevisionHistoryLimit() int {
+	globalConfig, err := config.GetConfigManagerInstance().GetConfig()
+	if err != nil || globalConfig.RevisionHistoryLimit <= 0 {
+		return defaultRevisionHistoryLimit
+	}
+	return globalConfig.RevisionHistoryLimit
+}
+
+// load the Rollout's revision history, in order of revision, along with the ConfigMap holding it (nil if it doesn't exist yet)
+func loadRevisionHistory(ctx context.Context, c client.Client, rollout revisionedRollout) ([]apiv1.RolloutRevision, *corev1.ConfigMap, error) {
+	configMapName, err := getRevisionHistoryConfigMapName(rollout)
+	if err != nil {
+		return nil, nil, err
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(ctx, k8stypes.NamespacedName{Namespace: rollout.GetNamespace(), Name: configMapName}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to get ConfigMap %s/%s: %w", rollout.GetNamespace(), configMapName, err)
+	}
+
+	revisions := make([]apiv1.RolloutRevision, 0, len(configMap.Data))
+	for key, data := range configMap.Data {
+		var revision apiv1.RolloutRevision
+		if err := json.Unmarshal([]byte(data), &revision); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal revision %s from ConfigMap %s/%s: %w", key, rollout.GetNamespace(), configMapName, err)
+		}
+		revisions = append(revisions, revision)
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+	return revisions, configMap, nil
+}
+
+// save the Rollout's revision history to its ConfigMap, creating it if it doesn't exist yet
+func saveRevisionHistory(ctx context.Context, c client.Client, rollout revisionedRollout, revisions []apiv1.RolloutRevision, configMap *corev1.ConfigMap) error {
+	data := make(map[string]string, len(revisions))
+	for _, revision := range revisions {
+		revisionData, err := json.Marshal(revision)
+		if err != nil {
+			return fmt.Errorf("failed to marshal revision %d: %w", revision.Revision, err)
+		}
+		data[strconv.FormatInt(revision.Revision, 10)] = string(revisionData)
+	}
+
+	if configMap == nil {
+		configMapName, err := getRevisionHistoryConfigMapName(rollout)
+		if err != nil {
+			return err
+		}
+		gvk, err := getRolloutGroupVersionKind(rollout)
+		if err != nil {
+			return err
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            configMapName,
+				Namespace:       rollout.GetNamespace(),
+				Labels:          map[string]string{common.LabelKeyParentRollout: rollout.GetName()},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(rollout, gvk)},
+			},
+			Data: data,
+		}
+		if err := c.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create ConfigMap %s/%s: %w", configMap.Namespace, configMap.Name, err)
+		}
+		return nil
+	}
+
+	configMap.Data = data
+	if err := c.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to update ConfigMap %s/%s: %w", configMap.Namespace, configMap.Name, err)
+	}
+	return nil
+}
+
+// return whether two child definitions are the same, regardless of how they're serialized
+func childDefinitionsEqual(a runtime.RawExtension, b runtime.RawExtension) (bool, error) {
+	var aAsMap, bAsMap map[string]interface{}
+	if err := json.Unmarshal(a.Raw, &aAsMap); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(b.Raw, &bAsMap); err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(aAsMap, bAsMap), nil
+}
+
+// update the Rollout's revision history after it's been reconciled: add a revision if its child definition changed, and
+// update the outcome of the latest revision based on the Rollout's Status
+func updateRevisionHistory(ctx context.Context, c client.Client, rollout revisionedRollout) error {
+	definition, err := getRolloutChildDefinition(rollout)
+	if err != nil {
+		return err
+	}
+	revisions, configMap, err := loadRevisionHistory(ctx, c, rollout)
+	if err != nil {
+		return err
+	}
+	originalRevisions := make([]apiv1.RolloutRevision, len(revisions))
+	copy(originalRevisions, revisions)
+
+	isNewDefinition := len(revisions) == 0
+	if !isNewDefinition {
+		sameDefinition, err := childDefinitionsEqual(revisions[len(revisions)-1].Definition, definition)
+		if err != nil {
+			return err
+		}
+		isNewDefinition = !sameDefinition
+	}
+	if isNewDefinition {
+		nextRevision := int64(1)
+		if len(revisions) > 0 {
+			latest := &revisions[len(revisions)-1]
+			nextRevision = latest.Revision + 1
+			if latest.Outcome == apiv1.RevisionOutcomePending {
+				latest.Outcome = apiv1.RevisionOutcomeSuperseded
+			}
+		}
+		revisions = append(revisions, apiv1.RolloutRevision{
+			Revision:    nextRevision,
+			Generation:  rollout.GetGeneration(),
+			AppliedTime: metav1.NewTime(time.Now()),
+			Definition:  definition,
+			Outcome:     apiv1.RevisionOutcomePending,
+		})
+	}
+
+	latest := &revisions[len(revisions)-1]
+	if latest.Outcome != apiv1.RevisionOutcomeSucceeded {
+		status := rollout.GetStatus()
+		upgradeStrategy := getRolloutUpgradeStrategy(rollout)
+		if upgradeStrategy != apiv1.UpgradeStrategyNoOp {
+			latest.Strategy = upgradeStrategy
+		}
+		progressiveCondition := status.GetCondition(apiv1.ConditionProgressiveUpgradeSucceeded)
+		healthyCondition := status.GetCondition(apiv1.ConditionChildResourceHealthy)
+		switch {
+		case status.Phase == apiv1.PhaseFailed:
+			latest.Outcome = apiv1.RevisionOutcomeFailed
+			latest.Message = status.Message
+		case progressiveCondition != nil && progressiveCondition.Status == metav1.ConditionFalse && progressiveCondition.ObservedGeneration == rollout.GetGeneration():
+			latest.Outcome = apiv1.RevisionOutcomeFailed
+			latest.Message = progressiveCondition.Message
+		case status.Phase == apiv1.PhaseDeployed && upgradeStrategy == apiv1.UpgradeStrategyNoOp &&
+			healthyCondition != nil && healthyCondition.Status == metav1.ConditionTrue && healthyCondition.ObservedGeneration == rollout.GetGeneration():
+			latest.Outcome = apiv1.RevisionOutcomeSucceeded
+			latest.Message = ""
+			if latest.Strategy == apiv1.UpgradeStrategyNoOp && latest.Revision > 1 {
+				// no upgrade was in progress while it was applied, so it was applied directly
+				latest.Strategy = apiv1.UpgradeStrategyApply
+			}
+		}
+	}
+
+	if limit := getRevisionHistoryLimit(); len(revisions) > limit {
+		revisions = revisions[len(revisions)-limit:]
+	}
+
+	if reflect.DeepEqual(originalRevisions, revisions) {
+		return nil
+	}
+	return saveRevisionHistory(ctx, c, rollout, revisions, configMap)
+}
+
+// record the Rollout's revision history after it's been reconciled
+// failing to do so doesn't fail the reconciliation, since the history is only informational until a rollback is requested
+func recordRevision(ctx context.Context, c client.Client, recorder record.EventRecorder, rollout revisionedRollout) {
+	if !rollout.GetDeletionTimestamp().IsZero() {
+		return
+	}
+	if err := updateRevisionHistory(ctx, c, rollout); err != nil {
+		logger.FromContext(ctx).Error(err, "failed to update revision history")
+		recorder.Eventf(rollout, corev1.EventTypeWarning, "RevisionHistoryFailed", "Failed to update revision history: %v", err.Error())
+	}
+}
+
+// rolloutChangedPredicate filters the events watched for a Rollout down to the ones which need reconciling: a change to its
+// generation, or to its annotations, which doesn't change the generation (e.g. the one requesting a rollback)
+func rolloutChangedPredicate[T client.Object]() predicate.TypedPredicate[T] {
+	return predicate.Or[T](predicate.TypedGenerationChangedPredicate[T]{}, predicate.TypedAnnotationChangedPredicate[T]{})
+}
+
+// if the Rollout's rollback-to annotation is set, write the child definition of that revision back into the Rollout's spec
+// and remove the annotation: the change is then applied like any other change to the spec, using the usual upgrade strategy
+// return whether the Rollout was updated, in which case the caller should requeue it rather than reconcile it
+func rollbackIfRequested(ctx context.Context, c client.Client, recorder record.EventRecorder, rollout revisionedRollout) (bool, error) {
+	numaLogger := logger.FromContext(ctx)
+	revisionToRollBackTo, found := rollout.GetAnnotations()[common.AnnotationKeyRollbackTo]
+	if !found {
+		return false, nil
+	}
+
+	var revision *apiv1.RolloutRevision
+	revisionNumber, err := strconv.ParseInt(revisionToRollBackTo, 10, 64)
+	if err == nil {
+		revisions, _, err := loadRevisionHistory(ctx, c, rollout)
+		if err != nil {
+			return false, err
+		}
+		for i := range revisions {
+			if revisions[i].Revision == revisionNumber {
+				revision = &revisions[i]
+			}
+		}
+	}
+
+	if revision == nil {
+		// nothing to roll back to: just remove the annotation so that it's not retried
+		numaLogger.Warnf("ignoring %s annotation: revision %q not found in revision history", common.AnnotationKeyRollbackTo, revisionToRollBackTo)
+		recorder.Eventf(rollout, corev1.EventTypeWarning, "RollbackFailed", "Revision %q not found in revision history", revisionToRollBackTo)
+	} else {
+		numaLogger.Infof("rolling back to revision %d", revision.Revision)
+		if err := setRolloutChildDefinition(rollout, revision.Definition); err != nil {
+			return false, err
+		}
+	}
+
+	annotations := maps.Clone(rollout.GetAnnotations())
+	delete(annotations, common.AnnotationKeyRollbackTo)
+	rollout.SetAnnotations(annotations)
+	if err := c.Update(ctx, rollout); err != nil {
+		return false, fmt.Errorf("failed to update %s for rollback: %w", rollout.GetName(), err)
+	}
+	if revision != nil {
+		recorder.Eventf(rollout, corev1.EventTypeNormal, "RolledBack", "Rolled back to revision %d", revision.Revision)
+	}
+	return true, nil
+}
@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/metrics"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	commontest "github.com/numaproj/numaplane/tests/common"
+)
+
+func Test_revisionHistory(t *testing.T) {
+	_, _, numaplaneClient, _, err := commontest.PrepareK8SEnvironment()
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+	recorder := record.NewFakeRecorder(64)
+
+	pipelineRollout := createPipelineRollout(pipelineSpec, map[string]string{}, map[string]string{})
+	_ = numaplaneClient.Delete(ctx, &apiv1.PipelineRollout{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: defaultPipelineRolloutName}})
+	configMapName, err := getRevisionHistoryConfigMapName(pipelineRollout)
+	assert.NoError(t, err)
+	assert.Equal(t, "pipelinerollout-"+defaultPipelineRolloutName+"-revisions", configMapName)
+	_ = numaplaneClient.Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: configMapName}})
+	createPipelineRolloutInK8S(ctx, t, numaplaneClient, pipelineRollout)
+	defer func() {
+		_ = numaplaneClient.Delete(ctx, pipelineRollout)
+	}()
+	originalDefinition, err := getRolloutChildDefinition(pipelineRollout)
+	assert.NoError(t, err)
+
+	withSpecChange := func(pipelineRollout *apiv1.PipelineRollout, rpu int64) {
+		spec := pipelineSpec.DeepCopy()
+		spec.Vertices[0].Source.Generator.RPU = &rpu
+		raw, _ := json.Marshal(spec)
+		pipelineRollout.Spec.Pipeline.Spec = runtime.RawExtension{Raw: raw}
+		pipelineRollout.Generation++
+	}
+	getRevisions := func() []apiv1.RolloutRevision {
+		revisions, _, err := loadRevisionHistory(ctx, numaplaneClient, pipelineRollout)
+		assert.NoError(t, err)
+		return revisions
+	}
+
+	// the first definition is deployed and healthy
+	pipelineRollout.Status.MarkDeployed(pipelineRollout.Generation)
+	pipelineRollout.Status.MarkChildResourcesHealthy(pipelineRollout.Generation)
+	assert.NoError(t, updateRevisionHistory(ctx, numaplaneClient, pipelineRollout))
+	revisions := getRevisions()
+	if assert.Len(t, revisions, 1) {
+		assert.Equal(t, int64(1), revisions[0].Revision)
+		assert.Equal(t, apiv1.RevisionOutcomeSucceeded, revisions[0].Outcome)
+		assert.Equal(t, apiv1.UpgradeStrategyNoOp, revisions[0].Strategy)
+	}
+
+	// the second one is being applied with pause-and-drain
+	withSpecChange(pipelineRollout, 10)
+	pipelineRollout.Status.MarkPending()
+	pipelineRollout.Status.SetUpgradeInProgress(apiv1.UpgradeStrategyPPND)
+	assert.NoError(t, updateRevisionHistory(ctx, numaplaneClient, pipelineRollout))
+	revisions = getRevisions()
+	if assert.Len(t, revisions, 2) {
+		assert.Equal(t, int64(2), revisions[1].Revision)
+		assert.Equal(t, pipelineRollout.Generation, revisions[1].Generation)
+		assert.Equal(t, apiv1.RevisionOutcomePending, revisions[1].Outcome)
+		assert.Equal(t, apiv1.UpgradeStrategyPPND, revisions[1].Strategy)
+	}
+
+	// before it's done, a third one replaces it and fails
+	withSpecChange(pipelineRollout, 20)
+	pipelineRollout.Status.ClearUpgradeInProgress()
+	pipelineRollout.Status.MarkFailed("bad spec")
+	assert.NoError(t, updateRevisionHistory(ctx, numaplaneClient, pipelineRollout))
+	revisions = getRevisions()
+	if assert.Len(t, revisions, 3) {
+		assert.Equal(t, apiv1.RevisionOutcomeSuperseded, revisions[1].Outcome)
+		assert.Equal(t, apiv1.RevisionOutcomeFailed, revisions[2].Outcome)
+		assert.Equal(t, "bad spec", revisions[2].Message)
+	}
+
+	// nothing changes without a new definition
+	assert.NoError(t, updateRevisionHistory(ctx, numaplaneClient, pipelineRollout))
+	assert.Len(t, getRevisions(), 3)
+
+	// roll back to the first revision
+	rollout := &apiv1.PipelineRollout{}
+	assert.NoError(t, numaplaneClient.Get(ctx, k8stypes.NamespacedName{Namespace: defaultNamespace, Name: defaultPipelineRolloutName}, rollout))
+	withSpecChange(rollout, 20)
+	rollout.Annotations = map[string]string{common.AnnotationKeyRollbackTo: "1"}
+	assert.NoError(t, numaplaneClient.Update(ctx, rollout))
+	rolledBack, err := rollbackIfRequested(ctx, numaplaneClient, recorder, rollout)
+	assert.NoError(t, err)
+	assert.True(t, rolledBack)
+
+	assert.NoError(t, numaplaneClient.Get(ctx, k8stypes.NamespacedName{Namespace: defaultNamespace, Name: defaultPipelineRolloutName}, rollout))
+	assertDefinition := func(expected runtime.RawExtension) {
+		definition, err := getRolloutChildDefinition(rollout)
+		assert.NoError(t, err)
+		sameDefinition, err := childDefinitionsEqual(expected, definition)
+		assert.NoError(t, err)
+		assert.True(t, sameDefinition)
+	}
+	assertDefinition(originalDefinition)
+	assert.NotContains(t, rollout.Annotations, common.AnnotationKeyRollbackTo)
+
+	// a revision which isn't in the history is ignored
+	rollout.Annotations = map[string]string{common.AnnotationKeyRollbackTo: "7"}
+	assert.NoError(t, numaplaneClient.Update(ctx, rollout))
+	rolledBack, err = rollbackIfRequested(ctx, numaplaneClient, recorder, rollout)
+	assert.NoError(t, err)
+	assert.True(t, rolledBack)
+	assert.NoError(t, numaplaneClient.Get(ctx, k8stypes.NamespacedName{Namespace: defaultNamespace, Name: defaultPipelineRolloutName}, rollout))
+	assert.NotContains(t, rollout.Annotations, common.AnnotationKeyRollbackTo)
+	assertDefinition(originalDefinition)
+
+	rolledBack, err = rollbackIfRequested(ctx, numaplaneClient, recorder, rollout)
+	assert.NoError(t, err)
+	assert.False(t, rolledBack)
+}
+
+func Test_reconcile_rollback(t *testing.T) {
+	restConfig, _, numaplaneClient, _, err := commontest.PrepareK8SEnvironment()
+	assert.Nil(t, err)
+	assert.Nil(t, kubernetes.SetDynamicClient(restConfig))
+	ctx := context.Background()
+
+	// other tests may call this, but it fails if called more than once
+	if customMetrics == nil {
+		customMetrics = metrics.RegisterCustomMetrics()
+	}
+	r := NewMonoVertexRolloutReconciler(numaplaneClient, scheme.Scheme, customMetrics, record.NewFakeRecorder(64))
+
+	const rolloutName = "rollback-test"
+	namespacedName := k8stypes.NamespacedName{Namespace: defaultNamespace, Name: rolloutName}
+	withReplicas := func(replicas int32) runtime.RawExtension {
+		monoVertexSpec := fakeMonoVertexSpec(t)
+		monoVertexSpec.Replicas = ptr.To(replicas)
+		raw, err := json.Marshal(monoVertexSpec)
+		assert.NoError(t, err)
+		return runtime.RawExtension{Raw: raw}
+	}
+
+	monoVertexRollout := &apiv1.MonoVertexRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: rolloutName},
+		Spec:       apiv1.MonoVertexRolloutSpec{MonoVertex: apiv1.MonoVertex{Spec: withReplicas(1)}},
+	}
+	configMapName, err := getRevisionHistoryConfigMapName(monoVertexRollout)
+	assert.NoError(t, err)
+	_ = numaplaneClient.Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: configMapName}})
+	_ = numaplaneClient.Delete(ctx, monoVertexRollout.DeepCopy())
+	assert.NoError(t, numaplaneClient.Create(ctx, monoVertexRollout))
+	defer func() {
+		_ = numaplaneClient.Delete(ctx, monoVertexRollout)
+	}()
+
+	// the first definition is recorded as having succeeded
+	originalDefinition, err := getRolloutChildDefinition(monoVertexRollout)
+	assert.NoError(t, err)
+	monoVertexRollout.Status.MarkDeployed(monoVertexRollout.Generation)
+	monoVertexRollout.Status.MarkChildResourcesHealthy(monoVertexRollout.Generation)
+	assert.NoError(t, updateRevisionHistory(ctx, numaplaneClient, monoVertexRollout))
+
+	// then the spec changes
+	assert.NoError(t, numaplaneClient.Get(ctx, namespacedName, monoVertexRollout))
+	monoVertexRollout.Spec.MonoVertex.Spec = withReplicas(3)
+	assert.NoError(t, numaplaneClient.Update(ctx, monoVertexRollout))
+
+	// requesting the rollback only changes the annotations, which still has to trigger a reconciliation
+	rollout := monoVertexRollout.DeepCopy()
+	rollout.Annotations = map[string]string{common.AnnotationKeyRollbackTo: "1"}
+	updateEvent := event.TypedUpdateEvent[*apiv1.MonoVertexRollout]{ObjectOld: monoVertexRollout, ObjectNew: rollout}
+	assert.False(t, predicate.TypedGenerationChangedPredicate[*apiv1.MonoVertexRollout]{}.Update(updateEvent))
+	assert.True(t, rolloutChangedPredicate[*apiv1.MonoVertexRollout]().Update(updateEvent))
+	assert.NoError(t, numaplaneClient.Update(ctx, rollout))
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: namespacedName})
+	assert.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	// the reconciliation wrote the first definition back into the spec and removed the annotation
+	assert.NoError(t, numaplaneClient.Get(ctx, namespacedName, rollout))
+	definition, err := getRolloutChildDefinition(rollout)
+	assert.NoError(t, err)
+	sameDefinition, err := childDefinitionsEqual(originalDefinition, definition)
+	assert.NoError(t, err)
+	assert.True(t, sameDefinition)
+	assert.NotContains(t, rollout.Annotations, common.AnnotationKeyRollbackTo)
+}
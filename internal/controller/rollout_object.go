@@ -2,6 +2,7 @@ package controller
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
 )
@@ -15,3 +16,13 @@ type RolloutObject interface {
 
 	GetStatus() *apiv1.Status
 }
+
+// ProgressiveRolloutObject describes a Rollout whose children can be upgraded with the Progressive strategy
+type ProgressiveRolloutObject interface {
+	RolloutObject
+	client.Object
+
+	GetProgressiveStrategy() apiv1.ProgressiveStrategy
+
+	GetProgressiveStatus() *apiv1.ProgressiveStatus
+}
@@ -0,0 +1,299 @@
+package usde
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// Guardrails catch changes to a Rollout which can't be applied to its child in place: each one either blocks the change
+// or requires it to use a particular upgrade strategy. They're enforced by the validating webhook when the Rollout is
+// updated, and checked again by the reconcilers before the change is applied.
+// Setting the "numaplane.numaproj.io/override-guardrails" annotation on the Rollout to "true" turns them off, in which
+// case such a change is applied like any other. A change which they'd block is also allowed if it rolls the child back to a
+// spec it was deployed with successfully before (see ExemptRecordedRevision).
+
+// GuardrailViolation is a change which a guardrail doesn't allow to be applied in place
+type GuardrailViolation struct {
+	// Path of the field which changed, relative to the child's spec
+	Path string
+	// Message explains why the change can't be applied in place
+	Message string
+	// Strategy is the upgrade strategy which the change requires, or UpgradeStrategyNoOp if the change is blocked
+	Strategy apiv1.UpgradeStrategy
+}
+
+// Blocked indicates that no upgrade strategy can apply the change
+func (v GuardrailViolation) Blocked() bool {
+	return v.Strategy == apiv1.UpgradeStrategyNoOp
+}
+
+func (v GuardrailViolation) String() string {
+	if v.Blocked() {
+		return fmt.Sprintf("%s: %s", v.Path, v.Message)
+	}
+	return fmt.Sprintf("%s: %s, so the %s strategy is required", v.Path, v.Message, v.Strategy)
+}
+
+// GuardrailsOverridden indicates that the Rollout's guardrails have been turned off with the override annotation
+func GuardrailsOverridden(rollout client.Object) bool {
+	return rollout.GetAnnotations()[common.AnnotationKeyOverrideGuardrails] == "true"
+}
+
+// GetGuardrailStrategy returns the upgrade strategy required by the violations (the most conservative, if there are several)
+// and an error describing any which are blocked
+func GetGuardrailStrategy(violations []GuardrailViolation) (apiv1.UpgradeStrategy, error) {
+	strategies := []apiv1.UpgradeStrategy{}
+	blocked := []string{}
+	for _, violation := range violations {
+		if violation.Blocked() {
+			blocked = append(blocked, violation.String())
+		} else {
+			strategies = append(strategies, violation.Strategy)
+		}
+	}
+	if len(blocked) > 0 {
+		return apiv1.UpgradeStrategyError, fmt.Errorf("change can't be applied in place: %s (set the %q annotation to \"true\" to apply it anyway)",
+			strings.Join(blocked, "; "), common.AnnotationKeyOverrideGuardrails)
+	}
+	return getMostConservativeStrategy(strategies), nil
+}
+
+// ExemptRecordedRevision drops the blocked violations of a change to the child spec of a Rollout if the new spec is the one
+// of a revision in the Rollout's revision history which was deployed successfully: the change then rolls the child back to
+// a spec it already ran with (which is what the "numaplane.numaproj.io/rollback-to" annotation does), so it's allowed
+// The revision history is only read if any of the violations are blocked.
+func ExemptRecordedRevision(ctx context.Context, c client.Client, rollout client.Object, rolloutKind string, newSpec []byte, violations []GuardrailViolation) ([]GuardrailViolation, error) {
+	unblocked := []GuardrailViolation{}
+	for _, violation := range violations {
+		if !violation.Blocked() {
+			unblocked = append(unblocked, violation)
+		}
+	}
+	if len(unblocked) == len(violations) {
+		return violations, nil
+	}
+	recorded, err := isRecordedRevisionSpec(ctx, c, rollout, rolloutKind, newSpec)
+	if err != nil || !recorded {
+		return violations, err
+	}
+	return unblocked, nil
+}
+
+// determine whether the child spec is the one of a revision in the Rollout's revision history which was deployed successfully
+func isRecordedRevisionSpec(ctx context.Context, c client.Client, rollout client.Object, rolloutKind string, spec []byte) (bool, error) {
+	configMapName := common.RevisionHistoryConfigMapName(rolloutKind, rollout.GetName())
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(ctx, k8stypes.NamespacedName{Namespace: rollout.GetNamespace(), Name: configMapName}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get ConfigMap %s/%s: %w", rollout.GetNamespace(), configMapName, err)
+	}
+
+	var specMap map[string]interface{}
+	if err := json.Unmarshal(spec, &specMap); err != nil {
+		return false, fmt.Errorf("failed to unmarshal spec: %w", err)
+	}
+	for key, data := range configMap.Data {
+		var revision apiv1.RolloutRevision
+		if err := json.Unmarshal([]byte(data), &revision); err != nil {
+			return false, fmt.Errorf("failed to unmarshal revision %s from ConfigMap %s/%s: %w", key, rollout.GetNamespace(), configMapName, err)
+		}
+		if revision.Outcome != apiv1.RevisionOutcomeSucceeded {
+			continue
+		}
+		var definition struct {
+			Spec map[string]interface{} `json:"spec"`
+		}
+		if err := json.Unmarshal(revision.Definition.Raw, &definition); err != nil {
+			return false, fmt.Errorf("failed to unmarshal the definition of revision %d: %w", revision.Revision, err)
+		}
+		if reflect.DeepEqual(definition.Spec, specMap) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CheckPipelineSpecChange checks a change to a Pipeline's spec against the guardrails
+func CheckPipelineSpecChange(existingSpec []byte, newSpec []byte) ([]GuardrailViolation, error) {
+	var existingPipelineSpec, newPipelineSpec numaflowv1.PipelineSpec
+	if err := json.Unmarshal(existingSpec, &existingPipelineSpec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal existing Pipeline spec: %w", err)
+	}
+	if err := json.Unmarshal(newSpec, &newPipelineSpec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal new Pipeline spec: %w", err)
+	}
+
+	violations := []GuardrailViolation{}
+
+	// a Pipeline's buffers live in its InterStepBufferService, so it needs to be recreated alongside the current one
+	existingISBServiceName := getISBServiceName(existingPipelineSpec)
+	newISBServiceName := getISBServiceName(newPipelineSpec)
+	if existingISBServiceName != newISBServiceName {
+		violations = append(violations, GuardrailViolation{
+			Path:     "interStepBufferServiceName",
+			Message:  fmt.Sprintf("the Pipeline's buffers can't be moved from InterStepBufferService %q to %q in place", existingISBServiceName, newISBServiceName),
+			Strategy: apiv1.UpgradeStrategyProgressive,
+		})
+	}
+	return violations, nil
+}
+
+func getISBServiceName(pipelineSpec numaflowv1.PipelineSpec) string {
+	if pipelineSpec.InterStepBufferServiceName == "" {
+		return "default"
+	}
+	return pipelineSpec.InterStepBufferServiceName
+}
+
+// CheckISBServiceSpecChange checks a change to an InterStepBufferService's spec against the guardrails
+func CheckISBServiceSpecChange(existingSpec []byte, newSpec []byte) ([]GuardrailViolation, error) {
+	var existingISBServiceSpec, newISBServiceSpec numaflowv1.InterStepBufferServiceSpec
+	if err := json.Unmarshal(existingSpec, &existingISBServiceSpec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal existing InterStepBufferService spec: %w", err)
+	}
+	if err := json.Unmarshal(newSpec, &newISBServiceSpec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal new InterStepBufferService spec: %w", err)
+	}
+
+	violations := []GuardrailViolation{}
+
+	// switching between JetStream and Redis needs a new InterStepBufferService for the Pipelines to move to
+	existingType := getISBServiceType(existingISBServiceSpec)
+	newType := getISBServiceType(newISBServiceSpec)
+	if existingType != newType {
+		violations = append(violations, GuardrailViolation{
+			Path:     newType,
+			Message:  fmt.Sprintf("the InterStepBufferService can't be changed from %s to %s in place", existingType, newType),
+			Strategy: apiv1.UpgradeStrategyProgressive,
+		})
+		return violations, nil
+	}
+	if existingISBServiceSpec.JetStream == nil || newISBServiceSpec.JetStream == nil {
+		return violations, nil
+	}
+
+	// JetStream's storage can't be read by an older version, and may not be readable by another major version
+	existingVersion := existingISBServiceSpec.JetStream.Version
+	newVersion := newISBServiceSpec.JetStream.Version
+	comparison, majorVersionChanged, comparable := compareVersions(existingVersion, newVersion)
+	if !comparable {
+		return violations, nil
+	}
+	if comparison > 0 {
+		violations = append(violations, GuardrailViolation{
+			Path:    "jetstream.version",
+			Message: fmt.Sprintf("JetStream can't be downgraded from version %s to %s", existingVersion, newVersion),
+		})
+	} else if majorVersionChanged {
+		violations = append(violations, GuardrailViolation{
+			Path:     "jetstream.version",
+			Message:  fmt.Sprintf("JetStream's storage can't be upgraded from version %s to %s in place", existingVersion, newVersion),
+			Strategy: apiv1.UpgradeStrategyProgressive,
+		})
+	}
+	return violations, nil
+}
+
+func getISBServiceType(isbServiceSpec numaflowv1.InterStepBufferServiceSpec) string {
+	if isbServiceSpec.Redis != nil {
+		return "redis"
+	}
+	return "jetstream"
+}
+
+// compare two versions of the form "[v]major[.minor[.patch]]", ignoring anything following the numbers (such as "-alpine")
+// return -1, 0 or 1 as a is older than, the same as or newer than b, whether the major version differs, and whether both
+// versions could be parsed (versions such as "latest" can't be compared)
+func compareVersions(a string, b string) (int, bool, bool) {
+	aNumbers, aOK := parseVersion(a)
+	bNumbers, bOK := parseVersion(b)
+	if !aOK || !bOK {
+		return 0, false, false
+	}
+	for i := range aNumbers {
+		if aNumbers[i] != bNumbers[i] {
+			comparison := 1
+			if aNumbers[i] < bNumbers[i] {
+				comparison = -1
+			}
+			return comparison, i == 0, true
+		}
+	}
+	return 0, false, true
+}
+
+func parseVersion(version string) ([3]int, bool) {
+	numbers := [3]int{}
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i != -1 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) > len(numbers) {
+		return numbers, false
+	}
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return numbers, false
+		}
+		numbers[i] = number
+	}
+	return numbers, true
+}
+
+// CheckNumaflowControllerInstanceIDChange checks a change to a NumaflowControllerRollout's InstanceID against the guardrails,
+// given the InstanceID in its spec before the change
+func CheckNumaflowControllerInstanceIDChange(ctx context.Context, c client.Client, controllerRollout *apiv1.NumaflowControllerRollout, existingSpecInstanceID string) ([]GuardrailViolation, error) {
+	newInstanceID := controllerRollout.Spec.Controller.InstanceID
+	if existingSpecInstanceID == newInstanceID {
+		return nil, nil
+	}
+	// (if a Progressive upgrade has moved the Pipelines to another InstanceID, that's the one they reference)
+	existingInstanceID := existingSpecInstanceID
+	if controllerRollout.Status.ProgressiveStatus.PromotedInstanceID != "" {
+		existingInstanceID = controllerRollout.Status.ProgressiveStatus.PromotedInstanceID
+	}
+
+	// the Pipelines which still reference the existing InstanceID would no longer be managed by any Numaflow Controller
+	pipelines := &unstructured.UnstructuredList{}
+	pipelines.SetGroupVersionKind(schema.GroupVersionKind{Group: common.NumaflowAPIGroup, Version: common.NumaflowAPIVersion, Kind: common.NumaflowPipelineKind + "List"})
+	listOptions := []client.ListOption{}
+	if !controllerRollout.Spec.ClusterScoped {
+		listOptions = append(listOptions, client.InNamespace(controllerRollout.Namespace))
+	}
+	if err := c.List(ctx, pipelines, listOptions...); err != nil {
+		return nil, fmt.Errorf("error listing Pipelines: %w", err)
+	}
+	referencingPipelines := []string{}
+	for _, pipeline := range pipelines.Items {
+		if pipeline.GetAnnotations()[common.AnnotationKeyNumaflowInstanceID] == existingInstanceID {
+			referencingPipelines = append(referencingPipelines, fmt.Sprintf("%s/%s", pipeline.GetNamespace(), pipeline.GetName()))
+		}
+	}
+	if len(referencingPipelines) == 0 {
+		return nil, nil
+	}
+	return []GuardrailViolation{{
+		Path: "instanceID",
+		Message: fmt.Sprintf("Pipelines %s still reference InstanceID %q, so they'd no longer be managed by a Numaflow Controller; move them to InstanceID %q first",
+			strings.Join(referencingPipelines, ", "), existingInstanceID, newInstanceID),
+	}}, nil
+}
@@ -0,0 +1,204 @@
+package usde
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_CheckPipelineSpecChange(t *testing.T) {
+	marshal := func(isbServiceName string) []byte {
+		pipelineSpec := defaultPipelineSpec.DeepCopy()
+		pipelineSpec.InterStepBufferServiceName = isbServiceName
+		raw, _ := json.Marshal(pipelineSpec)
+		return raw
+	}
+
+	violations, err := CheckPipelineSpecChange(marshal("my-isbsvc"), marshal("my-isbsvc"))
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+
+	// an unset InterStepBufferService name is "default"
+	violations, err = CheckPipelineSpecChange(marshal(""), marshal("default"))
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = CheckPipelineSpecChange(marshal("my-isbsvc"), marshal("other-isbsvc"))
+	assert.NoError(t, err)
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, "interStepBufferServiceName", violations[0].Path)
+		assert.Equal(t, apiv1.UpgradeStrategyProgressive, violations[0].Strategy)
+	}
+}
+
+func Test_CheckISBServiceSpecChange(t *testing.T) {
+	jetStream := func(version string) []byte {
+		raw, _ := json.Marshal(numaflowv1.InterStepBufferServiceSpec{JetStream: &numaflowv1.JetStreamBufferService{Version: version}})
+		return raw
+	}
+	redis, _ := json.Marshal(numaflowv1.InterStepBufferServiceSpec{Redis: &numaflowv1.RedisBufferService{Native: &numaflowv1.NativeRedis{Version: "7.0.11"}}})
+
+	tests := []struct {
+		name             string
+		existingSpec     []byte
+		newSpec          []byte
+		expectedPath     string
+		expectedStrategy apiv1.UpgradeStrategy
+		expectedBlocked  bool
+	}{
+		{name: "no change", existingSpec: jetStream("2.9.6"), newSpec: jetStream("2.9.6")},
+		{name: "minor upgrade", existingSpec: jetStream("2.9.6"), newSpec: jetStream("2.10.3")},
+		{name: "uncomparable version", existingSpec: jetStream("2.9.6"), newSpec: jetStream("latest")},
+		{name: "major upgrade", existingSpec: jetStream("2.10.3"), newSpec: jetStream("v3.0.0-alpine"), expectedPath: "jetstream.version", expectedStrategy: apiv1.UpgradeStrategyProgressive},
+		{name: "downgrade", existingSpec: jetStream("2.10.3"), newSpec: jetStream("2.9"), expectedPath: "jetstream.version", expectedBlocked: true},
+		{name: "to redis", existingSpec: jetStream("2.9.6"), newSpec: redis, expectedPath: "redis", expectedStrategy: apiv1.UpgradeStrategyProgressive},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			violations, err := CheckISBServiceSpecChange(tc.existingSpec, tc.newSpec)
+			assert.NoError(t, err)
+			if tc.expectedPath == "" {
+				assert.Empty(t, violations)
+				return
+			}
+			if assert.Len(t, violations, 1) {
+				assert.Equal(t, tc.expectedPath, violations[0].Path)
+				assert.Equal(t, tc.expectedStrategy, violations[0].Strategy)
+				assert.Equal(t, tc.expectedBlocked, violations[0].Blocked())
+			}
+		})
+	}
+}
+
+func Test_GetGuardrailStrategy(t *testing.T) {
+	strategy, err := GetGuardrailStrategy(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, apiv1.UpgradeStrategyNoOp, strategy)
+
+	strategy, err = GetGuardrailStrategy([]GuardrailViolation{
+		{Path: "a", Message: "a", Strategy: apiv1.UpgradeStrategyPPND},
+		{Path: "b", Message: "b", Strategy: apiv1.UpgradeStrategyRecreate},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, apiv1.UpgradeStrategyRecreate, strategy)
+
+	_, err = GetGuardrailStrategy([]GuardrailViolation{
+		{Path: "a", Message: "a", Strategy: apiv1.UpgradeStrategyPPND},
+		{Path: "jetstream.version", Message: "JetStream can't be downgraded"},
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "jetstream.version: JetStream can't be downgraded")
+		assert.Contains(t, err.Error(), common.AnnotationKeyOverrideGuardrails)
+	}
+}
+
+func Test_ExemptRecordedRevision(t *testing.T) {
+	ctx := context.Background()
+	jetStream := func(version string) []byte {
+		raw, _ := json.Marshal(numaflowv1.InterStepBufferServiceSpec{JetStream: &numaflowv1.JetStreamBufferService{Version: version}})
+		return raw
+	}
+	revision := func(number int64, version string, outcome apiv1.RevisionOutcome) string {
+		definition, _ := json.Marshal(apiv1.InterStepBufferService{Spec: runtime.RawExtension{Raw: jetStream(version)}})
+		data, _ := json.Marshal(apiv1.RolloutRevision{Revision: number, Definition: runtime.RawExtension{Raw: definition}, Outcome: outcome})
+		return string(data)
+	}
+
+	isbServiceRollout := &apiv1.ISBServiceRollout{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "my-isbsvc"}}
+	rolloutKind := apiv1.ISBServiceRolloutGroupVersionKind.Kind
+	fakeClient := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: common.RevisionHistoryConfigMapName(rolloutKind, isbServiceRollout.Name)},
+		Data: map[string]string{
+			"1": revision(1, "2.9.6", apiv1.RevisionOutcomeSucceeded),
+			"2": revision(2, "2.10.1", apiv1.RevisionOutcomeFailed),
+			"3": revision(3, "2.10.3", apiv1.RevisionOutcomeSucceeded),
+		},
+	}).Build()
+
+	downgrade := func(t *testing.T, version string) []GuardrailViolation {
+		violations, err := CheckISBServiceSpecChange(jetStream("2.10.3"), jetStream(version))
+		assert.NoError(t, err)
+		assert.Len(t, violations, 1)
+		violations, err = ExemptRecordedRevision(ctx, fakeClient, isbServiceRollout, rolloutKind, jetStream(version), violations)
+		assert.NoError(t, err)
+		return violations
+	}
+
+	// rolling back to a revision which was deployed successfully is allowed
+	assert.Empty(t, downgrade(t, "2.9.6"))
+	// but not to one which failed, or to one which isn't in the revision history
+	assert.Len(t, downgrade(t, "2.10.1"), 1)
+	assert.Len(t, downgrade(t, "2.9.5"), 1)
+
+	// nor is a Rollout without any revision history exempt
+	otherRollout := &apiv1.ISBServiceRollout{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "other-isbsvc"}}
+	violations := []GuardrailViolation{{Path: "jetstream.version", Message: "downgrade"}}
+	exemptViolations, err := ExemptRecordedRevision(ctx, fakeClient, otherRollout, rolloutKind, jetStream("2.9.6"), violations)
+	assert.NoError(t, err)
+	assert.Equal(t, violations, exemptViolations)
+}
+
+func Test_CheckNumaflowControllerInstanceIDChange(t *testing.T) {
+	ctx := context.Background()
+
+	makePipeline := func(namespace string, name string, instanceID string) *unstructured.Unstructured {
+		pipeline := &unstructured.Unstructured{}
+		pipeline.SetGroupVersionKind(numaflowv1.PipelineGroupVersionKind)
+		pipeline.SetNamespace(namespace)
+		pipeline.SetName(name)
+		if instanceID != "" {
+			pipeline.SetAnnotations(map[string]string{common.AnnotationKeyNumaflowInstanceID: instanceID})
+		}
+		return pipeline
+	}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, numaflowv1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		makePipeline(defaultNamespace, "pipeline-a", "a"),
+		makePipeline("other-namespace", "pipeline-b", "b"),
+	).Build()
+
+	controllerRollout := &apiv1.NumaflowControllerRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "numaflow-controller"},
+		Spec:       apiv1.NumaflowControllerRolloutSpec{Controller: apiv1.Controller{InstanceID: "c", Version: "1.4.0"}},
+	}
+
+	// unchanged
+	violations, err := CheckNumaflowControllerInstanceIDChange(ctx, fakeClient, controllerRollout, "c")
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+
+	// a Pipeline in the namespace still references the existing InstanceID
+	violations, err = CheckNumaflowControllerInstanceIDChange(ctx, fakeClient, controllerRollout, "a")
+	assert.NoError(t, err)
+	if assert.Len(t, violations, 1) {
+		assert.True(t, violations[0].Blocked())
+		assert.Contains(t, violations[0].Message, "default/pipeline-a")
+	}
+
+	// only a cluster-scoped Numaflow Controller manages Pipelines in other namespaces
+	violations, err = CheckNumaflowControllerInstanceIDChange(ctx, fakeClient, controllerRollout, "b")
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+	controllerRollout.Spec.ClusterScoped = true
+	violations, err = CheckNumaflowControllerInstanceIDChange(ctx, fakeClient, controllerRollout, "b")
+	assert.NoError(t, err)
+	assert.Len(t, violations, 1)
+
+	// after a Progressive upgrade, the Pipelines reference the promoted InstanceID
+	controllerRollout.Status.ProgressiveStatus.PromotedInstanceID = "c-1"
+	violations, err = CheckNumaflowControllerInstanceIDChange(ctx, fakeClient, controllerRollout, "b")
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+}
@@ -0,0 +1,71 @@
+package usde
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/numaproj/numaplane/internal/controller/config"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// GetMaintenanceWindows returns the maintenance windows in effect for a Rollout, in order of precedence:
+// the windows set on the Rollout (rolloutWindows, if any), the namespace-level windows, or the global default
+func GetMaintenanceWindows(namespace string, rolloutWindows []apiv1.MaintenanceWindow) []apiv1.MaintenanceWindow {
+	if len(rolloutWindows) > 0 {
+		return rolloutWindows
+	}
+
+	namespaceConfig := config.GetConfigManagerInstance().GetNamespaceConfig(namespace)
+	if namespaceConfig != nil && len(namespaceConfig.MaintenanceWindows) > 0 {
+		return namespaceConfig.MaintenanceWindows
+	}
+
+	return config.GetConfigManagerInstance().GetUSDEConfig().MaintenanceWindows
+}
+
+// InMaintenanceWindow returns whether the given time is within one of the maintenance windows and, if not, when the
+// next one opens. If there are no maintenance windows, any time is considered to be within one.
+func InMaintenanceWindow(windows []apiv1.MaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	var nextStart time.Time
+	for _, window := range windows {
+		schedule, location, err := parseMaintenanceWindow(window)
+		if err != nil {
+			return false, time.Time{}, err
+		}
+		localNow := now.In(location)
+
+		// the most recent opening of the window, if it's still open, is the first one after (now - duration)
+		start := schedule.Next(localNow.Add(-window.Duration.Duration))
+		if start.IsZero() {
+			// the schedule never occurs
+			continue
+		}
+		if !start.After(localNow) {
+			return true, time.Time{}, nil
+		}
+		if nextStart.IsZero() || start.Before(nextStart) {
+			nextStart = start
+		}
+	}
+	return len(windows) == 0, nextStart, nil
+}
+
+func parseMaintenanceWindow(window apiv1.MaintenanceWindow) (cron.Schedule, *time.Location, error) {
+	schedule, err := cron.ParseStandard(window.Schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid maintenance window schedule %q: %v", window.Schedule, err)
+	}
+	if window.Duration.Duration <= 0 {
+		return nil, nil, fmt.Errorf("invalid maintenance window duration %s: must be positive", window.Duration.Duration)
+	}
+	location := time.UTC
+	if window.TimeZone != "" {
+		location, err = time.LoadLocation(window.TimeZone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid maintenance window time zone %q: %v", window.TimeZone, err)
+		}
+	}
+	return schedule, location, nil
+}
@@ -0,0 +1,190 @@
+package usde
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/numaproj/numaplane/internal/controller/config"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// 02:00-04:00 UTC every Saturday
+var saturdayWindow = apiv1.MaintenanceWindow{
+	Schedule: "0 2 * * 6",
+	Duration: metav1.Duration{Duration: 2 * time.Hour},
+}
+
+// 22:00-23:00 New York time every day
+var newYorkWindow = apiv1.MaintenanceWindow{
+	Schedule: "0 22 * * *",
+	Duration: metav1.Duration{Duration: time.Hour},
+	TimeZone: "America/New_York",
+}
+
+func Test_GetMaintenanceWindows(t *testing.T) {
+	configManager := config.GetConfigManagerInstance()
+
+	testCases := []struct {
+		name            string
+		globalWindows   []apiv1.MaintenanceWindow
+		namespaceConfig *config.NamespaceConfig
+		rolloutWindows  []apiv1.MaintenanceWindow
+		expectedWindows []apiv1.MaintenanceWindow
+	}{
+		{
+			name:            "no windows",
+			globalWindows:   nil,
+			namespaceConfig: nil,
+			rolloutWindows:  nil,
+			expectedWindows: nil,
+		},
+		{
+			name:            "global default",
+			globalWindows:   []apiv1.MaintenanceWindow{saturdayWindow},
+			namespaceConfig: nil,
+			rolloutWindows:  nil,
+			expectedWindows: []apiv1.MaintenanceWindow{saturdayWindow},
+		},
+		{
+			name:            "namespace overrides global",
+			globalWindows:   []apiv1.MaintenanceWindow{saturdayWindow},
+			namespaceConfig: &config.NamespaceConfig{MaintenanceWindows: []apiv1.MaintenanceWindow{newYorkWindow}},
+			rolloutWindows:  nil,
+			expectedWindows: []apiv1.MaintenanceWindow{newYorkWindow},
+		},
+		{
+			name:            "namespace without windows uses global",
+			globalWindows:   []apiv1.MaintenanceWindow{saturdayWindow},
+			namespaceConfig: &config.NamespaceConfig{UpgradeStrategy: config.PPNDStrategyID},
+			rolloutWindows:  nil,
+			expectedWindows: []apiv1.MaintenanceWindow{saturdayWindow},
+		},
+		{
+			name:            "Rollout overrides namespace",
+			globalWindows:   nil,
+			namespaceConfig: &config.NamespaceConfig{MaintenanceWindows: []apiv1.MaintenanceWindow{newYorkWindow}},
+			rolloutWindows:  []apiv1.MaintenanceWindow{saturdayWindow},
+			expectedWindows: []apiv1.MaintenanceWindow{saturdayWindow},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configManager.UpdateUSDEConfig(config.USDEConfig{MaintenanceWindows: tc.globalWindows})
+			if tc.namespaceConfig != nil {
+				configManager.UpdateNamespaceConfig(defaultNamespace, *tc.namespaceConfig)
+			} else {
+				configManager.UnsetNamespaceConfig(defaultNamespace)
+			}
+
+			assert.Equal(t, tc.expectedWindows, GetMaintenanceWindows(defaultNamespace, tc.rolloutWindows))
+		})
+	}
+}
+
+func Test_InMaintenanceWindow(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name              string
+		windows           []apiv1.MaintenanceWindow
+		now               time.Time
+		expectedInWindow  bool
+		expectedNextStart time.Time
+		expectedError     bool
+	}{
+		{
+			name:             "no windows",
+			windows:          nil,
+			now:              time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC),
+			expectedInWindow: true,
+		},
+		{
+			name:             "at the start of the window",
+			windows:          []apiv1.MaintenanceWindow{saturdayWindow},
+			now:              time.Date(2024, 6, 8, 2, 0, 0, 0, time.UTC),
+			expectedInWindow: true,
+		},
+		{
+			name:             "inside the window",
+			windows:          []apiv1.MaintenanceWindow{saturdayWindow},
+			now:              time.Date(2024, 6, 8, 3, 30, 0, 0, time.UTC),
+			expectedInWindow: true,
+		},
+		{
+			name:              "after the window closed",
+			windows:           []apiv1.MaintenanceWindow{saturdayWindow},
+			now:               time.Date(2024, 6, 8, 4, 0, 0, 0, time.UTC),
+			expectedInWindow:  false,
+			expectedNextStart: time.Date(2024, 6, 15, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:              "before the window opens",
+			windows:           []apiv1.MaintenanceWindow{saturdayWindow},
+			now:               time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC),
+			expectedInWindow:  false,
+			expectedNextStart: time.Date(2024, 6, 8, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:             "inside the window of a time zone",
+			windows:          []apiv1.MaintenanceWindow{newYorkWindow},
+			now:              time.Date(2024, 6, 6, 2, 30, 0, 0, time.UTC), // 22:30 in New York
+			expectedInWindow: true,
+		},
+		{
+			name:              "outside the window of a time zone",
+			windows:           []apiv1.MaintenanceWindow{newYorkWindow},
+			now:               time.Date(2024, 6, 5, 22, 30, 0, 0, time.UTC), // 18:30 in New York
+			expectedInWindow:  false,
+			expectedNextStart: time.Date(2024, 6, 5, 22, 0, 0, 0, newYork),
+		},
+		{
+			name:              "earliest of several windows opens next",
+			windows:           []apiv1.MaintenanceWindow{saturdayWindow, newYorkWindow},
+			now:               time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC),
+			expectedInWindow:  false,
+			expectedNextStart: time.Date(2024, 6, 5, 22, 0, 0, 0, newYork),
+		},
+		{
+			name:             "inside one of several windows",
+			windows:          []apiv1.MaintenanceWindow{newYorkWindow, saturdayWindow},
+			now:              time.Date(2024, 6, 8, 2, 30, 0, 0, time.UTC),
+			expectedInWindow: true,
+		},
+		{
+			name:          "invalid schedule",
+			windows:       []apiv1.MaintenanceWindow{{Schedule: "every saturday", Duration: metav1.Duration{Duration: time.Hour}}},
+			now:           time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC),
+			expectedError: true,
+		},
+		{
+			name:          "invalid duration",
+			windows:       []apiv1.MaintenanceWindow{{Schedule: "0 2 * * 6"}},
+			now:           time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC),
+			expectedError: true,
+		},
+		{
+			name:          "invalid time zone",
+			windows:       []apiv1.MaintenanceWindow{{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Nowhere/Special"}},
+			now:           time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inWindow, nextStart, err := InMaintenanceWindow(tc.windows, tc.now)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedInWindow, inWindow)
+			assert.True(t, tc.expectedNextStart.Equal(nextStart), "expected next start %s, got %s", tc.expectedNextStart, nextStart)
+		})
+	}
+}
@@ -2,8 +2,10 @@ package usde
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/numaproj/numaplane/internal/controller/config"
 	"github.com/numaproj/numaplane/internal/util"
@@ -17,17 +19,18 @@ import (
 
 // ResourceNeedsUpdating calculates the upgrade strategy to use during the
 // resource reconciliation process based on configuration and user preference (see design doc for details).
+// userStrategy is the user's preferred strategy, as returned by GetUserStrategy().
 // It returns whether an update is needed and the strategy to use
-func ResourceNeedsUpdating(ctx context.Context, newDef *kubernetes.GenericObject, existingDef *kubernetes.GenericObject) (bool, apiv1.UpgradeStrategy, error) {
+func ResourceNeedsUpdating(ctx context.Context, newDef *kubernetes.GenericObject, existingDef *kubernetes.GenericObject, userStrategy config.USDEUserStrategy) (bool, apiv1.UpgradeStrategy, error) {
 
 	numaLogger := logger.FromContext(ctx)
 
-	metadataNeedsUpdating, metadataUpgradeStrategy, err := resourceMetadataNeedsUpdating(ctx, newDef, existingDef)
+	metadataNeedsUpdating, metadataUpgradeStrategy, err := resourceMetadataNeedsUpdating(ctx, newDef, existingDef, userStrategy)
 	if err != nil {
 		return false, apiv1.UpgradeStrategyError, err
 	}
 
-	specNeedsUpdating, specUpgradeStrategy, err := resourceSpecNeedsUpdating(ctx, newDef, existingDef)
+	specNeedsUpdating, specUpgradeStrategy, err := resourceSpecNeedsUpdating(ctx, newDef, existingDef, userStrategy)
 	if err != nil {
 		return false, apiv1.UpgradeStrategyError, err
 	}
@@ -45,25 +48,73 @@ func ResourceNeedsUpdating(ctx context.Context, newDef *kubernetes.GenericObject
 
 }
 
-func resourceSpecNeedsUpdating(ctx context.Context, newDef *kubernetes.GenericObject, existingDef *kubernetes.GenericObject) (bool, apiv1.UpgradeStrategy, error) {
+func resourceSpecNeedsUpdating(ctx context.Context, newDef *kubernetes.GenericObject, existingDef *kubernetes.GenericObject, userStrategy config.USDEUserStrategy) (bool, apiv1.UpgradeStrategy, error) {
 
 	numaLogger := logger.FromContext(ctx)
 
 	// Get USDE Config
 	usdeConfig := config.GetConfigManagerInstance().GetUSDEConfig()
 
-	// Get apply paths based on the spec type (Pipeline, ISBS)
+	// Get rules and apply paths based on the spec type (Pipeline, ISBS, MonoVertex)
 	applyPaths := []string{}
+	rules := []config.USDERule{}
 	if reflect.DeepEqual(newDef.GroupVersionKind(), numaflowv1.PipelineGroupVersionKind) {
 		applyPaths = usdeConfig.PipelineSpecExcludedPaths
+		rules = usdeConfig.PipelineSpecRules
 	} else if reflect.DeepEqual(newDef.GroupVersionKind(), numaflowv1.ISBGroupVersionKind) {
 		applyPaths = usdeConfig.ISBServiceSpecExcludedPaths
+		rules = usdeConfig.ISBServiceSpecRules
+	} else if reflect.DeepEqual(newDef.GroupVersionKind(), numaflowv1.MonoVertexGroupVersionKind) {
+		applyPaths = usdeConfig.MonoVertexSpecExcludedPaths
+		rules = usdeConfig.MonoVertexSpecRules
 	}
+	// any kind may also have apply paths configured generically
+	applyPaths = append(append([]string{}, applyPaths...), usdeConfig.SpecExcludedPaths[newDef.GroupVersionKind().GroupKind().String()]...)
 
-	numaLogger.WithValues("usdeConfig", usdeConfig, "applyPaths", applyPaths).Debug("started deriving upgrade strategy")
+	numaLogger.WithValues("usdeConfig", usdeConfig, "applyPaths", applyPaths, "rules", rules).Debug("started deriving upgrade strategy")
+
+	var newSpec, existingSpec map[string]any
+	if err := json.Unmarshal(newDef.Spec.Raw, &newSpec); err != nil {
+		return false, apiv1.UpgradeStrategyError, err
+	}
+	if err := json.Unmarshal(existingDef.Spec.Raw, &existingSpec); err != nil {
+		return false, apiv1.UpgradeStrategyError, err
+	}
+
+	// each difference found contributes the strategy it requires, and the most conservative one wins
+	upgradeStrategies := []apiv1.UpgradeStrategy{}
+
+	// Apply the rules in order: each one removes its path from the specs so that later rules don't consider it
+	for _, rule := range rules {
+		ruleStrategy, err := getRuleUpgradeStrategy(rule.Strategy)
+		if err != nil {
+			return false, apiv1.UpgradeStrategyError, err
+		}
+
+		var newSpecOnlyRulePath, existingSpecOnlyRulePath map[string]any
+		newSpecOnlyRulePath, newSpec, err = util.SplitMap(newSpec, []string{rule.Path}, []string{}, ".")
+		if err != nil {
+			return false, apiv1.UpgradeStrategyError, err
+		}
+		existingSpecOnlyRulePath, existingSpec, err = util.SplitMap(existingSpec, []string{rule.Path}, []string{}, ".")
+		if err != nil {
+			return false, apiv1.UpgradeStrategyError, err
+		}
+
+		if !reflect.DeepEqual(newSpecOnlyRulePath, existingSpecOnlyRulePath) {
+			numaLogger.WithValues(
+				"rulePath", rule.Path,
+				"ruleStrategy", ruleStrategy,
+				"newSpecOnlyRulePath", newSpecOnlyRulePath,
+				"existingSpecOnlyRulePath", existingSpecOnlyRulePath,
+			).Debug("the specs are different at a rule's path")
+
+			upgradeStrategies = append(upgradeStrategies, ruleStrategy)
+		}
+	}
 
 	// Split newDef
-	newSpecOnlyApplyPaths, newSpecWithoutApplyPaths, err := util.SplitObject(newDef.Spec.Raw, applyPaths, []string{}, ".")
+	newSpecOnlyApplyPaths, newSpecWithoutApplyPaths, err := util.SplitMap(newSpec, applyPaths, []string{}, ".")
 	if err != nil {
 		return false, apiv1.UpgradeStrategyError, err
 	}
@@ -74,7 +125,7 @@ func resourceSpecNeedsUpdating(ctx context.Context, newDef *kubernetes.GenericOb
 	).Debug("split new spec")
 
 	// Split existingDef
-	existingSpecOnlyApplyPaths, existingSpecWithoutApplyPaths, err := util.SplitObject(existingDef.Spec.Raw, applyPaths, []string{}, ".")
+	existingSpecOnlyApplyPaths, existingSpecWithoutApplyPaths, err := util.SplitMap(existingSpec, applyPaths, []string{}, ".")
 	if err != nil {
 		return false, apiv1.UpgradeStrategyError, err
 	}
@@ -86,7 +137,7 @@ func resourceSpecNeedsUpdating(ctx context.Context, newDef *kubernetes.GenericOb
 
 	// Compare specs without the apply fields and check user's strategy to return their preferred strategy
 	if !reflect.DeepEqual(newSpecWithoutApplyPaths, existingSpecWithoutApplyPaths) {
-		upgradeStrategy, err := getDataLossUpggradeStrategy(ctx, newDef.Namespace)
+		upgradeStrategy, err := getDataLossUpggradeStrategy(userStrategy)
 		if err != nil {
 			return false, apiv1.UpgradeStrategyError, err
 		}
@@ -97,7 +148,7 @@ func resourceSpecNeedsUpdating(ctx context.Context, newDef *kubernetes.GenericOb
 			"existingSpecWithoutApplyPaths", existingSpecWithoutApplyPaths,
 		).Debug("the specs without the 'apply' paths are different")
 
-		return true, upgradeStrategy, nil
+		upgradeStrategies = append(upgradeStrategies, upgradeStrategy)
 	}
 
 	// Compare specs with the apply fields
@@ -107,14 +158,34 @@ func resourceSpecNeedsUpdating(ctx context.Context, newDef *kubernetes.GenericOb
 			"existingSpecOnlyApplyPaths", existingSpecOnlyApplyPaths,
 		).Debug("the specs with only the 'apply' paths are different")
 
-		return true, apiv1.UpgradeStrategyApply, nil
+		upgradeStrategies = append(upgradeStrategies, apiv1.UpgradeStrategyApply)
 	}
 
-	numaLogger.Debug("the specs are equal, no update needed")
+	if len(upgradeStrategies) == 0 {
+		numaLogger.Debug("the specs are equal, no update needed")
 
-	// Return NoOp if no differences were found between the new and existing specs
-	return false, apiv1.UpgradeStrategyNoOp, nil
+		// Return NoOp if no differences were found between the new and existing specs
+		return false, apiv1.UpgradeStrategyNoOp, nil
+	}
+
+	return true, getMostConservativeStrategy(upgradeStrategies), nil
+
+}
 
+// return the upgrade strategy required by a USDE rule
+func getRuleUpgradeStrategy(ruleStrategy config.USDERuleStrategy) (apiv1.UpgradeStrategy, error) {
+	switch ruleStrategy {
+	case config.ApplyRuleStrategy:
+		return apiv1.UpgradeStrategyApply, nil
+	case config.PPNDRuleStrategy:
+		return apiv1.UpgradeStrategyPPND, nil
+	case config.ProgressiveRuleStrategy:
+		return apiv1.UpgradeStrategyProgressive, nil
+	case config.RecreateRuleStrategy:
+		return apiv1.UpgradeStrategyRecreate, nil
+	default:
+		return apiv1.UpgradeStrategyError, fmt.Errorf("invalid USDE rule strategy: %v", ruleStrategy)
+	}
 }
 
 func getMostConservativeStrategy(strategies []apiv1.UpgradeStrategy) apiv1.UpgradeStrategy {
@@ -127,19 +198,22 @@ func getMostConservativeStrategy(strategies []apiv1.UpgradeStrategy) apiv1.Upgra
 	return strategy
 }
 
+// each strategy is ranked by how conservative it is, so that the most conservative strategy required by any change wins:
+// a change which needs a Progressive upgrade can't be applied in place, even with a pause and drain
 var (
 	strategyRating map[apiv1.UpgradeStrategy]int = map[apiv1.UpgradeStrategy]int{
 		apiv1.UpgradeStrategyNoOp:        0,
 		apiv1.UpgradeStrategyApply:       1,
 		apiv1.UpgradeStrategyPPND:        2,
-		apiv1.UpgradeStrategyProgressive: 2,
+		apiv1.UpgradeStrategyProgressive: 3,
+		apiv1.UpgradeStrategyRecreate:    4,
 	}
 )
 
-func resourceMetadataNeedsUpdating(ctx context.Context, newDef *kubernetes.GenericObject, existingDef *kubernetes.GenericObject) (bool, apiv1.UpgradeStrategy, error) {
+func resourceMetadataNeedsUpdating(ctx context.Context, newDef *kubernetes.GenericObject, existingDef *kubernetes.GenericObject, userStrategy config.USDEUserStrategy) (bool, apiv1.UpgradeStrategy, error) {
 	numaLogger := logger.FromContext(ctx)
 
-	upgradeStrategy, err := getDataLossUpggradeStrategy(ctx, newDef.Namespace)
+	upgradeStrategy, err := getDataLossUpggradeStrategy(userStrategy)
 	if err != nil {
 		return false, apiv1.UpgradeStrategyError, err
 	}
@@ -151,11 +225,12 @@ func resourceMetadataNeedsUpdating(ctx context.Context, newDef *kubernetes.Gener
 		"existing labels", existingDef.Labels,
 	).Debug("metadata comparison")
 
-	// First look for Label or Annotation changes that require PPND or Progressive strategy
-	// TODO: make this configurable to look for particular Labels and Annotations rather than this specific one
-	instanceIDNew := newDef.Annotations[common.AnnotationKeyNumaflowInstanceID]
-	instanceIDExisting := existingDef.Annotations[common.AnnotationKeyNumaflowInstanceID]
-	if instanceIDNew != instanceIDExisting {
+	// First look for Label or Annotation changes that require PPND or Progressive strategy: a change of Numaflow Controller
+	// instance always does, in addition to any configured keys
+	usdeConfig := config.GetConfigManagerInstance().GetUSDEConfig()
+	dataLossAnnotationKeys := append([]string{common.AnnotationKeyNumaflowInstanceID}, usdeConfig.DataLossAnnotationKeys...)
+	if metadataKeysChanged(newDef.Labels, existingDef.Labels, usdeConfig.DataLossLabelKeys) ||
+		metadataKeysChanged(newDef.Annotations, existingDef.Annotations, dataLossAnnotationKeys) {
 		return true, upgradeStrategy, nil
 	}
 
@@ -166,6 +241,37 @@ func resourceMetadataNeedsUpdating(ctx context.Context, newDef *kubernetes.Gener
 	return false, apiv1.UpgradeStrategyNoOp, nil
 }
 
+// metadataKeysChanged returns true if any Label or Annotation matching one of the given keys was added, removed, or modified.
+// A key ending with "*" matches any key with that prefix.
+func metadataKeysChanged(newMap map[string]string, existingMap map[string]string, keys []string) bool {
+	for _, m := range []map[string]string{newMap, existingMap} {
+		for key := range m {
+			if !metadataKeyMatches(key, keys) {
+				continue
+			}
+			newValue, newFound := newMap[key]
+			existingValue, existingFound := existingMap[key]
+			if newFound != existingFound || newValue != existingValue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func metadataKeyMatches(key string, keys []string) bool {
+	for _, k := range keys {
+		if prefix, isPrefix := strings.CutSuffix(k, "*"); isPrefix {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == k {
+			return true
+		}
+	}
+	return false
+}
+
 func checkMapsEqual(map1 map[string]string, map2 map[string]string) bool {
 	tempMap1 := map1
 	if tempMap1 == nil {
@@ -179,12 +285,7 @@ func checkMapsEqual(map1 map[string]string, map2 map[string]string) bool {
 }
 
 // return the upgrade strategy that represents what the user prefers to do when there's a concern for data loss
-func getDataLossUpggradeStrategy(ctx context.Context, namespace string) (apiv1.UpgradeStrategy, error) {
-	userUpgradeStrategy, err := GetUserStrategy(ctx, namespace)
-	if err != nil {
-		return apiv1.UpgradeStrategyError, err
-	}
-
+func getDataLossUpggradeStrategy(userUpgradeStrategy config.USDEUserStrategy) (apiv1.UpgradeStrategy, error) {
 	switch userUpgradeStrategy {
 	case config.PPNDStrategyID:
 		return apiv1.UpgradeStrategyPPND, nil
@@ -197,9 +298,18 @@ func getDataLossUpggradeStrategy(ctx context.Context, namespace string) (apiv1.U
 	}
 }
 
-func GetUserStrategy(ctx context.Context, namespace string) (config.USDEUserStrategy, error) {
+// GetUserStrategy returns the strategy the user prefers for updates which risk data loss, in order of precedence:
+// the strategy set on the Rollout (rolloutStrategy, if any), the namespace-level strategy, or the global default
+func GetUserStrategy(ctx context.Context, namespace string, rolloutStrategy apiv1.UserUpgradeStrategy) (config.USDEUserStrategy, error) {
 	numaLogger := logger.FromContext(ctx)
 
+	if rolloutStrategy != "" {
+		if config.USDEUserStrategy(rolloutStrategy).IsValid() {
+			return config.USDEUserStrategy(rolloutStrategy), nil
+		}
+		numaLogger.WithValues("upgrade strategy", rolloutStrategy).Warn("invalid Upgrade strategy for Rollout")
+	}
+
 	namespaceConfig := config.GetConfigManagerInstance().GetNamespaceConfig(namespace)
 
 	var userUpgradeStrategy config.USDEUserStrategy = config.GetConfigManagerInstance().GetUSDEConfig().DefaultUpgradeStrategy
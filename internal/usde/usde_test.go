@@ -131,6 +131,44 @@ func makeISBServiceDefinition(isbServiceSpec numaflowv1.InterStepBufferServiceSp
 
 }
 
+var defaultMonoVertexSpec = numaflowv1.MonoVertexSpec{
+	Source: &numaflowv1.Source{
+		Generator: &numaflowv1.GeneratorSource{
+			RPU:      &pipelineSpecSourceRPU,
+			Duration: &pipelineSpecSourceDuration,
+		},
+	},
+	Sink: &numaflowv1.Sink{
+		AbstractSink: numaflowv1.AbstractSink{
+			Log: &numaflowv1.Log{},
+		},
+	},
+}
+
+func makeMonoVertexDefinition(monoVertexSpec numaflowv1.MonoVertexSpec) kubernetes.GenericObject {
+	monoVertexSpecRaw, _ := json.Marshal(monoVertexSpec)
+
+	mvrs := apiv1.MonoVertexRolloutSpec{
+		MonoVertex: apiv1.MonoVertex{
+			Spec: runtime.RawExtension{
+				Raw: monoVertexSpecRaw,
+			},
+		},
+	}
+
+	return kubernetes.GenericObject{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "MonoVertex",
+			APIVersion: "numaflow.numaproj.io/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-monovertex",
+			Namespace: defaultNamespace,
+		},
+		Spec: mvrs.MonoVertex.Spec,
+	}
+}
+
 func Test_ResourceNeedsUpdating(t *testing.T) {
 	ctx := context.Background()
 
@@ -424,6 +462,302 @@ func Test_ResourceNeedsUpdating(t *testing.T) {
 			expectedNeedsUpdating: true,
 			expectedStrategy:      apiv1.UpgradeStrategyProgressive,
 		},
+		{
+			name: "test Annotation change not configured to require data loss strategy resulting in Direct Apply",
+			newDefinition: func() kubernetes.GenericObject {
+				pipelineDef := pipelineDefn
+				pipelineDef.Annotations = map[string]string{"example.com/owner": "a"}
+				return pipelineDef
+			}(),
+			existingDefinition: func() kubernetes.GenericObject {
+				pipelineDef := pipelineDefn
+				pipelineDef.Annotations = map[string]string{"example.com/owner": "b"}
+				return pipelineDef
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy: config.PPNDStrategyID,
+				DataLossAnnotationKeys: []string{"something"},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyApply,
+		},
+		{
+			name: "test Numaflow Controller instance change requires PPND in addition to configured keys",
+			newDefinition: func() kubernetes.GenericObject {
+				pipelineDef := pipelineDefn
+				pipelineDef.Annotations = map[string]string{common.AnnotationKeyNumaflowInstanceID: "0"}
+				return pipelineDef
+			}(),
+			existingDefinition: func() kubernetes.GenericObject {
+				pipelineDef := pipelineDefn
+				pipelineDef.Annotations = map[string]string{common.AnnotationKeyNumaflowInstanceID: "1"}
+				return pipelineDef
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy: config.PPNDStrategyID,
+				DataLossAnnotationKeys: []string{"something"},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyPPND,
+		},
+		{
+			name: "test Label added matching configured key requires PPND",
+			newDefinition: func() kubernetes.GenericObject {
+				pipelineDef := pipelineDefn
+				pipelineDef.Labels = map[string]string{"team": "a"}
+				return pipelineDef
+			}(),
+			existingDefinition: pipelineDefn,
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy: config.PPNDStrategyID,
+				DataLossLabelKeys:      []string{"team"},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyPPND,
+		},
+		{
+			name:          "test Annotation removed matching configured key prefix requires Progressive",
+			newDefinition: pipelineDefn,
+			existingDefinition: func() kubernetes.GenericObject {
+				pipelineDef := pipelineDefn
+				pipelineDef.Annotations = map[string]string{"example.com/partition-count": "3"}
+				return pipelineDef
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy: config.ProgressiveStrategyID,
+				DataLossAnnotationKeys: []string{"example.com/*"},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyProgressive,
+		},
+		{
+			name: "test Label key prefix is not applied to Annotations",
+			newDefinition: func() kubernetes.GenericObject {
+				pipelineDef := pipelineDefn
+				pipelineDef.Annotations = map[string]string{"example.com/partition-count": "3"}
+				return pipelineDef
+			}(),
+			existingDefinition: func() kubernetes.GenericObject {
+				pipelineDef := pipelineDefn
+				pipelineDef.Annotations = map[string]string{"example.com/partition-count": "4"}
+				return pipelineDef
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy: config.PPNDStrategyID,
+				DataLossLabelKeys:      []string{"example.com/*"},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyApply,
+		},
+		{
+			name: "test unchanged Label matching configured key requires no update",
+			newDefinition: func() kubernetes.GenericObject {
+				pipelineDef := pipelineDefn
+				pipelineDef.Labels = map[string]string{"team": "a"}
+				return pipelineDef
+			}(),
+			existingDefinition: func() kubernetes.GenericObject {
+				pipelineDef := pipelineDefn
+				pipelineDef.Labels = map[string]string{"team": "a"}
+				return pipelineDef
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy: config.PPNDStrategyID,
+				DataLossLabelKeys:      []string{"*"},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: false,
+			expectedStrategy:      apiv1.UpgradeStrategyNoOp,
+		},
+		{
+			name:          "test MonoVertex scale change resulting in Direct Apply",
+			newDefinition: makeMonoVertexDefinition(defaultMonoVertexSpec),
+			existingDefinition: func() kubernetes.GenericObject {
+				newMonoVertexSpec := defaultMonoVertexSpec.DeepCopy()
+				maxReplicas := int32(5)
+				newMonoVertexSpec.Scale.Max = &maxReplicas
+				return makeMonoVertexDefinition(*newMonoVertexSpec)
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy:      config.PPNDStrategyID,
+				MonoVertexSpecExcludedPaths: []string{"scale"},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyApply,
+		},
+		{
+			name:          "test MonoVertex source change resulting in Progressive",
+			newDefinition: makeMonoVertexDefinition(defaultMonoVertexSpec),
+			existingDefinition: func() kubernetes.GenericObject {
+				newMonoVertexSpec := defaultMonoVertexSpec.DeepCopy()
+				newMonoVertexSpec.Source.Generator.RPU = nil
+				return makeMonoVertexDefinition(*newMonoVertexSpec)
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy:      config.ProgressiveStrategyID,
+				MonoVertexSpecExcludedPaths: []string{"scale"},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyProgressive,
+		},
+		{
+			name:          "test Pipeline excluded paths don't apply to MonoVertex",
+			newDefinition: makeMonoVertexDefinition(defaultMonoVertexSpec),
+			existingDefinition: func() kubernetes.GenericObject {
+				newMonoVertexSpec := defaultMonoVertexSpec.DeepCopy()
+				maxReplicas := int32(5)
+				newMonoVertexSpec.Scale.Max = &maxReplicas
+				return makeMonoVertexDefinition(*newMonoVertexSpec)
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy:    config.PPNDStrategyID,
+				PipelineSpecExcludedPaths: []string{"scale"},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyPPND,
+		},
+		{
+			name:          "test MonoVertex scale change with generic excluded paths resulting in Direct Apply",
+			newDefinition: makeMonoVertexDefinition(defaultMonoVertexSpec),
+			existingDefinition: func() kubernetes.GenericObject {
+				newMonoVertexSpec := defaultMonoVertexSpec.DeepCopy()
+				maxReplicas := int32(5)
+				newMonoVertexSpec.Scale.Max = &maxReplicas
+				return makeMonoVertexDefinition(*newMonoVertexSpec)
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy: config.PPNDStrategyID,
+				SpecExcludedPaths: map[string][]string{
+					"MonoVertex.numaflow.numaproj.io": {"scale"},
+				},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyApply,
+		},
+		{
+			name:          "test generic excluded paths combined with Pipeline excluded paths",
+			newDefinition: pipelineDefn,
+			existingDefinition: func() kubernetes.GenericObject {
+				newPipelineSpec := defaultPipelineSpec.DeepCopy()
+				newPipelineSpec.InterStepBufferServiceName = "changed-isbsvc"
+				newPipelineSpec.Watermark.Disabled = true
+				return makePipelineDefinition(*newPipelineSpec)
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy:    config.PPNDStrategyID,
+				PipelineSpecExcludedPaths: []string{"watermark"},
+				SpecExcludedPaths: map[string][]string{
+					"Pipeline.numaflow.numaproj.io": {"interStepBufferServiceName"},
+				},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyApply,
+		},
+		{
+			name:          "rule with wildcard: vertex scale change is applied directly",
+			newDefinition: pipelineDefn,
+			existingDefinition: func() kubernetes.GenericObject {
+				newPipelineSpec := defaultPipelineSpec.DeepCopy()
+				minReplicas := int32(2)
+				newPipelineSpec.Vertices[1].Scale.Min = &minReplicas
+				return makePipelineDefinition(*newPipelineSpec)
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy: config.PPNDStrategyID,
+				PipelineSpecRules: []config.USDERule{
+					{Path: "vertices.*.scale", Strategy: config.ApplyRuleStrategy},
+				},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyApply,
+		},
+		{
+			name:          "rule requiring Progressive overrides user's data loss strategy",
+			newDefinition: pipelineDefn,
+			existingDefinition: func() kubernetes.GenericObject {
+				newPipelineSpec := defaultPipelineSpec.DeepCopy()
+				newPipelineSpec.Vertices[1].UDF.Builtin.Name = "filter"
+				return makePipelineDefinition(*newPipelineSpec)
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy: config.PPNDStrategyID,
+				PipelineSpecRules: []config.USDERule{
+					{Path: "vertices.*.udf", Strategy: config.ProgressiveRuleStrategy},
+				},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyProgressive,
+		},
+		{
+			name:          "earlier rule takes precedence over later rule for the same path",
+			newDefinition: pipelineDefn,
+			existingDefinition: func() kubernetes.GenericObject {
+				newPipelineSpec := defaultPipelineSpec.DeepCopy()
+				newPipelineSpec.Vertices[1].UDF.Builtin.Name = "filter"
+				return makePipelineDefinition(*newPipelineSpec)
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy: config.PPNDStrategyID,
+				PipelineSpecRules: []config.USDERule{
+					{Path: "vertices.1.udf.builtin", Strategy: config.ApplyRuleStrategy},
+					{Path: "vertices", Strategy: config.RecreateRuleStrategy},
+				},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyApply,
+		},
+		{
+			name:          "most conservative of multiple matching rules",
+			newDefinition: pipelineDefn,
+			existingDefinition: func() kubernetes.GenericObject {
+				newPipelineSpec := defaultPipelineSpec.DeepCopy()
+				newPipelineSpec.Vertices[1].UDF.Builtin.Name = "filter"
+				newPipelineSpec.Edges[0].To = "other"
+				return makePipelineDefinition(*newPipelineSpec)
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy: config.ProgressiveStrategyID,
+				PipelineSpecRules: []config.USDERule{
+					{Path: "vertices.*.udf", Strategy: config.ApplyRuleStrategy},
+					{Path: "edges", Strategy: config.RecreateRuleStrategy},
+				},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyRecreate,
+		},
+		{
+			name:          "rule for MonoVertex: scale change is applied directly",
+			newDefinition: makeMonoVertexDefinition(defaultMonoVertexSpec),
+			existingDefinition: func() kubernetes.GenericObject {
+				newMonoVertexSpec := defaultMonoVertexSpec.DeepCopy()
+				maxReplicas := int32(5)
+				newMonoVertexSpec.Scale.Max = &maxReplicas
+				return makeMonoVertexDefinition(*newMonoVertexSpec)
+			}(),
+			usdeConfig: config.USDEConfig{
+				DefaultUpgradeStrategy: config.ProgressiveStrategyID,
+				MonoVertexSpecRules: []config.USDERule{
+					{Path: "scale", Strategy: config.ApplyRuleStrategy},
+				},
+			},
+			namespaceConfig:       nil,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyApply,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -435,7 +769,10 @@ func Test_ResourceNeedsUpdating(t *testing.T) {
 				configManager.UnsetNamespaceConfig(defaultNamespace)
 			}
 
-			needsUpdating, strategy, err := ResourceNeedsUpdating(ctx, &tc.newDefinition, &tc.existingDefinition)
+			userStrategy, err := GetUserStrategy(ctx, defaultNamespace, "")
+			assert.NoError(t, err)
+
+			needsUpdating, strategy, err := ResourceNeedsUpdating(ctx, &tc.newDefinition, &tc.existingDefinition, userStrategy)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.expectedNeedsUpdating, needsUpdating)
 			assert.Equal(t, tc.expectedStrategy, strategy)
@@ -443,6 +780,71 @@ func Test_ResourceNeedsUpdating(t *testing.T) {
 	}
 }
 
+func Test_GetUserStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	configManager := config.GetConfigManagerInstance()
+
+	testCases := []struct {
+		name             string
+		globalStrategy   config.USDEUserStrategy
+		namespaceConfig  *config.NamespaceConfig
+		rolloutStrategy  apiv1.UserUpgradeStrategy
+		expectedStrategy config.USDEUserStrategy
+	}{
+		{
+			name:             "global default",
+			globalStrategy:   config.PPNDStrategyID,
+			namespaceConfig:  nil,
+			rolloutStrategy:  "",
+			expectedStrategy: config.PPNDStrategyID,
+		},
+		{
+			name:             "namespace overrides global",
+			globalStrategy:   config.PPNDStrategyID,
+			namespaceConfig:  &config.NamespaceConfig{UpgradeStrategy: config.ProgressiveStrategyID},
+			rolloutStrategy:  "",
+			expectedStrategy: config.ProgressiveStrategyID,
+		},
+		{
+			name:             "Rollout overrides namespace",
+			globalStrategy:   config.ProgressiveStrategyID,
+			namespaceConfig:  &config.NamespaceConfig{UpgradeStrategy: config.ProgressiveStrategyID},
+			rolloutStrategy:  apiv1.UserUpgradeStrategyPPND,
+			expectedStrategy: config.PPNDStrategyID,
+		},
+		{
+			name:             "Rollout overrides global",
+			globalStrategy:   config.PPNDStrategyID,
+			namespaceConfig:  nil,
+			rolloutStrategy:  apiv1.UserUpgradeStrategyProgressive,
+			expectedStrategy: config.ProgressiveStrategyID,
+		},
+		{
+			name:             "invalid Rollout strategy is ignored",
+			globalStrategy:   config.PPNDStrategyID,
+			namespaceConfig:  &config.NamespaceConfig{UpgradeStrategy: config.ProgressiveStrategyID},
+			rolloutStrategy:  "invalid",
+			expectedStrategy: config.ProgressiveStrategyID,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configManager.UpdateUSDEConfig(config.USDEConfig{DefaultUpgradeStrategy: tc.globalStrategy})
+			if tc.namespaceConfig != nil {
+				configManager.UpdateNamespaceConfig(defaultNamespace, *tc.namespaceConfig)
+			} else {
+				configManager.UnsetNamespaceConfig(defaultNamespace)
+			}
+
+			strategy, err := GetUserStrategy(ctx, defaultNamespace, tc.rolloutStrategy)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStrategy, strategy)
+		})
+	}
+}
+
 func TestGetMostConservativeStrategy(t *testing.T) {
 	tests := []struct {
 		name                   string
@@ -464,12 +866,20 @@ func TestGetMostConservativeStrategy(t *testing.T) {
 			expectedStrategyRating: 0,
 		},
 		{
-			name: "Same Rating",
+			name: "Progressive over PPND",
 			strategies: []apiv1.UpgradeStrategy{
+				apiv1.UpgradeStrategyProgressive,
 				apiv1.UpgradeStrategyPPND,
+			},
+			expectedStrategyRating: 3,
+		},
+		{
+			name: "Recreate over Progressive",
+			strategies: []apiv1.UpgradeStrategy{
+				apiv1.UpgradeStrategyRecreate,
 				apiv1.UpgradeStrategyProgressive,
 			},
-			expectedStrategyRating: 2,
+			expectedStrategyRating: 4,
 		},
 	}
 
@@ -4,7 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
+	"maps"
+	"time"
 
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
@@ -12,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	sigsyaml "sigs.k8s.io/yaml"
 
 	"github.com/numaproj/numaplane/internal/common"
 	"github.com/numaproj/numaplane/internal/controller/config"
@@ -26,12 +28,12 @@ func StartConfigMapWatcher(ctx context.Context, config *rest.Config) error {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
-	numaplaneNamespace, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	numaplaneNamespace, err := GetNumaplaneNamespace()
 	if err != nil {
-		return fmt.Errorf("failed to read namespace: %w", err)
+		return err
 	}
 
-	go watchConfigMaps(ctx, client, string(numaplaneNamespace))
+	go watchConfigMaps(ctx, client, numaplaneNamespace)
 
 	return nil
 }
@@ -145,6 +147,72 @@ func handleUSDEConfigMapEvent(configMap *corev1.ConfigMap, event watch.Event) er
 			return fmt.Errorf("error unmarshalling USDE ISBServiceSpecExcludedPaths: %v", err)
 		}
 
+		err = yaml.Unmarshal([]byte(configMap.Data["monoVertexSpecExcludedPaths"]), &usdeConfig.MonoVertexSpecExcludedPaths)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling USDE MonoVertexSpecExcludedPaths: %v", err)
+		}
+
+		err = yaml.Unmarshal([]byte(configMap.Data["specExcludedPaths"]), &usdeConfig.SpecExcludedPaths)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling USDE SpecExcludedPaths: %v", err)
+		}
+
+		err = yaml.Unmarshal([]byte(configMap.Data["pipelineSpecRules"]), &usdeConfig.PipelineSpecRules)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling USDE PipelineSpecRules: %v", err)
+		}
+
+		err = yaml.Unmarshal([]byte(configMap.Data["isbServiceSpecRules"]), &usdeConfig.ISBServiceSpecRules)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling USDE ISBServiceSpecRules: %v", err)
+		}
+
+		err = yaml.Unmarshal([]byte(configMap.Data["monoVertexSpecRules"]), &usdeConfig.MonoVertexSpecRules)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling USDE MonoVertexSpecRules: %v", err)
+		}
+
+		err = yaml.Unmarshal([]byte(configMap.Data["dataLossLabelKeys"]), &usdeConfig.DataLossLabelKeys)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling USDE DataLossLabelKeys: %v", err)
+		}
+
+		err = yaml.Unmarshal([]byte(configMap.Data["dataLossAnnotationKeys"]), &usdeConfig.DataLossAnnotationKeys)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling USDE DataLossAnnotationKeys: %v", err)
+		}
+
+		// unmarshal as JSON so that durations are parsed
+		err = sigsyaml.Unmarshal([]byte(configMap.Data["maintenanceWindows"]), &usdeConfig.MaintenanceWindows)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling USDE MaintenanceWindows: %v", err)
+		}
+
+		if pauseTimeout := configMap.Data["pauseTimeout"]; pauseTimeout != "" {
+			usdeConfig.PauseTimeout, err = time.ParseDuration(pauseTimeout)
+			if err != nil {
+				return fmt.Errorf("error parsing USDE PauseTimeout: %v", err)
+			}
+		}
+
+		err = yaml.Unmarshal([]byte(configMap.Data["pauseTimeoutPolicy"]), &usdeConfig.PauseTimeoutPolicy)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling USDE PauseTimeoutPolicy: %v", err)
+		}
+		if !usdeConfig.PauseTimeoutPolicy.IsValid() {
+			return fmt.Errorf("invalid USDE PauseTimeoutPolicy %q", usdeConfig.PauseTimeoutPolicy)
+		}
+
+		err = yaml.Unmarshal([]byte(configMap.Data["pauseByPriority"]), &usdeConfig.PauseByPriority)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling USDE PauseByPriority: %v", err)
+		}
+
+		err = yaml.Unmarshal([]byte(configMap.Data["pauseBatchSize"]), &usdeConfig.PauseBatchSize)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling USDE PauseBatchSize: %v", err)
+		}
+
 		config.GetConfigManagerInstance().UpdateUSDEConfig(usdeConfig)
 	} else if event.Type == watch.Deleted {
 		config.GetConfigManagerInstance().UnsetUSDEConfig()
@@ -159,12 +227,21 @@ func handleNamespaceConfigMapEvent(configMap *corev1.ConfigMap, event watch.Even
 			return fmt.Errorf("no ConfigMap or data field available for Namespace-level ConfigMap")
 		}
 
+		// the maintenance windows are a YAML list rather than a simple string value, so they're unmarshalled separately
+		data := maps.Clone(configMap.Data)
+		delete(data, "maintenanceWindows")
+
 		namespaceConfig := config.NamespaceConfig{}
-		err := util.StructToStruct(configMap.Data, &namespaceConfig)
+		err := util.StructToStruct(data, &namespaceConfig)
 		if err != nil {
 			return fmt.Errorf("error converting Namespace-level ConfigMap: %v", err)
 		}
 
+		err = sigsyaml.Unmarshal([]byte(configMap.Data["maintenanceWindows"]), &namespaceConfig.MaintenanceWindows)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling Namespace-level MaintenanceWindows: %v", err)
+		}
+
 		config.GetConfigManagerInstance().UpdateNamespaceConfig(configMap.Namespace, namespaceConfig)
 	} else if event.Type == watch.Deleted {
 		config.GetConfigManagerInstance().UnsetNamespaceConfig(configMap.Namespace)
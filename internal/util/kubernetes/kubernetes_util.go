@@ -1,6 +1,8 @@
 package kubernetes
 
 import (
+	"fmt"
+	"os"
 	"regexp"
 	"strings"
 
@@ -10,6 +12,9 @@ import (
 // this file is designed to hold utility functions for Kubernetes that are not specific to
 // any type of resource
 
+// file containing the namespace of the service account which Numaplane runs as
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
 // validManifestExtensions contains the supported extension for raw file.
 var validManifestExtensions = map[string]struct{}{"yaml": {}, "yml": {}, "json": {}}
 
@@ -30,3 +35,12 @@ func IsValidKubernetesManifestFile(fileName string) bool {
 	}
 	return false
 }
+
+// GetNumaplaneNamespace returns the namespace in which Numaplane is running
+func GetNumaplaneNamespace() (string, error) {
+	namespace, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read namespace: %w", err)
+	}
+	return string(namespace), nil
+}
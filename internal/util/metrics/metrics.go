@@ -67,6 +67,10 @@ type CustomMetrics struct {
 	ISBServicePausedSeconds *prometheus.GaugeVec
 	// NumaflowControllerPausedSeconds counts the total time a Numaflow controller requested resources be paused.
 	NumaflowControllerPausedSeconds *prometheus.GaugeVec
+	// PauseTimeouts counts the number of times Pipelines didn't all pause within the pause timeout, by the policy applied.
+	PauseTimeouts *prometheus.CounterVec
+	// ProgressDeadlineExceeded counts the number of times Rollouts exceeded their progress deadline.
+	ProgressDeadlineExceeded *prometheus.CounterVec
 }
 
 const (
@@ -82,6 +86,7 @@ const (
 	LabelNumaflowController = "numaflowcontroller"
 	LabelMonoVertex         = "monovertex"
 	LabelPauseType          = "pause_type"
+	LabelPolicy             = "policy"
 )
 
 var (
@@ -237,6 +242,20 @@ var (
 		ConstLabels: defaultLabels,
 	}, []string{LabelName})
 
+	// pauseTimeouts Check the total number of times Pipelines didn't all pause within the pause timeout
+	pauseTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:        "numaplane_pause_timeouts_total",
+		Help:        "The total number of times Pipelines didn't all pause in time for an ISBService or Numaflow controller update",
+		ConstLabels: defaultLabels,
+	}, []string{LabelType, LabelNamespace, LabelName, LabelPolicy})
+
+	// progressDeadlineExceeded Check the total number of times Rollouts exceeded their progress deadline
+	progressDeadlineExceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:        "numaplane_progress_deadline_exceeded_total",
+		Help:        "The total number of times Rollouts didn't finish progressing within their progress deadline",
+		ConstLabels: defaultLabels,
+	}, []string{LabelType, LabelNamespace, LabelName})
+
 	// reconciliationDuration is the histogram for the duration of pipeline, isb service and numaflow controller reconciliation.
 	reconciliationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Name:        "numaplane_reconciliation_duration_seconds",
@@ -280,7 +299,7 @@ func RegisterCustomMetrics() *CustomMetrics {
 		monoVerticesRolloutHealth, monoVertexRolloutsRunning, monoVertexROSyncs, monoVertexROSyncErrors,
 		numaflowControllersRolloutHealth, numaflowControllerRORunning, numaflowControllerROSyncs, numaflowControllerROSyncErrors, reconciliationDuration, kubeRequestCounter,
 		numaflowControllerKubectlExecutionCounter, kubeResourceCacheMonitored, kubeResourceCache, clusterCacheError,
-		pipelinePausedSeconds, isbServicePausedSeconds, numaflowControllerPausedSeconds)
+		pipelinePausedSeconds, isbServicePausedSeconds, numaflowControllerPausedSeconds, pauseTimeouts, progressDeadlineExceeded)
 
 	return &CustomMetrics{
 		PipelinesRolloutHealth:                    pipelinesRolloutHealth,
@@ -312,6 +331,8 @@ func RegisterCustomMetrics() *CustomMetrics {
 		PipelinePausedSeconds:                     pipelinePausedSeconds,
 		ISBServicePausedSeconds:                   isbServicePausedSeconds,
 		NumaflowControllerPausedSeconds:           numaflowControllerPausedSeconds,
+		PauseTimeouts:                             pauseTimeouts,
+		ProgressDeadlineExceeded:                  progressDeadlineExceeded,
 	}
 }
 
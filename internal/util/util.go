@@ -1,9 +1,12 @@
 package util
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -22,6 +25,21 @@ func StructToStruct(src any, dst any) error {
 	return nil
 }
 
+// JSONHash returns a hash of a JSON document which doesn't depend on its formatting or on the order of its keys
+func JSONHash(jsonBytes []byte) (string, error) {
+	var obj any
+	if err := json.Unmarshal(jsonBytes, &obj); err != nil {
+		return "", err
+	}
+	// map keys are marshaled in sorted order
+	canonicalBytes, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonicalBytes)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // SplitObject returns 2 maps from a given object as bytes array and a slice of paths.
 // One of the 2 output maps will include only the paths from the slice while the second returned map will include all other paths.
 func SplitObject(obj []byte, paths []string, excludedPaths []string, pathSeparator string) (map[string]any, map[string]any, error) {
@@ -33,8 +51,13 @@ func SplitObject(obj []byte, paths []string, excludedPaths []string, pathSeparat
 	return SplitMap(objAsMap, paths, excludedPaths, pathSeparator)
 }
 
+// PathWildcard is a path token which matches every key of a map or every element of an array
+const PathWildcard = "*"
+
 // SplitMap returns 2 maps from a given map and a slice of paths.
 // One of the 2 output maps will include only the paths from the slice while the second returned map will include all other paths.
+// A path token may be PathWildcard, or, following an array, the index of a single element; otherwise, a path continues into
+// every element of an array it passes through.
 // NOTE: any path in "paths" which is not found in m will have an associated key in "onlyPaths", which is "{}"
 // If the caller is calling this function on 2 maps for the purpose of comparing them, and if the key is not found in either one,
 // then 'key: {}' will be returned for both, and they will be deemed equal
@@ -97,6 +120,15 @@ func extractPath(src, dst map[string]any, pathTokens []string) error {
 
 	key := pathTokens[0]
 
+	if key == PathWildcard {
+		for srcKey := range src {
+			if err := extractPath(src, dst, append([]string{srcKey}, pathTokens[1:]...)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	srcVal, exists := src[key]
 	if !exists {
 		return nil
@@ -120,12 +152,41 @@ func extractPath(src, dst map[string]any, pathTokens []string) error {
 		}
 
 	case []any:
+		// the next token may select every element or a single element by index; otherwise, the path continues into every element
+		elemTokens := pathTokens[1:]
+		indices := make([]int, len(nextSrc))
+		for i := range nextSrc {
+			indices[i] = i
+		}
+		if elemTokens[0] == PathWildcard {
+			elemTokens = elemTokens[1:]
+		} else if index, err := strconv.Atoi(elemTokens[0]); err == nil {
+			if index < 0 || index >= len(nextSrc) {
+				return nil
+			}
+			indices = []int{index}
+			elemTokens = elemTokens[1:]
+		}
+
+		// the path ends with every element, which is the same as the whole array
+		if len(elemTokens) == 0 && len(indices) == len(nextSrc) {
+			dst[key] = srcVal
+			delete(src, key)
+			return nil
+		}
+
 		if _, exists := dst[key]; !exists {
 			dst[key] = make([]any, len(nextSrc))
 		}
 
-		// Loop through each slice element to extract paths inside slice of objects
-		for i := range nextSrc {
+		// Loop through each selected slice element to extract paths inside slice of objects
+		for _, i := range indices {
+			if len(elemTokens) == 0 {
+				dst[key].([]any)[i] = nextSrc[i]
+				nextSrc[i] = nil
+				continue
+			}
+
 			switch nextSrcElem := nextSrc[i].(type) {
 			case map[string]any:
 				nextDestArr := dst[key].([]any)
@@ -133,7 +194,7 @@ func extractPath(src, dst map[string]any, pathTokens []string) error {
 					nextDestArr[i] = make(map[string]any)
 				}
 
-				if err := extractPath(nextSrcElem, nextDestArr[i].(map[string]any), pathTokens[1:]); err != nil {
+				if err := extractPath(nextSrcElem, nextDestArr[i].(map[string]any), elemTokens); err != nil {
 					return err
 				}
 
@@ -265,6 +265,74 @@ func Test_SplitMap(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:              "simple map - wildcard map key",
+			inputMap:          simpleMap,
+			paths:             []string{"map.*.x"},
+			excludedPaths:     nil,
+			expectedOnlyPaths: msa{"map": msa{"field": msa{}, "field2": msa{"x": 324}}},
+			expectedWithoutPaths: msa{
+				"map": msa{
+					"field": msa{
+						"inner":  123,
+						"inner2": "inner2val",
+					},
+					"field2": msa{},
+				},
+			},
+		},
+		{
+			name:          "complex map - wildcard array elements",
+			inputMap:      complexMap,
+			paths:         []string{"projects.*.status"},
+			excludedPaths: nil,
+			expectedOnlyPaths: msa{
+				"projects": []msa{{"status": "completed"}, {}, {}, {"status": "in progress"}},
+			},
+			expectedWithoutPaths: msa{
+				"address":  complexMap["address"],
+				"age":      30,
+				"lastname": nil,
+				"name":     "John",
+				"primArr":  []any{1, 2, 3, 4, 5, 6},
+				"projects": []msa{
+					{"name": "Project2", "nothing": nil, "other": []msa{{"x": "x2"}, {"y": "y2"}}, "vals": []any{1, 2, 3}},
+					{"name": "Project3", "other": []msa{{"y": "y3"}}},
+					{"name": "Project4", "other": []msa{{"z": "z4"}}},
+					{"name": "Project1", "other": []msa{{"x": "x1"}, {"w": "w1", "y": "y1"}, {"t": "t1"}, {"z": "z1"}}, "vals": []any{4, 5, 6, 7}},
+				},
+			},
+		},
+		{
+			name:          "complex map - array index",
+			inputMap:      complexMap,
+			paths:         []string{"projects.3.other.1.y", "projects.1"},
+			excludedPaths: nil,
+			expectedOnlyPaths: msa{
+				"projects": []any{nil, msa{"name": "Project3", "other": []msa{{"y": "y3"}}}, nil, msa{"other": []any{nil, msa{"y": "y1"}, nil, nil}}},
+			},
+			expectedWithoutPaths: msa{
+				"address":  complexMap["address"],
+				"age":      30,
+				"lastname": nil,
+				"name":     "John",
+				"primArr":  []any{1, 2, 3, 4, 5, 6},
+				"projects": []any{
+					msa{"name": "Project2", "nothing": nil, "other": []msa{{"x": "x2"}, {"y": "y2"}}, "status": "completed", "vals": []any{1, 2, 3}},
+					nil,
+					msa{"name": "Project4", "other": []msa{{"z": "z4"}}},
+					msa{"name": "Project1", "other": []msa{{"x": "x1"}, {"w": "w1"}, {"t": "t1"}, {"z": "z1"}}, "status": "in progress", "vals": []any{4, 5, 6, 7}},
+				},
+			},
+		},
+		{
+			name:                 "complex map - array index out of range",
+			inputMap:             complexMap,
+			paths:                []string{"projects.4.name"},
+			excludedPaths:        nil,
+			expectedOnlyPaths:    msa{},
+			expectedWithoutPaths: complexMap,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -283,3 +351,21 @@ func Test_SplitMap(t *testing.T) {
 		})
 	}
 }
+
+func Test_JSONHash(t *testing.T) {
+	hash, err := JSONHash([]byte(`{"a": 1, "b": {"c": [1, 2], "d": "x"}}`))
+	assert.NoError(t, err)
+
+	// formatting and key order don't matter
+	sameHash, err := JSONHash([]byte(`{"b":{"d":"x","c":[1,2]},"a":1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, hash, sameHash)
+
+	// content does
+	differentHash, err := JSONHash([]byte(`{"a": 1, "b": {"c": [2, 1], "d": "x"}}`))
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, differentHash)
+
+	_, err = JSONHash([]byte(`{"a": `))
+	assert.Error(t, err)
+}
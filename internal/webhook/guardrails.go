@@ -0,0 +1,100 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/usde"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// check an update of a Rollout from oldObj to obj against the guardrails (see usde.GuardrailViolation)
+// A change which they block is rejected; a change which requires a particular upgrade strategy is allowed with a warning.
+func (v *RolloutValidator) checkGuardrails(ctx context.Context, oldObj runtime.Object, obj runtime.Object) (admission.Warnings, field.ErrorList) {
+	rollout, ok := obj.(client.Object)
+	if !ok || usde.GuardrailsOverridden(rollout) {
+		return nil, nil
+	}
+
+	var specPath *field.Path
+	var violations []usde.GuardrailViolation
+	var err error
+	switch rollout := obj.(type) {
+	case *apiv1.PipelineRollout:
+		specPath = field.NewPath("spec", "pipeline", "spec")
+		violations, err = usde.CheckPipelineSpecChange(oldObj.(*apiv1.PipelineRollout).Spec.Pipeline.Spec.Raw, rollout.Spec.Pipeline.Spec.Raw)
+		if err == nil {
+			violations, err = usde.ExemptRecordedRevision(ctx, v.client, rollout, apiv1.PipelineRolloutGroupVersionKind.Kind, rollout.Spec.Pipeline.Spec.Raw, violations)
+			if err != nil {
+				return nil, field.ErrorList{field.InternalError(specPath, err)}
+			}
+		}
+	case *apiv1.ISBServiceRollout:
+		specPath = field.NewPath("spec", "interStepBufferService", "spec")
+		violations, err = usde.CheckISBServiceSpecChange(oldObj.(*apiv1.ISBServiceRollout).Spec.InterStepBufferService.Spec.Raw, rollout.Spec.InterStepBufferService.Spec.Raw)
+		if err == nil {
+			violations, err = usde.ExemptRecordedRevision(ctx, v.client, rollout, apiv1.ISBServiceRolloutGroupVersionKind.Kind, rollout.Spec.InterStepBufferService.Spec.Raw, violations)
+			if err != nil {
+				return nil, field.ErrorList{field.InternalError(specPath, err)}
+			}
+		}
+	case *apiv1.NumaflowControllerRollout:
+		specPath = field.NewPath("spec", "controller")
+		violations, err = usde.CheckNumaflowControllerInstanceIDChange(ctx, v.client, rollout, oldObj.(*apiv1.NumaflowControllerRollout).Spec.Controller.InstanceID)
+		if err != nil {
+			return nil, field.ErrorList{field.InternalError(specPath.Child("instanceID"), err)}
+		}
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		// the existing spec may not be valid, in which case there's nothing to compare with
+		logger.FromContext(ctx).Debugf("unable to check guardrails: %v", err)
+		return nil, nil
+	}
+
+	var warnings admission.Warnings
+	var errs field.ErrorList
+	for _, violation := range violations {
+		if violation.Blocked() {
+			errs = append(errs, field.Forbidden(childPath(specPath, violation.Path),
+				fmt.Sprintf("%s (set the %q annotation to \"true\" to apply it anyway)", violation.Message, common.AnnotationKeyOverrideGuardrails)))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("%s: %s, so it will be applied with the %s strategy",
+				childPath(specPath, violation.Path), violation.Message, violation.Strategy))
+		}
+	}
+	return warnings, errs
+}
+
+// get the path of a field given its dot-separated path relative to specPath
+func childPath(specPath *field.Path, path string) *field.Path {
+	for _, name := range strings.Split(path, ".") {
+		specPath = specPath.Child(name)
+	}
+	return specPath
+}
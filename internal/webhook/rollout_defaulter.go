@@ -0,0 +1,294 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/mutate-numaplane-numaproj-io-v1alpha1-pipelinerollout,mutating=true,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=pipelinerollouts,verbs=create;update,versions=v1alpha1,name=mpipelinerollout.numaplane.numaproj.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-numaplane-numaproj-io-v1alpha1-isbservicerollout,mutating=true,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=isbservicerollouts,verbs=create;update,versions=v1alpha1,name=misbservicerollout.numaplane.numaproj.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-numaplane-numaproj-io-v1alpha1-monovertexrollout,mutating=true,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=monovertexrollouts,verbs=create;update,versions=v1alpha1,name=mmonovertexrollout.numaplane.numaproj.io,admissionReviewVersions=v1
+
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
+
+// crdSchema is the part of the OpenAPI schema of a Numaflow CRD needed to apply its defaults
+type crdSchema struct {
+	Properties map[string]crdSchema `json:"properties,omitempty"`
+	Items      *crdSchema           `json:"items,omitempty"`
+	Default    *json.RawMessage     `json:"default,omitempty"`
+}
+
+// childSchema is the schema of a Numaflow child kind's spec, along with the fields which aren't defaulted
+type childSchema struct {
+	crdName string
+	// dot-separated paths of the fields whose defaults aren't applied
+	excludedPaths []string
+}
+
+// The desired phase is what pauses a child, so defaulting it to "Running" in the Rollout would override any pause which
+// isn't set explicitly. The replicas of a MonoVertex aren't defaulted either, since Numaplane carries the replicas of the
+// running MonoVertex over to its updates.
+var (
+	pipelineSchema   = childSchema{crdName: "pipelines." + common.NumaflowAPIGroup, excludedPaths: []string{"lifecycle.desiredPhase"}}
+	monoVertexSchema = childSchema{crdName: "monovertices." + common.NumaflowAPIGroup, excludedPaths: []string{"lifecycle.desiredPhase", "replicas"}}
+	isbServiceSchema = childSchema{crdName: "interstepbufferservices." + common.NumaflowAPIGroup}
+)
+
+// RolloutDefaulter fills in the defaults of a Rollout's child spec when the Rollout is created or updated, so that the
+// desired child spec is the same as the one the API server stores for the child. Otherwise, comparing the two finds
+// differences in fields which the user never set.
+// The defaults are read from the schemas of the Numaflow CRDs installed in the cluster, so they're always the ones the API
+// server applies.
+type RolloutDefaulter struct {
+	client client.Reader
+
+	lock sync.Mutex
+	// the spec schema of each CRD, along with the resourceVersion of the CRD it was read from
+	specSchemas map[string]cachedSpecSchema
+}
+
+type cachedSpecSchema struct {
+	resourceVersion string
+	schema          crdSchema
+}
+
+// NewRolloutDefaulter returns a RolloutDefaulter which reads the Numaflow CRDs with the given client
+func NewRolloutDefaulter(c client.Reader) *RolloutDefaulter {
+	return &RolloutDefaulter{client: c, specSchemas: map[string]cachedSpecSchema{}}
+}
+
+// Default implements admission.CustomDefaulter
+// An update which doesn't change the spec (such as adding or removing a finalizer) is left alone, so that it doesn't
+// change the generation of a Rollout which was applied before it could be defaulted.
+func (d *RolloutDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	rollout, ok := obj.(client.Object)
+	if !ok {
+		return fmt.Errorf("unexpected object of type %T", obj)
+	}
+	if !rollout.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+	specChanged, err := specChangedByRequest(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if !specChanged {
+		return nil
+	}
+	logger.FromContext(ctx).Debugf("defaulting %T %s/%s", obj, rollout.GetNamespace(), rollout.GetName())
+
+	switch rollout := obj.(type) {
+	case *apiv1.PipelineRollout:
+		return d.defaultChildSpec(ctx, &rollout.Spec.Pipeline.Spec, pipelineSchema)
+	case *apiv1.ISBServiceRollout:
+		return d.defaultChildSpec(ctx, &rollout.Spec.InterStepBufferService.Spec, isbServiceSchema)
+	case *apiv1.MonoVertexRollout:
+		return d.defaultChildSpec(ctx, &rollout.Spec.MonoVertex.Spec, monoVertexSchema)
+	default:
+		return fmt.Errorf("unexpected object of type %T", obj)
+	}
+}
+
+// fill in the defaults of a child spec
+// A spec which can't be decoded is left as it is, for the validating webhook to reject, and so is one whose CRD isn't
+// installed.
+func (d *RolloutDefaulter) defaultChildSpec(ctx context.Context, spec *runtime.RawExtension, child childSchema) error {
+	var specMap map[string]interface{}
+	if err := json.Unmarshal(spec.Raw, &specMap); err != nil || specMap == nil {
+		return nil
+	}
+	specSchema, found, err := d.getSpecSchema(ctx, child.crdName)
+	if err != nil {
+		return err
+	}
+	if !found {
+		logger.FromContext(ctx).Debugf("not defaulting child spec since CRD %s isn't installed", child.crdName)
+		return nil
+	}
+
+	defaulted, err := applySchemaDefaults(specMap, specSchema, "", child.excludedPaths)
+	if err != nil {
+		return err
+	}
+	if !defaulted {
+		return nil
+	}
+	raw, err := json.Marshal(specMap)
+	if err != nil {
+		return err
+	}
+	spec.Raw = raw
+	return nil
+}
+
+// get the schema of the spec of the given CRD, reading it again only if the CRD has changed
+// return whether the CRD was found
+func (d *RolloutDefaulter) getSpecSchema(ctx context.Context, crdName string) (crdSchema, bool, error) {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+	if err := d.client.Get(ctx, k8stypes.NamespacedName{Name: crdName}, crd); err != nil {
+		if apierrors.IsNotFound(err) {
+			return crdSchema{}, false, nil
+		}
+		return crdSchema{}, false, fmt.Errorf("failed to get CRD %s: %w", crdName, err)
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if cached, found := d.specSchemas[crdName]; found && cached.resourceVersion == crd.GetResourceVersion() {
+		return cached.schema, true, nil
+	}
+	specSchema, err := parseSpecSchema(crd)
+	if err != nil {
+		return crdSchema{}, false, fmt.Errorf("failed to read the schema of CRD %s: %w", crdName, err)
+	}
+	d.specSchemas[crdName] = cachedSpecSchema{resourceVersion: crd.GetResourceVersion(), schema: specSchema}
+	return specSchema, true, nil
+}
+
+// get the schema of the spec from the CRD's version of the Numaflow API
+func parseSpecSchema(crd *unstructured.Unstructured) (crdSchema, error) {
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return crdSchema{}, err
+	}
+	for _, version := range versions {
+		versionMap, ok := version.(map[string]interface{})
+		if !ok || versionMap["name"] != common.NumaflowAPIVersion {
+			continue
+		}
+		specSchemaMap, found, err := unstructured.NestedMap(versionMap, "schema", "openAPIV3Schema", "properties", "spec")
+		if err != nil {
+			return crdSchema{}, err
+		}
+		if !found {
+			return crdSchema{}, fmt.Errorf("version %s has no spec schema", common.NumaflowAPIVersion)
+		}
+		specSchemaJSON, err := json.Marshal(specSchemaMap)
+		if err != nil {
+			return crdSchema{}, err
+		}
+		var specSchema crdSchema
+		err = json.Unmarshal(specSchemaJSON, &specSchema)
+		return specSchema, err
+	}
+	return crdSchema{}, fmt.Errorf("version %s not found", common.NumaflowAPIVersion)
+}
+
+// apply the defaults of the schema to an object, the way the API server does: a missing field with a default is set to it,
+// and then the defaults of its own fields apply, as they do for each element of a list
+// The fields at the excluded paths (relative to the root object, with "[]" for list elements) are left out, including from
+// the default values of their parents.
+// return whether any default was applied
+func applySchemaDefaults(obj map[string]interface{}, objSchema crdSchema, path string, excludedPaths []string) (bool, error) {
+	defaulted := false
+	for key, propertySchema := range objSchema.Properties {
+		propertyPath := key
+		if path != "" {
+			propertyPath = path + "." + key
+		}
+		if slices.Contains(excludedPaths, propertyPath) {
+			continue
+		}
+
+		if _, found := obj[key]; !found && propertySchema.Default != nil {
+			var defaultValue interface{}
+			if err := json.Unmarshal(*propertySchema.Default, &defaultValue); err != nil {
+				return false, fmt.Errorf("invalid default for %q: %w", propertyPath, err)
+			}
+			if defaultMap, ok := defaultValue.(map[string]interface{}); ok && len(defaultMap) > 0 {
+				removeExcludedFields(defaultMap, propertyPath, excludedPaths)
+				if len(defaultMap) == 0 {
+					// the default only sets excluded fields
+					continue
+				}
+			}
+			obj[key] = defaultValue
+			defaulted = true
+		}
+
+		var propertyDefaulted bool
+		var err error
+		switch value := obj[key].(type) {
+		case map[string]interface{}:
+			propertyDefaulted, err = applySchemaDefaults(value, propertySchema, propertyPath, excludedPaths)
+		case []interface{}:
+			if propertySchema.Items == nil {
+				continue
+			}
+			for _, element := range value {
+				if elementMap, ok := element.(map[string]interface{}); ok {
+					elementDefaulted, elementErr := applySchemaDefaults(elementMap, *propertySchema.Items, propertyPath+"[]", excludedPaths)
+					if elementErr != nil {
+						return false, elementErr
+					}
+					propertyDefaulted = propertyDefaulted || elementDefaulted
+				}
+			}
+		}
+		if err != nil {
+			return false, err
+		}
+		defaulted = defaulted || propertyDefaulted
+	}
+	return defaulted, nil
+}
+
+// remove the fields at the excluded paths from a default value for the field at the given path
+func removeExcludedFields(defaultValue map[string]interface{}, path string, excludedPaths []string) {
+	for _, excludedPath := range excludedPaths {
+		if fieldPath, isChild := strings.CutPrefix(excludedPath, path+"."); isChild {
+			unstructured.RemoveNestedField(defaultValue, strings.Split(fieldPath, ".")...)
+		}
+	}
+}
+
+// determine whether the admission request creates the Rollout or changes its spec
+func specChangedByRequest(ctx context.Context, obj runtime.Object) (bool, error) {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || req.Operation != admissionv1.Update {
+		return true, nil
+	}
+	oldObj, ok := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(runtime.Object)
+	if !ok {
+		return false, fmt.Errorf("unexpected object of type %T", obj)
+	}
+	if err := json.Unmarshal(req.OldObject.Raw, oldObj); err != nil {
+		return false, fmt.Errorf("failed to decode existing %T: %w", obj, err)
+	}
+	return !reflect.DeepEqual(getSpec(oldObj), getSpec(obj)), nil
+}
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// create a client which serves the Numaflow CRDs of the version in go.mod
+func newCRDClient(t *testing.T) client.Client {
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "github.com/numaproj/numaflow").Output()
+	require.NoError(t, err)
+	crdDirectory := filepath.Join(strings.TrimSpace(string(out)), "config", "base", "crds", "full")
+
+	clientBuilder := fake.NewClientBuilder().WithScheme(runtime.NewScheme())
+	for _, crdFile := range []string{
+		"numaflow.numaproj.io_pipelines.yaml",
+		"numaflow.numaproj.io_monovertices.yaml",
+		"numaflow.numaproj.io_interstepbufferservices.yaml",
+	} {
+		crdYAML, err := os.ReadFile(filepath.Join(crdDirectory, crdFile))
+		require.NoError(t, err)
+		crd := &unstructured.Unstructured{}
+		require.NoError(t, yaml.Unmarshal(crdYAML, &crd.Object))
+		clientBuilder = clientBuilder.WithObjects(crd)
+	}
+	return clientBuilder.Build()
+}
+
+func Test_defaultChildSpec(t *testing.T) {
+	defaulter := NewRolloutDefaulter(newCRDClient(t))
+
+	tests := []struct {
+		name         string
+		spec         string
+		child        childSchema
+		expectedSpec string
+	}{
+		{
+			name:         "pipeline with nothing set",
+			spec:         `{"vertices":[{"name":"in","source":{"generator":{"rpu":10}}},{"name":"out","sink":{"log":{}}}],"edges":[{"from":"in","to":"out"}]}`,
+			child:        pipelineSchema,
+			expectedSpec: `{"vertices":[{"name":"in","source":{"generator":{"rpu":10,"duration":"1s","msgSize":8,"jitter":"0s"}},"updateStrategy":{"type":"RollingUpdate","rollingUpdate":{"maxUnavailable":"25%"}}},{"name":"out","sink":{"log":{}},"updateStrategy":{"type":"RollingUpdate","rollingUpdate":{"maxUnavailable":"25%"}}}],"edges":[{"from":"in","to":"out"}],"lifecycle":{"deleteGracePeriodSeconds":30,"pauseGracePeriodSeconds":30},"limits":{"readBatchSize":500,"bufferMaxLength":30000,"bufferUsageLimit":80,"readTimeout":"1s"},"watermark":{"disabled":false,"maxDelay":"0s"}}`,
+		},
+		{
+			name:         "pipeline with some fields set",
+			spec:         `{"vertices":[],"lifecycle":{"desiredPhase":"Paused"},"limits":{"readBatchSize":100},"watermark":{"idleSource":{"threshold":"5s"}}}`,
+			child:        pipelineSchema,
+			expectedSpec: `{"vertices":[],"lifecycle":{"deleteGracePeriodSeconds":30,"desiredPhase":"Paused","pauseGracePeriodSeconds":30},"limits":{"readBatchSize":100,"bufferMaxLength":30000,"bufferUsageLimit":80,"readTimeout":"1s"},"watermark":{"disabled":false,"maxDelay":"0s","idleSource":{"threshold":"5s","stepInterval":"0s"}}}`,
+		},
+		{
+			name:         "monovertex",
+			spec:         `{"source":{"udsource":{"container":{"image":"source","ports":[{"containerPort":8080}]}}},"sink":{"udsink":{},"retryStrategy":{"backoff":{"steps":3}}}}`,
+			child:        monoVertexSchema,
+			expectedSpec: `{"source":{"udsource":{"container":{"image":"source","ports":[{"containerPort":8080,"protocol":"TCP"}]}}},"sink":{"udsink":{},"retryStrategy":{"onFailure":"retry","backoff":{"steps":3,"interval":"1ms"}}},"updateStrategy":{"type":"RollingUpdate","rollingUpdate":{"maxUnavailable":"25%"}}}`,
+		},
+		{
+			name:         "isbservice",
+			spec:         `{"jetstream":{"version":"latest"}}`,
+			child:        isbServiceSchema,
+			expectedSpec: `{"jetstream":{"version":"latest","replicas":3}}`,
+		},
+		{
+			name:         "nothing to default",
+			spec:         `{"jetstream":{"replicas":5}}`,
+			child:        isbServiceSchema,
+			expectedSpec: `{"jetstream":{"replicas":5}}`,
+		},
+		{
+			name:         "invalid spec is left alone",
+			spec:         `{"jetstream":`,
+			child:        isbServiceSchema,
+			expectedSpec: `{"jetstream":`,
+		},
+		{
+			name:         "CRD which isn't installed",
+			spec:         `{"jetstream":{"version":"latest"}}`,
+			child:        childSchema{crdName: "others.numaflow.numaproj.io"},
+			expectedSpec: `{"jetstream":{"version":"latest"}}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := runtime.RawExtension{Raw: []byte(tc.spec)}
+			assert.NoError(t, defaulter.defaultChildSpec(context.Background(), &spec, tc.child))
+			if json.Valid([]byte(tc.expectedSpec)) {
+				assert.JSONEq(t, tc.expectedSpec, string(spec.Raw))
+			} else {
+				assert.Equal(t, tc.expectedSpec, string(spec.Raw))
+			}
+		})
+	}
+}
+
+func Test_RolloutDefaulter_Update(t *testing.T) {
+	defaulter := NewRolloutDefaulter(newCRDClient(t))
+	isbServiceRollout := func(spec string) *apiv1.ISBServiceRollout {
+		return &apiv1.ISBServiceRollout{
+			ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "my-isbsvc"},
+			Spec: apiv1.ISBServiceRolloutSpec{
+				InterStepBufferService: apiv1.InterStepBufferService{Spec: runtime.RawExtension{Raw: []byte(spec)}},
+			},
+		}
+	}
+	updateContext := func(oldObj runtime.Object) context.Context {
+		raw, err := json.Marshal(oldObj)
+		assert.NoError(t, err)
+		return admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Update,
+			OldObject: runtime.RawExtension{Raw: raw},
+		}})
+	}
+
+	// an update which only adds a finalizer doesn't change the spec
+	existing := isbServiceRollout(`{"jetstream":{"version":"latest"}}`)
+	updated := existing.DeepCopy()
+	updated.Finalizers = []string{"numaplane.numaproj.io/numaplane-controller"}
+	assert.NoError(t, defaulter.Default(updateContext(existing), updated))
+	assert.Equal(t, `{"jetstream":{"version":"latest"}}`, string(updated.Spec.InterStepBufferService.Spec.Raw))
+
+	// an update which changes the spec is defaulted
+	updated = isbServiceRollout(`{"jetstream":{"version":"2.10.3"}}`)
+	assert.NoError(t, defaulter.Default(updateContext(existing), updated))
+	assert.JSONEq(t, `{"jetstream":{"version":"2.10.3","replicas":3}}`, string(updated.Spec.InterStepBufferService.Spec.Raw))
+}
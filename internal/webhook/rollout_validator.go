@@ -0,0 +1,264 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	"github.com/numaproj/numaflow/pkg/reconciler/validator"
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-numaplane-numaproj-io-v1alpha1-pipelinerollout,mutating=false,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=pipelinerollouts,verbs=create;update,versions=v1alpha1,name=vpipelinerollout.numaplane.numaproj.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-numaplane-numaproj-io-v1alpha1-isbservicerollout,mutating=false,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=isbservicerollouts,verbs=create;update,versions=v1alpha1,name=visbservicerollout.numaplane.numaproj.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-numaplane-numaproj-io-v1alpha1-monovertexrollout,mutating=false,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=monovertexrollouts,verbs=create;update,versions=v1alpha1,name=vmonovertexrollout.numaplane.numaproj.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-numaplane-numaproj-io-v1alpha1-numaflowcontrollerrollout,mutating=false,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=numaflowcontrollerrollouts,verbs=create;update,versions=v1alpha1,name=vnumaflowcontrollerrollout.numaplane.numaproj.io,admissionReviewVersions=v1
+
+// RolloutValidator validates Rollouts when they're created or updated, so that an invalid child spec is rejected when
+// it's applied rather than discovered when reconciling the Rollout
+// The webhooks are opt-in (see the "--enable-webhooks" flag), so nothing may rely on them: the controller enforces the same
+// rules when reconciling, and this only reports violations earlier.
+type RolloutValidator struct {
+	client client.Client
+}
+
+// NewRolloutValidator returns a RolloutValidator which looks up the resources referenced by Rollouts with the given client
+func NewRolloutValidator(c client.Client) *RolloutValidator {
+	return &RolloutValidator{client: c}
+}
+
+// SetupWebhooksWithManager registers the webhooks for all of the Rollout kinds with the Manager
+func SetupWebhooksWithManager(mgr ctrl.Manager) error {
+	rolloutValidator := NewRolloutValidator(mgr.GetClient())
+	rolloutDefaulter := NewRolloutDefaulter(mgr.GetClient())
+	for _, rollout := range []runtime.Object{&apiv1.PipelineRollout{}, &apiv1.ISBServiceRollout{}, &apiv1.MonoVertexRollout{}, &apiv1.NumaflowControllerRollout{}} {
+		webhookBuilder := ctrl.NewWebhookManagedBy(mgr).For(rollout).WithValidator(rolloutValidator)
+		// a NumaflowControllerRollout has no child spec to default
+		if _, isNumaflowControllerRollout := rollout.(*apiv1.NumaflowControllerRollout); !isNumaflowControllerRollout {
+			webhookBuilder = webhookBuilder.WithDefaulter(rolloutDefaulter)
+		}
+		if err := webhookBuilder.Complete(); err != nil {
+			return fmt.Errorf("failed to set up webhook for %T: %w", rollout, err)
+		}
+	}
+	return nil
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *RolloutValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, nil, obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *RolloutValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, oldObj, newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *RolloutValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate a Rollout which is being created, or updated from oldObj, in which case the change is also checked against the
+// guardrails
+// An update which doesn't change the spec (such as adding or removing a finalizer) is always allowed, so that a Rollout
+// which was applied before it could be validated can still be reconciled and deleted.
+func (v *RolloutValidator) validate(ctx context.Context, oldObj runtime.Object, obj runtime.Object) (admission.Warnings, error) {
+	rollout, ok := obj.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object of type %T", obj)
+	}
+	if oldObj != nil && (!rollout.GetDeletionTimestamp().IsZero() || reflect.DeepEqual(getSpec(oldObj), getSpec(obj))) {
+		return nil, nil
+	}
+	logger.FromContext(ctx).Debugf("validating %T %s/%s", obj, rollout.GetNamespace(), rollout.GetName())
+
+	var errs field.ErrorList
+	var gk schema.GroupKind
+	switch rollout := obj.(type) {
+	case *apiv1.PipelineRollout:
+		gk = apiv1.PipelineRolloutGroupVersionKind.GroupKind()
+		errs = v.validatePipelineRollout(ctx, rollout)
+	case *apiv1.ISBServiceRollout:
+		gk = apiv1.ISBServiceRolloutGroupVersionKind.GroupKind()
+		errs = validateISBServiceRollout(rollout)
+	case *apiv1.MonoVertexRollout:
+		gk = apiv1.MonoVertexRolloutGroupVersionKind.GroupKind()
+		errs = validateMonoVertexRollout(rollout)
+	case *apiv1.NumaflowControllerRollout:
+		gk = apiv1.NumaflowControllerRolloutGroupVersionKind.GroupKind()
+		errs = validateNumaflowControllerRollout(rollout)
+	default:
+		return nil, fmt.Errorf("unexpected object of type %T", obj)
+	}
+
+	var warnings admission.Warnings
+	if len(errs) == 0 && oldObj != nil {
+		warnings, errs = v.checkGuardrails(ctx, oldObj, obj)
+	}
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(gk, rollout.GetName(), errs)
+	}
+	return warnings, nil
+}
+
+// get the spec of a Rollout, for comparison
+func getSpec(obj runtime.Object) interface{} {
+	switch rollout := obj.(type) {
+	case *apiv1.PipelineRollout:
+		return rollout.Spec
+	case *apiv1.ISBServiceRollout:
+		return rollout.Spec
+	case *apiv1.MonoVertexRollout:
+		return rollout.Spec
+	case *apiv1.NumaflowControllerRollout:
+		return rollout.Spec
+	default:
+		return nil
+	}
+}
+
+func (v *RolloutValidator) validatePipelineRollout(ctx context.Context, pipelineRollout *apiv1.PipelineRollout) field.ErrorList {
+	specPath := field.NewPath("spec", "pipeline", "spec")
+
+	var pipelineSpec numaflowv1.PipelineSpec
+	if err := decodeChildSpec(pipelineRollout.Spec.Pipeline.Spec, &pipelineSpec); err != nil {
+		return field.ErrorList{invalidChildSpec(specPath, err)}
+	}
+	pipeline := &numaflowv1.Pipeline{Spec: pipelineSpec}
+	pipeline.Name = pipelineRollout.Name
+	if err := validator.ValidatePipeline(pipeline); err != nil {
+		return field.ErrorList{invalidChildSpec(specPath, err)}
+	}
+
+	// the Pipeline runs on the InterStepBufferService of the ISBServiceRollout it names, or else on an
+	// InterStepBufferService of that name
+	isbServiceName := pipelineSpec.InterStepBufferServiceName
+	if isbServiceName == "" {
+		isbServiceName = "default"
+	}
+	exists, err := v.isbServiceExists(ctx, pipelineRollout.Namespace, isbServiceName)
+	if err != nil {
+		return field.ErrorList{field.InternalError(specPath.Child("interStepBufferServiceName"), err)}
+	}
+	if !exists {
+		return field.ErrorList{field.NotFound(specPath.Child("interStepBufferServiceName"), isbServiceName)}
+	}
+	return nil
+}
+
+// determine whether there's an ISBServiceRollout or InterStepBufferService with the given name
+func (v *RolloutValidator) isbServiceExists(ctx context.Context, namespace string, name string) (bool, error) {
+	namespacedName := k8stypes.NamespacedName{Namespace: namespace, Name: name}
+	err := v.client.Get(ctx, namespacedName, &apiv1.ISBServiceRollout{})
+	if err == nil {
+		return true, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("error getting ISBServiceRollout %s: %w", namespacedName, err)
+	}
+
+	isbService := &unstructured.Unstructured{}
+	isbService.SetGroupVersionKind(schema.GroupVersionKind{Group: common.NumaflowAPIGroup, Version: common.NumaflowAPIVersion, Kind: common.NumaflowISBServiceKind})
+	err = v.client.Get(ctx, namespacedName, isbService)
+	if err == nil {
+		return true, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("error getting InterStepBufferService %s: %w", namespacedName, err)
+	}
+	return false, nil
+}
+
+func validateISBServiceRollout(isbServiceRollout *apiv1.ISBServiceRollout) field.ErrorList {
+	specPath := field.NewPath("spec", "interStepBufferService", "spec")
+
+	var isbServiceSpec numaflowv1.InterStepBufferServiceSpec
+	if err := decodeChildSpec(isbServiceRollout.Spec.InterStepBufferService.Spec, &isbServiceSpec); err != nil {
+		return field.ErrorList{invalidChildSpec(specPath, err)}
+	}
+	isbService := &numaflowv1.InterStepBufferService{Spec: isbServiceSpec}
+	isbService.Name = isbServiceRollout.Name
+	if err := validator.ValidateInterStepBufferService(isbService); err != nil {
+		return field.ErrorList{invalidChildSpec(specPath, err)}
+	}
+	return nil
+}
+
+func validateMonoVertexRollout(monoVertexRollout *apiv1.MonoVertexRollout) field.ErrorList {
+	specPath := field.NewPath("spec", "monoVertex", "spec")
+
+	var monoVertexSpec numaflowv1.MonoVertexSpec
+	if err := decodeChildSpec(monoVertexRollout.Spec.MonoVertex.Spec, &monoVertexSpec); err != nil {
+		return field.ErrorList{invalidChildSpec(specPath, err)}
+	}
+	monoVertex := &numaflowv1.MonoVertex{Spec: monoVertexSpec}
+	monoVertex.Name = monoVertexRollout.Name
+	if err := validator.ValidateMonoVertex(monoVertex); err != nil {
+		return field.ErrorList{invalidChildSpec(specPath, err)}
+	}
+	return nil
+}
+
+func validateNumaflowControllerRollout(numaflowControllerRollout *apiv1.NumaflowControllerRollout) field.ErrorList {
+	version := numaflowControllerRollout.Spec.Controller.Version
+	definitions := config.GetConfigManagerInstance().GetControllerDefinitionsMgr().GetNumaflowControllerDefinitionsConfig()
+	if len(definitions[version]) == 0 {
+		return field.ErrorList{field.NotSupported(field.NewPath("spec", "controller", "version"), version, getVersions(definitions))}
+	}
+	return nil
+}
+
+func getVersions(definitions map[string]string) []string {
+	versions := make([]string, 0, len(definitions))
+	for version := range definitions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// the child spec is left out of the error, since it may be large
+func invalidChildSpec(specPath *field.Path, err error) *field.Error {
+	return field.Invalid(specPath, field.OmitValueType{}, err.Error())
+}
+
+// decode a child spec into its Numaflow type, rejecting any field which the type doesn't have (such as a misspelled one)
+func decodeChildSpec(spec runtime.RawExtension, into interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(spec.Raw))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(into)
+}
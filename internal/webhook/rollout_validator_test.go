@@ -0,0 +1,166 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	"github.com/numaproj/numaplane/internal/controller/config"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	commontest "github.com/numaproj/numaplane/tests/common"
+)
+
+const defaultNamespace = "default"
+
+var (
+	isbServiceSpec = numaflowv1.InterStepBufferServiceSpec{
+		JetStream: &numaflowv1.JetStreamBufferService{Version: "2.9.6"},
+	}
+	pipelineSpec = numaflowv1.PipelineSpec{
+		InterStepBufferServiceName: "my-isbsvc",
+		Vertices: []numaflowv1.AbstractVertex{
+			{Name: "in", Source: &numaflowv1.Source{Generator: &numaflowv1.GeneratorSource{}}},
+			{Name: "out", Sink: &numaflowv1.Sink{AbstractSink: numaflowv1.AbstractSink{Log: &numaflowv1.Log{}}}},
+		},
+		Edges: []numaflowv1.Edge{{From: "in", To: "out"}},
+	}
+	monoVertexSpec = numaflowv1.MonoVertexSpec{
+		Source: &numaflowv1.Source{UDSource: &numaflowv1.UDSource{Container: &numaflowv1.Container{Image: "source"}}},
+		Sink:   &numaflowv1.Sink{AbstractSink: numaflowv1.AbstractSink{Log: &numaflowv1.Log{}}},
+	}
+)
+
+func rawExtension(t *testing.T, spec interface{}) runtime.RawExtension {
+	raw, err := json.Marshal(spec)
+	assert.NoError(t, err)
+	return runtime.RawExtension{Raw: raw}
+}
+
+// start the webhook server which the test environment's webhook configurations call
+func startWebhookServer(ctx context.Context, t *testing.T) client.Client {
+	restConfig, webhookOptions, numaplaneClient, err := commontest.PrepareK8SEnvironmentWithWebhooks()
+	require.NoError(t, err)
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+		Scheme: scheme.Scheme,
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    webhookOptions.LocalServingHost,
+			Port:    webhookOptions.LocalServingPort,
+			CertDir: webhookOptions.LocalServingCertDir,
+		}),
+		Metrics:        metricsserver.Options{BindAddress: "0"},
+		LeaderElection: false,
+	})
+	require.NoError(t, err)
+	require.NoError(t, SetupWebhooksWithManager(mgr))
+	go func() {
+		_ = mgr.Start(ctx)
+	}()
+
+	// wait for the webhook server to be serving
+	address := fmt.Sprintf("%s:%d", webhookOptions.LocalServingHost, webhookOptions.LocalServingPort)
+	require.Eventually(t, func() bool {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", address, &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}, 30*time.Second, 100*time.Millisecond)
+
+	return numaplaneClient
+}
+
+func Test_RolloutValidator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	numaplaneClient := startWebhookServer(ctx, t)
+
+	config.GetConfigManagerInstance().GetControllerDefinitionsMgr().UpdateNumaflowControllerDefinitionConfig(config.NumaflowControllerDefinitionConfig{
+		ControllerDefinitions: []apiv1.ControllerDefinitions{{Version: "1.4.0", FullSpec: "apiVersion: v1\nkind: ServiceAccount"}},
+	})
+
+	assertInvalid := func(err error, message string) {
+		if assert.Error(t, err) {
+			assert.True(t, apierrors.IsInvalid(err), err.Error())
+			assert.Contains(t, err.Error(), message)
+		}
+	}
+
+	// ISBServiceRollout
+	isbServiceRollout := &apiv1.ISBServiceRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "my-isbsvc"},
+		Spec: apiv1.ISBServiceRolloutSpec{
+			InterStepBufferService: apiv1.InterStepBufferService{Spec: runtime.RawExtension{Raw: []byte(`{"jetstream":{"versoin":"2.9.6"}}`)}},
+		},
+	}
+	assertInvalid(numaplaneClient.Create(ctx, isbServiceRollout), `unknown field "versoin"`)
+	isbServiceRollout.Spec.InterStepBufferService.Spec = runtime.RawExtension{Raw: []byte(`{"jetstream":{}}`)}
+	assertInvalid(numaplaneClient.Create(ctx, isbServiceRollout), `"spec.jetstream.version" is not defined`)
+	isbServiceRollout.Spec.InterStepBufferService.Spec = rawExtension(t, isbServiceSpec)
+	assert.NoError(t, numaplaneClient.Create(ctx, isbServiceRollout))
+
+	// PipelineRollout
+	pipelineRollout := &apiv1.PipelineRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "my-pipeline"},
+		Spec: apiv1.PipelineRolloutSpec{
+			Pipeline: apiv1.Pipeline{Spec: runtime.RawExtension{Raw: []byte(`{"vertices":[{"name":"in"}],"edges":[]}`)}},
+		},
+	}
+	assertInvalid(numaplaneClient.Create(ctx, pipelineRollout), "no edges defined")
+	missingISBService := pipelineSpec.DeepCopy()
+	missingISBService.InterStepBufferServiceName = "other-isbsvc"
+	pipelineRollout.Spec.Pipeline.Spec = rawExtension(t, missingISBService)
+	assertInvalid(numaplaneClient.Create(ctx, pipelineRollout), `spec.pipeline.spec.interStepBufferServiceName: Not found: "other-isbsvc"`)
+	// (the webhook reads the ISBServiceRollout from its cache, which may take a moment to see it)
+	pipelineRollout.Spec.Pipeline.Spec = rawExtension(t, pipelineSpec)
+	assert.Eventually(t, func() bool {
+		return numaplaneClient.Create(ctx, pipelineRollout) == nil
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// an update to the spec is validated too
+	pipelineRollout.Spec.Pipeline.Spec = runtime.RawExtension{Raw: []byte(`{"vertices":[],"edgse":[]}`)}
+	assertInvalid(numaplaneClient.Update(ctx, pipelineRollout), `unknown field "edgse"`)
+
+	// MonoVertexRollout
+	monoVertexRollout := &apiv1.MonoVertexRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "my-monovertex"},
+		Spec: apiv1.MonoVertexRolloutSpec{
+			MonoVertex: apiv1.MonoVertex{Spec: runtime.RawExtension{Raw: []byte(`{"sink":{"log":{}}}`)}},
+		},
+	}
+	assertInvalid(numaplaneClient.Create(ctx, monoVertexRollout), "source is not defined")
+	monoVertexRollout.Spec.MonoVertex.Spec = rawExtension(t, monoVertexSpec)
+	assert.NoError(t, numaplaneClient.Create(ctx, monoVertexRollout))
+	// (and its spec was defaulted from the schema of the MonoVertex CRD, except for the desired phase and replicas)
+	var createdMonoVertexSpec map[string]interface{}
+	assert.NoError(t, json.Unmarshal(monoVertexRollout.Spec.MonoVertex.Spec.Raw, &createdMonoVertexSpec))
+	assert.Equal(t, map[string]interface{}{"onFailure": "retry"}, createdMonoVertexSpec["sink"].(map[string]interface{})["retryStrategy"])
+	assert.NotContains(t, createdMonoVertexSpec, "replicas")
+	assert.Nil(t, createdMonoVertexSpec["lifecycle"].(map[string]interface{})["desiredPhase"])
+
+	// NumaflowControllerRollout
+	numaflowControllerRollout := &apiv1.NumaflowControllerRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: "numaflow-controller"},
+		Spec:       apiv1.NumaflowControllerRolloutSpec{Controller: apiv1.Controller{Version: "9.9.9"}},
+	}
+	assertInvalid(numaplaneClient.Create(ctx, numaflowControllerRollout), `spec.controller.version: Unsupported value: "9.9.9"`)
+	numaflowControllerRollout.Spec.Controller.Version = "1.4.0"
+	assert.NoError(t, numaplaneClient.Create(ctx, numaflowControllerRollout))
+}
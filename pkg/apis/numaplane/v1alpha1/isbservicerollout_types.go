@@ -27,6 +27,16 @@ import (
 // ISBServiceRolloutSpec defines the desired state of ISBServiceRollout
 type ISBServiceRolloutSpec struct {
 	InterStepBufferService InterStepBufferService `json:"interStepBufferService"`
+
+	// Strategy describes how upgrades are performed
+	// +optional
+	Strategy *ISBServiceRolloutStrategy `json:"strategy,omitempty"`
+
+	// ProgressDeadlineSeconds is how long the Rollout may take to deploy a change to its child before the
+	// ProgressDeadlineExceeded Condition is set. If not set, the global progressDeadlineSeconds applies; 0 means no deadline.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
 }
 
 // InterStepBufferService includes the spec of InterStepBufferService in Numaflow
@@ -42,6 +52,17 @@ type ISBServiceRolloutStatus struct {
 
 	// UpgradeInProgress indicates the upgrade strategy currently being used and affecting the resource state or empty if no upgrade is in progress
 	UpgradeInProgress UpgradeStrategy `json:"upgradeInProgress,omitempty"`
+
+	// UserStrategy is the strategy in effect for updates which risk data loss, whether it comes from the Rollout,
+	// the namespace or the global default
+	UserStrategy UserUpgradeStrategy `json:"userStrategy,omitempty"`
+
+	// NameCount is used as a suffix for the name of the managed InterStepBufferService, to uniquely
+	// identify an InterStepBufferService.
+	NameCount *int32 `json:"nameCount,omitempty"`
+
+	// ProgressiveStatus describes the state of the Progressive upgrade, if any
+	ProgressiveStatus ProgressiveStatus `json:"progressiveStatus,omitempty"`
 }
 
 // +genclient
@@ -78,10 +99,42 @@ func (isbServiceRollout *ISBServiceRollout) GetObjectMeta() *metav1.ObjectMeta {
 func (isbServiceRollout *ISBServiceRollout) GetStatus() *Status {
 	return &isbServiceRollout.Status.Status
 }
+
+func (isbServiceRollout *ISBServiceRollout) GetPauseRequestStatus() *PauseStatus {
+	return &isbServiceRollout.Status.PauseRequestStatus
+}
+
+// GetUserUpgradeStrategy returns the upgrade strategy set on the Rollout, if any
+func (isbServiceRollout *ISBServiceRollout) GetUserUpgradeStrategy() UserUpgradeStrategy {
+	if isbServiceRollout.Spec.Strategy == nil {
+		return ""
+	}
+	return isbServiceRollout.Spec.Strategy.Type
+}
+
+// GetMaintenanceWindows returns the maintenance windows set on the Rollout, if any
+func (isbServiceRollout *ISBServiceRollout) GetMaintenanceWindows() []MaintenanceWindow {
+	if isbServiceRollout.Spec.Strategy == nil {
+		return nil
+	}
+	return isbServiceRollout.Spec.Strategy.MaintenanceWindows
+}
 func (isbServiceRollout *ISBServiceRollout) GetChildPluralName() string {
 	return "interstepbufferservices"
 }
 
+// the following functions implement the ProgressiveRolloutObject interface:
+func (isbServiceRollout *ISBServiceRollout) GetProgressiveStrategy() ProgressiveStrategy {
+	if isbServiceRollout.Spec.Strategy == nil {
+		return ProgressiveStrategy{}
+	}
+	return isbServiceRollout.Spec.Strategy.Progressive
+}
+
+func (isbServiceRollout *ISBServiceRollout) GetProgressiveStatus() *ProgressiveStatus {
+	return &isbServiceRollout.Status.ProgressiveStatus
+}
+
 func init() {
 	SchemeBuilder.Register(&ISBServiceRollout{}, &ISBServiceRolloutList{})
 }
@@ -29,6 +29,20 @@ const (
 // MonoVertexRolloutSpec defines the desired state of MonoVertexRollout
 type MonoVertexRolloutSpec struct {
 	MonoVertex MonoVertex `json:"monoVertex"`
+
+	// Strategy describes how upgrades are performed
+	// +optional
+	Strategy *MonoVertexRolloutStrategy `json:"strategy,omitempty"`
+
+	// Paused, if true, pauses the MonoVertex until it's set back to false
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// ProgressDeadlineSeconds is how long the Rollout may take to deploy a change to its child before the
+	// ProgressDeadlineExceeded Condition is set. If not set, the global progressDeadlineSeconds applies; 0 means no deadline.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
 }
 
 // MonoVertex includes the spec of MonoVertex in Numaflow
@@ -44,9 +58,20 @@ type MonoVertexRolloutStatus struct {
 	// UpgradeInProgress indicates the upgrade strategy currently being used and affecting the resource state or empty if no upgrade is in progress
 	UpgradeInProgress UpgradeStrategy `json:"upgradeInProgress,omitempty"`
 
+	// UserStrategy is the strategy in effect for updates which risk data loss, whether it comes from the Rollout,
+	// the namespace or the global default
+	UserStrategy UserUpgradeStrategy `json:"userStrategy,omitempty"`
+
 	// NameCount is used as a suffix for the name of the managed pipeline, to uniquely
 	// identify a pipeline.
 	NameCount *int32 `json:"nameCount,omitempty"`
+
+	// ProgressiveStatus describes the state of the Progressive upgrade, if any
+	ProgressiveStatus ProgressiveStatus `json:"progressiveStatus,omitempty"`
+
+	// PauseRequests are the active requests to pause the MonoVertex
+	// +optional
+	PauseRequests []PauseRequest `json:"pauseRequests,omitempty"`
 }
 
 // +genclient
@@ -88,6 +113,42 @@ func (monoVertexRollout *MonoVertexRollout) GetChildPluralName() string {
 	return "monovertices"
 }
 
+// the following functions implement the ProgressiveRolloutObject interface:
+func (monoVertexRollout *MonoVertexRollout) GetProgressiveStrategy() ProgressiveStrategy {
+	if monoVertexRollout.Spec.Strategy == nil {
+		return ProgressiveStrategy{}
+	}
+	return monoVertexRollout.Spec.Strategy.Progressive
+}
+
+func (monoVertexRollout *MonoVertexRollout) GetProgressiveStatus() *ProgressiveStatus {
+	return &monoVertexRollout.Status.ProgressiveStatus
+}
+
+// GetUserUpgradeStrategy returns the upgrade strategy set on the Rollout, if any
+func (monoVertexRollout *MonoVertexRollout) GetUserUpgradeStrategy() UserUpgradeStrategy {
+	if monoVertexRollout.Spec.Strategy == nil {
+		return ""
+	}
+	return monoVertexRollout.Spec.Strategy.Type
+}
+
+// GetMaintenanceWindows returns the maintenance windows set on the Rollout, if any
+func (monoVertexRollout *MonoVertexRollout) GetMaintenanceWindows() []MaintenanceWindow {
+	if monoVertexRollout.Spec.Strategy == nil {
+		return nil
+	}
+	return monoVertexRollout.Spec.Strategy.MaintenanceWindows
+}
+
+// GetCanarySteps returns the canary steps of the Progressive strategy, if any
+func (monoVertexRollout *MonoVertexRollout) GetCanarySteps() []CanaryStep {
+	if monoVertexRollout.Spec.Strategy == nil || monoVertexRollout.Spec.Strategy.Canary == nil {
+		return nil
+	}
+	return monoVertexRollout.Spec.Strategy.Canary.Steps
+}
+
 func init() {
 	SchemeBuilder.Register(&MonoVertexRollout{}, &MonoVertexRolloutList{})
 }
@@ -31,12 +31,65 @@ type Controller struct {
 // NumaflowControllerRolloutSpec defines the desired state of NumaflowControllerRollout
 type NumaflowControllerRolloutSpec struct {
 	Controller Controller `json:"controller"`
+
+	// ClusterScoped indicates that the Numaflow Controller manages Pipelines in all of the namespaces selected by
+	// NamespaceSelector rather than only in the namespace of this NumaflowControllerRollout.
+	// Its Pipelines are the ones whose "numaflow.numaproj.io/instance" annotation matches the Controller's InstanceID.
+	// +optional
+	ClusterScoped bool `json:"clusterScoped,omitempty"`
+	// NamespaceSelector selects the namespaces managed by a cluster-scoped Numaflow Controller (all namespaces if not set)
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Strategy describes how upgrades are performed
+	// +optional
+	Strategy *NumaflowControllerRolloutStrategy `json:"strategy,omitempty"`
+
+	// ProgressDeadlineSeconds is how long the Rollout may take to deploy a change to its child before the
+	// ProgressDeadlineExceeded Condition is set. If not set, the global progressDeadlineSeconds applies; 0 means no deadline.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
 }
 
 // NumaflowControllerRolloutStatus defines the observed state of NumaflowControllerRollout
 type NumaflowControllerRolloutStatus struct {
 	Status             `json:",inline"`
 	PauseRequestStatus PauseStatus `json:"pauseRequestStatus,omitempty"`
+	// ManagedNamespaces are the namespaces managed by a cluster-scoped Numaflow Controller
+	ManagedNamespaces []string `json:"managedNamespaces,omitempty"`
+	// NameCount is used to generate the InstanceIDs of the Numaflow Controllers created by Progressive upgrades
+	NameCount *int32 `json:"nameCount,omitempty"`
+	// ProgressiveStatus describes the Numaflow Controllers involved in a Progressive upgrade
+	ProgressiveStatus ControllerProgressiveStatus `json:"progressiveStatus,omitempty"`
+}
+
+// ControllerProgressiveStatus describes a Progressive upgrade of the Numaflow Controller: the new version runs alongside
+// the promoted one under a distinct InstanceID, and the Pipelines (followed by the MonoVertices and InterStepBufferServices)
+// move onto it one at a time
+type ControllerProgressiveStatus struct {
+	// PromotedInstanceID is the InstanceID of the Numaflow Controller managing the children which haven't moved
+	// (if not set, it's the InstanceID in the spec)
+	PromotedInstanceID string `json:"promotedInstanceID,omitempty"`
+	// PromotedVersion is the version of the promoted Numaflow Controller while an upgrade is in progress
+	PromotedVersion string `json:"promotedVersion,omitempty"`
+	// UpgradingInstanceID is the InstanceID of the new Numaflow Controller while an upgrade is in progress
+	UpgradingInstanceID string `json:"upgradingInstanceID,omitempty"`
+	// MigratedChildren are the children which have moved to the new Numaflow Controller, in the order they moved
+	MigratedChildren []MigratedChild `json:"migratedChildren,omitempty"`
+	// FailedVersion is the version to which the last Progressive upgrade failed: it isn't attempted again until the version
+	// in the spec changes
+	FailedVersion string `json:"failedVersion,omitempty"`
+}
+
+// MigratedChild is the child of a Rollout which has moved to a new Numaflow Controller
+type MigratedChild struct {
+	// Kind of the Rollout: PipelineRollout, MonoVertexRollout or ISBServiceRollout
+	Kind string `json:"kind"`
+	// Name of the Rollout
+	Name string `json:"name"`
+	// Healthy indicates that the child was verified to be healthy after moving
+	Healthy bool `json:"healthy,omitempty"`
 }
 
 // +genclient
@@ -67,6 +120,31 @@ func init() {
 	SchemeBuilder.Register(&NumaflowControllerRollout{}, &NumaflowControllerRolloutList{})
 }
 
+func (numaflowControllerRollout *NumaflowControllerRollout) GetStatus() *Status {
+	return &numaflowControllerRollout.Status.Status
+}
+
+// GetMaintenanceWindows returns the maintenance windows set on the Rollout, if any
+func (numaflowControllerRollout *NumaflowControllerRollout) GetMaintenanceWindows() []MaintenanceWindow {
+	if numaflowControllerRollout.Spec.Strategy == nil {
+		return nil
+	}
+	return numaflowControllerRollout.Spec.Strategy.MaintenanceWindows
+}
+
+func (numaflowControllerRollout *NumaflowControllerRollout) GetPauseRequestStatus() *PauseStatus {
+	return &numaflowControllerRollout.Status.PauseRequestStatus
+}
+
+// GetPromotedInstanceID returns the InstanceID of the Numaflow Controller managing the children which aren't moving to a
+// new one
+func (numaflowControllerRollout *NumaflowControllerRollout) GetPromotedInstanceID() string {
+	if numaflowControllerRollout.Status.ProgressiveStatus.PromotedInstanceID != "" {
+		return numaflowControllerRollout.Status.ProgressiveStatus.PromotedInstanceID
+	}
+	return numaflowControllerRollout.Spec.Controller.InstanceID
+}
+
 // IsHealthy indicates whether the NumaflowController rollout is healthy or not
 func (nc *NumaflowControllerRolloutStatus) IsHealthy() bool {
 	return nc.Phase == PhaseDeployed || nc.Phase == PhasePending
@@ -29,6 +29,28 @@ const (
 // PipelineRolloutSpec defines the desired state of PipelineRollout
 type PipelineRolloutSpec struct {
 	Pipeline Pipeline `json:"pipeline"`
+
+	// Strategy describes how upgrades are performed
+	// +optional
+	Strategy *PipelineTypeRolloutStrategy `json:"strategy,omitempty"`
+
+	// Paused, if true, pauses the Pipeline. Once it's set back to false, the Pipeline only resumes if nothing else
+	// (such as an ISBService or Numaflow Controller update) still requires it to be paused.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// ProgressDeadlineSeconds is how long the Rollout may take to deploy a change to its child before the
+	// ProgressDeadlineExceeded Condition is set. If not set, the global progressDeadlineSeconds applies; 0 means no deadline.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// DependsOn names PipelineRollouts in the same namespace which must be upgraded before this one: an upgrade of this
+	// PipelineRollout's Pipeline is held back until each of them is Deployed with healthy children at its current generation.
+	// (For example, a Pipeline which writes to a topic may depend on the Pipeline which reads from it.)
+	// Dependencies mustn't form a cycle.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
 // Pipeline includes the spec of Pipeline in Numaflow
@@ -43,12 +65,63 @@ type PipelineRolloutStatus struct {
 	Status      `json:",inline"`
 	PauseStatus PauseStatus `json:"pauseStatus,omitempty"`
 
+	// PauseRequests are the active requests to pause the Pipeline
+	// +optional
+	PauseRequests []PauseRequest `json:"pauseRequests,omitempty"`
+
 	// UpgradeInProgress indicates the upgrade strategy currently being used and affecting the resource state or empty if no upgrade is in progress
 	UpgradeInProgress UpgradeStrategy `json:"upgradeInProgress,omitempty"`
 
+	// UserStrategy is the strategy in effect for updates which risk data loss, whether it comes from the Rollout,
+	// the namespace or the global default
+	UserStrategy UserUpgradeStrategy `json:"userStrategy,omitempty"`
+
 	// NameCount is used as a suffix for the name of the managed pipeline, to uniquely
 	// identify a pipeline.
 	NameCount *int32 `json:"nameCount,omitempty"`
+
+	// ProgressiveStatus describes the state of the Progressive upgrade, if any
+	ProgressiveStatus ProgressiveStatus `json:"progressiveStatus,omitempty"`
+
+	// Plan describes what reconciling the PipelineRollout would do; it's only set while the PipelineRollout has the
+	// "numaplane.numaproj.io/dry-run" annotation set to "true"
+	// +optional
+	Plan *RolloutPlan `json:"plan,omitempty"`
+}
+
+// RolloutPlan describes what Numaplane would do to reconcile the current spec of a Rollout
+type RolloutPlan struct {
+	// ObservedGeneration is the generation of the Rollout that the plan was computed for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// UpgradeStrategy is the strategy that would be used to update the existing child, if any
+	// +optional
+	UpgradeStrategy UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+
+	// PausePipelines indicates whether the Pipeline would be paused
+	// +optional
+	PausePipelines bool `json:"pausePipelines,omitempty"`
+
+	// ChildrenToCreate are the names of the children that would be created
+	// +optional
+	ChildrenToCreate []string `json:"childrenToCreate,omitempty"`
+
+	// ChildrenToUpdate are the names of the children that would be updated in place
+	// +optional
+	ChildrenToUpdate []string `json:"childrenToUpdate,omitempty"`
+
+	// ChildrenToDelete are the names of the children that would be deleted (or, for a Progressive upgrade, drained
+	// and then deleted once the new child is promoted)
+	// +optional
+	ChildrenToDelete []string `json:"childrenToDelete,omitempty"`
+
+	// Message summarizes the plan
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Diff is a unified diff from the existing child spec to the desired child spec
+	// +optional
+	Diff string `json:"diff,omitempty"`
 }
 
 type UpgradeStrategy string
@@ -60,6 +133,7 @@ const (
 	UpgradeStrategyApply       UpgradeStrategy = "DirectApply"
 	UpgradeStrategyPPND        UpgradeStrategy = "PipelinePauseAndDrain"
 	UpgradeStrategyProgressive UpgradeStrategy = "Progressive"
+	UpgradeStrategyRecreate    UpgradeStrategy = "Recreate"
 )
 
 // +genclient
@@ -102,6 +176,34 @@ func (pipelineRollout *PipelineRollout) GetChildPluralName() string {
 	return "pipelines"
 }
 
+// the following functions implement the ProgressiveRolloutObject interface:
+func (pipelineRollout *PipelineRollout) GetProgressiveStrategy() ProgressiveStrategy {
+	if pipelineRollout.Spec.Strategy == nil {
+		return ProgressiveStrategy{}
+	}
+	return pipelineRollout.Spec.Strategy.Progressive
+}
+
+func (pipelineRollout *PipelineRollout) GetProgressiveStatus() *ProgressiveStatus {
+	return &pipelineRollout.Status.ProgressiveStatus
+}
+
+// GetUserUpgradeStrategy returns the upgrade strategy set on the Rollout, if any
+func (pipelineRollout *PipelineRollout) GetUserUpgradeStrategy() UserUpgradeStrategy {
+	if pipelineRollout.Spec.Strategy == nil {
+		return ""
+	}
+	return pipelineRollout.Spec.Strategy.Type
+}
+
+// GetMaintenanceWindows returns the maintenance windows set on the Rollout, if any
+func (pipelineRollout *PipelineRollout) GetMaintenanceWindows() []MaintenanceWindow {
+	if pipelineRollout.Spec.Strategy == nil {
+		return nil
+	}
+	return pipelineRollout.Spec.Strategy.MaintenanceWindows
+}
+
 func init() {
 	SchemeBuilder.Register(&PipelineRollout{}, &PipelineRolloutList{})
 }
@@ -0,0 +1,65 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// RevisionOutcome is the result of applying a RolloutRevision
+type RevisionOutcome string
+
+const (
+	// RevisionOutcomePending indicates that the revision is still being applied
+	RevisionOutcomePending RevisionOutcome = "Pending"
+
+	// RevisionOutcomeSucceeded indicates that the revision was applied and its child became healthy
+	RevisionOutcomeSucceeded RevisionOutcome = "Succeeded"
+
+	// RevisionOutcomeFailed indicates that applying the revision failed
+	RevisionOutcomeFailed RevisionOutcome = "Failed"
+
+	// RevisionOutcomeSuperseded indicates that the revision was replaced by a newer one before it finished being applied
+	RevisionOutcomeSuperseded RevisionOutcome = "Superseded"
+)
+
+// RolloutRevision is a child definition which was applied by a Rollout, as kept in its revision history
+type RolloutRevision struct {
+	// Revision is the number of the revision, starting at 1 and incremented with each new child definition
+	Revision int64 `json:"revision"`
+
+	// Generation is the Rollout's generation at which the revision was applied
+	Generation int64 `json:"generation"`
+
+	// AppliedTime is when the revision was first applied
+	AppliedTime metav1.Time `json:"appliedTime"`
+
+	// Definition is the child definition of the Rollout's spec (e.g. spec.pipeline of a PipelineRollout)
+	Definition runtime.RawExtension `json:"definition"`
+
+	// Strategy is the upgrade strategy which was used to apply the revision
+	// +optional
+	Strategy UpgradeStrategy `json:"strategy,omitempty"`
+
+	// Outcome is the result of applying the revision
+	Outcome RevisionOutcome `json:"outcome"`
+
+	// Message gives the reason for a failed Outcome
+	// +optional
+	Message string `json:"message,omitempty"`
+}
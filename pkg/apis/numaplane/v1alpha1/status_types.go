@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
 	"reflect"
 	"sort"
 	"time"
@@ -51,6 +52,22 @@ const (
 	// of pausing pipelines
 	ConditionPausingPipelines ConditionType = "PausingPipelines"
 
+	// ConditionPauseTimedOut applies to ISBServiceRollout or NumaflowControllerRollout for when the Pipelines didn't all
+	// pause within the pause timeout; its Reason is the policy which was applied
+	ConditionPauseTimedOut ConditionType = "PauseTimedOut"
+
+	// ConditionAwaitingMaintenanceWindow indicates that an update requiring a disruptive upgrade strategy is being held
+	// until the next maintenance window
+	ConditionAwaitingMaintenanceWindow ConditionType = "AwaitingMaintenanceWindow"
+
+	// ConditionProgressDeadlineExceeded indicates that the Rollout has been progressing towards deploying its child for longer
+	// than its progress deadline; the message says what it's waiting for
+	ConditionProgressDeadlineExceeded ConditionType = "ProgressDeadlineExceeded"
+
+	// ConditionGuardrailsBlocked indicates that the guardrails block the update of the child resource; the message says which
+	// changes they don't allow
+	ConditionGuardrailsBlocked ConditionType = "GuardrailsBlocked"
+
 	// ConditionProgressiveUpgradeSucceeded indicates that whether the progressive upgrade succeeded.
 	ConditionProgressiveUpgradeSucceeded ConditionType = "ProgressiveUpgradeSucceed"
 )
@@ -71,6 +88,14 @@ type Status struct {
 
 	// ObservedGeneration stores the generation value observed when setting the current Phase
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ProgressingSince is when the Rollout began progressing towards deploying its child, if it hasn't been deployed since
+	// +optional
+	ProgressingSince *metav1.Time `json:"progressingSince,omitempty"`
+
+	// WaitingFor describes what the Rollout was last waiting for while progressing towards deploying its child
+	// +optional
+	WaitingFor string `json:"waitingFor,omitempty"`
 }
 
 // PauseStatus is a common structure used to communicate how long Pipelines are paused.
@@ -80,6 +105,50 @@ type PauseStatus struct {
 
 	// The end time for the last pause of the Pipeline.
 	LastPauseEndTime metav1.Time `json:"lastPauseEndTime,omitempty"`
+
+	// TimeoutPolicy is the policy applied if the Pipelines don't all pause within the pause timeout, if there is one.
+	// +optional
+	TimeoutPolicy PauseTimeoutPolicy `json:"timeoutPolicy,omitempty"`
+
+	// Progress of the last pause requested of the Pipelines, as "x/y pipelines paused"
+	// +optional
+	Progress string `json:"progress,omitempty"`
+}
+
+// PauseRequest describes an active request to pause a child
+type PauseRequest struct {
+	// Requester is who requests the pause: "user" for the Rollout's own spec.paused, otherwise the Rollout whose update
+	// requires the pause, e.g. "NumaflowControllerRollout" or "ISBServiceRollout/my-isbsvc"
+	Requester string `json:"requester"`
+
+	// Since is when the pause was requested
+	Since metav1.Time `json:"since"`
+}
+
+// +kubebuilder:validation:Enum="";abort;force;notify
+type PauseTimeoutPolicy string
+
+const (
+	// PauseTimeoutPolicyAbort stops requesting the Pipelines to pause, letting them resume, and doesn't update the child
+	// until the Rollout is changed again
+	PauseTimeoutPolicyAbort PauseTimeoutPolicy = "abort"
+
+	// PauseTimeoutPolicyForce updates the child anyway, as if the Pipelines allowed data loss
+	PauseTimeoutPolicyForce PauseTimeoutPolicy = "force"
+
+	// PauseTimeoutPolicyNotify keeps waiting for the Pipelines to pause, having marked the PauseTimedOut Condition and
+	// emitted an Event
+	PauseTimeoutPolicyNotify PauseTimeoutPolicy = "notify"
+)
+
+// IsValid returns whether the policy is one of the known policies or unset
+func (policy PauseTimeoutPolicy) IsValid() bool {
+	switch policy {
+	case "", PauseTimeoutPolicyAbort, PauseTimeoutPolicyForce, PauseTimeoutPolicyNotify:
+		return true
+	default:
+		return false
+	}
 }
 
 func (status *Status) SetPhase(phase Phase, msg string) {
@@ -124,6 +193,8 @@ func (s *Status) GetCondition(t ConditionType) *metav1.Condition {
 // Init sets certain Status parameters to a default initial state
 func (status *Status) Init(generation int64) {
 	status.SetObservedGeneration(generation)
+	// anything still being waited for is recorded again during this reconciliation
+	status.WaitingFor = ""
 	// rationale for commenting this out:
 	// "Pending" is now something we indicate when a rollout has been updated and we are trying to deploy it,
 	// as opposed to meaning that we're "pending reconciliation"
@@ -133,12 +204,23 @@ func (status *Status) Init(generation int64) {
 // MarkPending sets Phase to Pending
 func (status *Status) MarkPending() {
 	status.SetPhase(PhasePending, "Progressing")
+	if status.ProgressingSince == nil {
+		now := metav1.Now()
+		status.ProgressingSince = &now
+	}
 }
 
 // MarkDeployed sets Phase to Deployed
 func (status *Status) MarkDeployed(generation int64) {
 	status.SetPhase(PhaseDeployed, "Deployed")
 	status.MarkTrue(ConditionChildResourceDeployed, generation)
+	status.ProgressingSince = nil
+	status.WaitingFor = ""
+}
+
+// MarkWaitingFor records what the Rollout is waiting for before it can finish progressing
+func (status *Status) MarkWaitingFor(format string, args ...interface{}) {
+	status.WaitingFor = fmt.Sprintf(format, args...)
 }
 
 // MarkFailed sets Phase to Failed
@@ -0,0 +1,319 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum="";progressive;pause-and-drain
+type UserUpgradeStrategy string
+
+const (
+	UserUpgradeStrategyProgressive UserUpgradeStrategy = "progressive"
+	UserUpgradeStrategyPPND        UserUpgradeStrategy = "pause-and-drain"
+)
+
+// RolloutStrategy describes how a Rollout is upgraded
+type RolloutStrategy struct {
+	// Type is the strategy used when an update risks data loss ("progressive" or "pause-and-drain").
+	// If set, it overrides the namespace-level and global default strategies.
+	// +optional
+	Type UserUpgradeStrategy `json:"type,omitempty"`
+
+	// MaintenanceWindows, if set, are the only times at which an update requiring the "progressive" or "pause-and-drain"
+	// strategy, or the child to be recreated, may start; such an update is held until the next window opens. Other updates
+	// are applied right away.
+	// If set, they override the namespace-level and global maintenance windows.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// NumaflowControllerRolloutStrategy describes how a NumaflowControllerRollout is upgraded
+type NumaflowControllerRolloutStrategy struct {
+	// MaintenanceWindows, if set, are the only times at which an update of the Numaflow Controller, which pauses the
+	// Pipelines or moves them to a new Numaflow Controller, may start; such an update is held until the next window opens.
+	// If set, they override the namespace-level and global maintenance windows.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of time during which disruptive upgrades may start
+type MaintenanceWindow struct {
+	// Schedule is a cron expression ("minute hour day-of-month month day-of-week") for when the window opens,
+	// e.g. "0 2 * * 6,0" for 02:00 every Saturday and Sunday
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the IANA time zone of the Schedule, e.g. "America/Los_Angeles"; it defaults to UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// PipelineTypeRolloutStrategy describes how a PipelineRollout or MonoVertexRollout is upgraded
+type PipelineTypeRolloutStrategy struct {
+	RolloutStrategy `json:",inline"`
+
+	// Progressive configures the Progressive upgrade strategy
+	// +optional
+	Progressive ProgressiveStrategy `json:"progressive,omitempty"`
+}
+
+// ISBServiceRolloutStrategy describes how an ISBServiceRollout is upgraded
+type ISBServiceRolloutStrategy struct {
+	RolloutStrategy `json:",inline"`
+
+	// Progressive configures the Progressive upgrade strategy, in which a new InterStepBufferService is created
+	// alongside the current one and the Pipelines are moved onto it before the current one is deleted
+	// +optional
+	Progressive ProgressiveStrategy `json:"progressive,omitempty"`
+}
+
+// MonoVertexRolloutStrategy describes how a MonoVertexRollout is upgraded
+type MonoVertexRolloutStrategy struct {
+	PipelineTypeRolloutStrategy `json:",inline"`
+
+	// Canary, if set, shifts replicas from the promoted MonoVertex to the upgrading MonoVertex in steps during a
+	// Progressive upgrade, rather than all at once
+	// +optional
+	Canary *CanaryStrategy `json:"canary,omitempty"`
+}
+
+// CanaryStrategy describes the steps of a canary upgrade
+type CanaryStrategy struct {
+	// Steps are performed in order once the upgrading child is healthy
+	Steps []CanaryStep `json:"steps"`
+}
+
+// CanaryStep gives the upgrading child a percentage of the replicas and then verifies it
+type CanaryStep struct {
+	// Weight is the percentage of the replicas given to the upgrading child
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
+
+	// Pause is how long to wait at this step before moving to the next one
+	// +optional
+	Pause *metav1.Duration `json:"pause,omitempty"`
+
+	// Analysis, if set, must pass before moving to the next step
+	// +optional
+	Analysis *Analysis `json:"analysis,omitempty"`
+}
+
+// ProgressiveStrategy configures the Progressive upgrade strategy
+type ProgressiveStrategy struct {
+	// Analysis, if set, is run against the upgrading child once it's healthy; the upgrading child is only promoted
+	// if the Analysis passes
+	// +optional
+	Analysis *Analysis `json:"analysis,omitempty"`
+
+	// AutoRollback, if true, drains and deletes the upgrading child if it fails (or fails Analysis), ending the upgrade
+	// and leaving the promoted child in place. The failed spec is not retried until it's changed.
+	// +optional
+	AutoRollback bool `json:"autoRollback,omitempty"`
+
+	// ManualPromotion, if true, holds the upgrading child once it's healthy (and has completed any canary steps and passed
+	// Analysis) until the Rollout is annotated with "numaplane.numaproj.io/promote: true". Annotating it with
+	// "numaplane.numaproj.io/abort: true" instead rolls the upgrade back.
+	// +optional
+	ManualPromotion bool `json:"manualPromotion,omitempty"`
+}
+
+// Analysis describes a set of metric checks which must pass before the upgrading child is promoted
+type Analysis struct {
+	// Prometheus is the metrics provider used to evaluate the Metrics
+	Prometheus PrometheusProvider `json:"prometheus"`
+
+	// Window is how long the upgrading child is observed once healthy before it can be promoted.
+	// Metrics are evaluated throughout the Window and any failure fails the Analysis.
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// Metrics are the checks to evaluate
+	Metrics []AnalysisMetric `json:"metrics"`
+}
+
+// PrometheusProvider describes how to reach a Prometheus-compatible query API
+type PrometheusProvider struct {
+	// Address of the Prometheus server, e.g. "http://prometheus.monitoring:9090"
+	Address string `json:"address"`
+}
+
+// AnalysisMetric is a single query whose result is compared to a threshold
+type AnalysisMetric struct {
+	// Name of the metric check
+	Name string `json:"name"`
+
+	// Query must evaluate to a single value. It's a Go template which may reference
+	// {{.Namespace}} and {{.Name}} of the upgrading child and {{.Window}} of the Analysis.
+	Query string `json:"query"`
+
+	// Operator used to compare the query result with the Threshold, as in "<result> <operator> <threshold>"
+	Operator AnalysisOperator `json:"operator"`
+
+	// Threshold the query result is compared to
+	Threshold string `json:"threshold"`
+}
+
+// +kubebuilder:validation:Enum="<";"<=";">";">=";"==";"!="
+type AnalysisOperator string
+
+const (
+	AnalysisOperatorLessThan           AnalysisOperator = "<"
+	AnalysisOperatorLessThanOrEqual    AnalysisOperator = "<="
+	AnalysisOperatorGreaterThan        AnalysisOperator = ">"
+	AnalysisOperatorGreaterThanOrEqual AnalysisOperator = ">="
+	AnalysisOperatorEqual              AnalysisOperator = "=="
+	AnalysisOperatorNotEqual           AnalysisOperator = "!="
+)
+
+// +kubebuilder:validation:Enum="";Running;Successful;Failed
+type AnalysisPhase string
+
+const (
+	AnalysisPhaseRunning    AnalysisPhase = "Running"
+	AnalysisPhaseSuccessful AnalysisPhase = "Successful"
+	AnalysisPhaseFailed     AnalysisPhase = "Failed"
+)
+
+// +kubebuilder:validation:Enum="";Creating;Assessing;AwaitingPromotion;Promoting;Draining;Done
+type ProgressiveState string
+
+const (
+	// ProgressiveStateCreating indicates that the upgrading child is being created
+	ProgressiveStateCreating ProgressiveState = "Creating"
+
+	// ProgressiveStateAssessing indicates that we're waiting for the upgrading child to be healthy and pass Analysis
+	ProgressiveStateAssessing ProgressiveState = "Assessing"
+
+	// ProgressiveStateAwaitingPromotion indicates that the upgrading child has passed assessment and, the strategy requiring
+	// manual promotion, we're waiting for the user to promote it
+	ProgressiveStateAwaitingPromotion ProgressiveState = "AwaitingPromotion"
+
+	// ProgressiveStatePromoting indicates that the upgrading child is replacing the promoted child
+	ProgressiveStatePromoting ProgressiveState = "Promoting"
+
+	// ProgressiveStateDraining indicates that the previously promoted child is being drained
+	ProgressiveStateDraining ProgressiveState = "Draining"
+
+	// ProgressiveStateDone indicates that the last Progressive upgrade is complete
+	ProgressiveStateDone ProgressiveState = "Done"
+)
+
+// ProgressiveStatus describes the state of a Progressive upgrade
+type ProgressiveStatus struct {
+	// State is the current step of the Progressive upgrade
+	// +optional
+	State ProgressiveState `json:"state,omitempty"`
+
+	// PromotedChildName is the name of the child which was promoted when the Progressive upgrade began
+	// +optional
+	PromotedChildName string `json:"promotedChildName,omitempty"`
+
+	// UpgradingChildName is the name of the child being upgraded to
+	// +optional
+	UpgradingChildName string `json:"upgradingChildName,omitempty"`
+
+	// Analysis is the status of the Analysis of the upgrading child
+	// +optional
+	Analysis *AnalysisStatus `json:"analysis,omitempty"`
+
+	// Canary is the status of the canary steps, if the Rollout defines any
+	// +optional
+	Canary *CanaryStatus `json:"canary,omitempty"`
+
+	// AwaitingPromotionSince is when the upgrading child began waiting to be promoted manually, while the State is
+	// AwaitingPromotion
+	// +optional
+	AwaitingPromotionSince *metav1.Time `json:"awaitingPromotionSince,omitempty"`
+
+	// FailedSpecHash is the hash of the last child spec whose upgrade failed and was rolled back
+	// +optional
+	FailedSpecHash string `json:"failedSpecHash,omitempty"`
+}
+
+// CanaryStatus describes the progress of the canary steps of an upgrading child
+type CanaryStatus struct {
+	// ChildName is the name of the upgrading child
+	ChildName string `json:"childName"`
+
+	// TotalReplicas is the number of replicas of the promoted child when the canary began, which are divided
+	// between the two children
+	TotalReplicas int32 `json:"totalReplicas"`
+
+	// CurrentStepIndex is the index of the step in progress
+	CurrentStepIndex int32 `json:"currentStepIndex"`
+
+	// Weight is the percentage of the replicas currently given to the upgrading child
+	Weight int32 `json:"weight"`
+
+	// StepStartTime is when the current step started, or unset if its weight hasn't been applied yet
+	// +optional
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+}
+
+// AnalysisStatus describes the state of the Analysis of an upgrading child
+type AnalysisStatus struct {
+	// ChildName is the name of the child being analyzed
+	ChildName string `json:"childName"`
+
+	// ChildGeneration is the generation of the child being analyzed: if the child changes, the Analysis restarts
+	ChildGeneration int64 `json:"childGeneration,omitempty"`
+
+	// Phase of the Analysis
+	Phase AnalysisPhase `json:"phase,omitempty"`
+
+	// StartTime is when the Analysis started
+	StartTime metav1.Time `json:"startTime,omitempty"`
+
+	// EndTime is when the Analysis completed
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// Message describes the result of the Analysis
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// MetricResults are the results from the latest evaluation of each metric
+	// +optional
+	MetricResults []AnalysisMetricResult `json:"metricResults,omitempty"`
+}
+
+// AnalysisMetricResult is the result of evaluating an AnalysisMetric
+type AnalysisMetricResult struct {
+	// Name of the metric check
+	Name string `json:"name"`
+
+	// Value returned by the query
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Passed indicates whether the value satisfied the threshold
+	Passed bool `json:"passed"`
+
+	// Message is set if the metric could not be evaluated
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// IsDone returns true if the Analysis is complete
+func (status *AnalysisStatus) IsDone() bool {
+	return status.Phase == AnalysisPhaseSuccessful || status.Phase == AnalysisPhaseFailed
+}
@@ -25,6 +25,153 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Analysis) DeepCopyInto(out *Analysis) {
+	*out = *in
+	out.Prometheus = in.Prometheus
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]AnalysisMetric, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Analysis.
+func (in *Analysis) DeepCopy() *Analysis {
+	if in == nil {
+		return nil
+	}
+	out := new(Analysis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisMetric) DeepCopyInto(out *AnalysisMetric) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisMetric.
+func (in *AnalysisMetric) DeepCopy() *AnalysisMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisMetricResult) DeepCopyInto(out *AnalysisMetricResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisMetricResult.
+func (in *AnalysisMetricResult) DeepCopy() *AnalysisMetricResult {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisMetricResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisStatus) DeepCopyInto(out *AnalysisStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	if in.MetricResults != nil {
+		in, out := &in.MetricResults, &out.MetricResults
+		*out = make([]AnalysisMetricResult, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisStatus.
+func (in *AnalysisStatus) DeepCopy() *AnalysisStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
+	*out = *in
+	if in.StepStartTime != nil {
+		in, out := &in.StepStartTime, &out.StepStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStatus.
+func (in *CanaryStatus) DeepCopy() *CanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStep) DeepCopyInto(out *CanaryStep) {
+	*out = *in
+	if in.Pause != nil {
+		in, out := &in.Pause, &out.Pause
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(Analysis)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStep.
+func (in *CanaryStep) DeepCopy() *CanaryStep {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStrategy) DeepCopyInto(out *CanaryStrategy) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]CanaryStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStrategy.
+func (in *CanaryStrategy) DeepCopy() *CanaryStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Controller) DeepCopyInto(out *Controller) {
 	*out = *in
@@ -55,6 +202,26 @@ func (in *ControllerDefinitions) DeepCopy() *ControllerDefinitions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerProgressiveStatus) DeepCopyInto(out *ControllerProgressiveStatus) {
+	*out = *in
+	if in.MigratedChildren != nil {
+		in, out := &in.MigratedChildren, &out.MigratedChildren
+		*out = make([]MigratedChild, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerProgressiveStatus.
+func (in *ControllerProgressiveStatus) DeepCopy() *ControllerProgressiveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerProgressiveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ISBServiceRollout) DeepCopyInto(out *ISBServiceRollout) {
 	*out = *in
@@ -118,6 +285,16 @@ func (in *ISBServiceRolloutList) DeepCopyObject() runtime.Object {
 func (in *ISBServiceRolloutSpec) DeepCopyInto(out *ISBServiceRolloutSpec) {
 	*out = *in
 	in.InterStepBufferService.DeepCopyInto(&out.InterStepBufferService)
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(ISBServiceRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ISBServiceRolloutSpec.
@@ -135,6 +312,12 @@ func (in *ISBServiceRolloutStatus) DeepCopyInto(out *ISBServiceRolloutStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
 	in.PauseRequestStatus.DeepCopyInto(&out.PauseRequestStatus)
+	if in.NameCount != nil {
+		in, out := &in.NameCount, &out.NameCount
+		*out = new(int32)
+		**out = **in
+	}
+	in.ProgressiveStatus.DeepCopyInto(&out.ProgressiveStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ISBServiceRolloutStatus.
@@ -147,6 +330,23 @@ func (in *ISBServiceRolloutStatus) DeepCopy() *ISBServiceRolloutStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ISBServiceRolloutStrategy) DeepCopyInto(out *ISBServiceRolloutStrategy) {
+	*out = *in
+	in.RolloutStrategy.DeepCopyInto(&out.RolloutStrategy)
+	in.Progressive.DeepCopyInto(&out.Progressive)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ISBServiceRolloutStrategy.
+func (in *ISBServiceRolloutStrategy) DeepCopy() *ISBServiceRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ISBServiceRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InterStepBufferService) DeepCopyInto(out *InterStepBufferService) {
 	*out = *in
@@ -164,6 +364,22 @@ func (in *InterStepBufferService) DeepCopy() *InterStepBufferService {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Metadata) DeepCopyInto(out *Metadata) {
 	*out = *in
@@ -193,6 +409,21 @@ func (in *Metadata) DeepCopy() *Metadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigratedChild) DeepCopyInto(out *MigratedChild) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigratedChild.
+func (in *MigratedChild) DeepCopy() *MigratedChild {
+	if in == nil {
+		return nil
+	}
+	out := new(MigratedChild)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonoVertex) DeepCopyInto(out *MonoVertex) {
 	*out = *in
@@ -273,6 +504,16 @@ func (in *MonoVertexRolloutList) DeepCopyObject() runtime.Object {
 func (in *MonoVertexRolloutSpec) DeepCopyInto(out *MonoVertexRolloutSpec) {
 	*out = *in
 	in.MonoVertex.DeepCopyInto(&out.MonoVertex)
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(MonoVertexRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonoVertexRolloutSpec.
@@ -294,6 +535,14 @@ func (in *MonoVertexRolloutStatus) DeepCopyInto(out *MonoVertexRolloutStatus) {
 		*out = new(int32)
 		**out = **in
 	}
+	in.ProgressiveStatus.DeepCopyInto(&out.ProgressiveStatus)
+	if in.PauseRequests != nil {
+		in, out := &in.PauseRequests, &out.PauseRequests
+		*out = make([]PauseRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonoVertexRolloutStatus.
@@ -306,12 +555,33 @@ func (in *MonoVertexRolloutStatus) DeepCopy() *MonoVertexRolloutStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonoVertexRolloutStrategy) DeepCopyInto(out *MonoVertexRolloutStrategy) {
+	*out = *in
+	in.PipelineTypeRolloutStrategy.DeepCopyInto(&out.PipelineTypeRolloutStrategy)
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonoVertexRolloutStrategy.
+func (in *MonoVertexRolloutStrategy) DeepCopy() *MonoVertexRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(MonoVertexRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NumaflowControllerRollout) DeepCopyInto(out *NumaflowControllerRollout) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -369,6 +639,21 @@ func (in *NumaflowControllerRolloutList) DeepCopyObject() runtime.Object {
 func (in *NumaflowControllerRolloutSpec) DeepCopyInto(out *NumaflowControllerRolloutSpec) {
 	*out = *in
 	out.Controller = in.Controller
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(NumaflowControllerRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NumaflowControllerRolloutSpec.
@@ -386,6 +671,17 @@ func (in *NumaflowControllerRolloutStatus) DeepCopyInto(out *NumaflowControllerR
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
 	in.PauseRequestStatus.DeepCopyInto(&out.PauseRequestStatus)
+	if in.ManagedNamespaces != nil {
+		in, out := &in.ManagedNamespaces, &out.ManagedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NameCount != nil {
+		in, out := &in.NameCount, &out.NameCount
+		*out = new(int32)
+		**out = **in
+	}
+	in.ProgressiveStatus.DeepCopyInto(&out.ProgressiveStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NumaflowControllerRolloutStatus.
@@ -398,6 +694,42 @@ func (in *NumaflowControllerRolloutStatus) DeepCopy() *NumaflowControllerRollout
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NumaflowControllerRolloutStrategy) DeepCopyInto(out *NumaflowControllerRolloutStrategy) {
+	*out = *in
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NumaflowControllerRolloutStrategy.
+func (in *NumaflowControllerRolloutStrategy) DeepCopy() *NumaflowControllerRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(NumaflowControllerRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PauseRequest) DeepCopyInto(out *PauseRequest) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PauseRequest.
+func (in *PauseRequest) DeepCopy() *PauseRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(PauseRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PauseStatus) DeepCopyInto(out *PauseStatus) {
 	*out = *in
@@ -495,6 +827,21 @@ func (in *PipelineRolloutList) DeepCopyObject() runtime.Object {
 func (in *PipelineRolloutSpec) DeepCopyInto(out *PipelineRolloutSpec) {
 	*out = *in
 	in.Pipeline.DeepCopyInto(&out.Pipeline)
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(PipelineTypeRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRolloutSpec.
@@ -512,11 +859,24 @@ func (in *PipelineRolloutStatus) DeepCopyInto(out *PipelineRolloutStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
 	in.PauseStatus.DeepCopyInto(&out.PauseStatus)
+	if in.PauseRequests != nil {
+		in, out := &in.PauseRequests, &out.PauseRequests
+		*out = make([]PauseRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.NameCount != nil {
 		in, out := &in.NameCount, &out.NameCount
 		*out = new(int32)
 		**out = **in
 	}
+	in.ProgressiveStatus.DeepCopyInto(&out.ProgressiveStatus)
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(RolloutPlan)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRolloutStatus.
@@ -529,6 +889,154 @@ func (in *PipelineRolloutStatus) DeepCopy() *PipelineRolloutStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineTypeRolloutStrategy) DeepCopyInto(out *PipelineTypeRolloutStrategy) {
+	*out = *in
+	in.RolloutStrategy.DeepCopyInto(&out.RolloutStrategy)
+	in.Progressive.DeepCopyInto(&out.Progressive)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineTypeRolloutStrategy.
+func (in *PipelineTypeRolloutStrategy) DeepCopy() *PipelineTypeRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineTypeRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProgressiveStatus) DeepCopyInto(out *ProgressiveStatus) {
+	*out = *in
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(AnalysisStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AwaitingPromotionSince != nil {
+		in, out := &in.AwaitingPromotionSince, &out.AwaitingPromotionSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProgressiveStatus.
+func (in *ProgressiveStatus) DeepCopy() *ProgressiveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProgressiveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProgressiveStrategy) DeepCopyInto(out *ProgressiveStrategy) {
+	*out = *in
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(Analysis)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProgressiveStrategy.
+func (in *ProgressiveStrategy) DeepCopy() *ProgressiveStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ProgressiveStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusProvider) DeepCopyInto(out *PrometheusProvider) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusProvider.
+func (in *PrometheusProvider) DeepCopy() *PrometheusProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutPlan) DeepCopyInto(out *RolloutPlan) {
+	*out = *in
+	if in.ChildrenToCreate != nil {
+		in, out := &in.ChildrenToCreate, &out.ChildrenToCreate
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChildrenToUpdate != nil {
+		in, out := &in.ChildrenToUpdate, &out.ChildrenToUpdate
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChildrenToDelete != nil {
+		in, out := &in.ChildrenToDelete, &out.ChildrenToDelete
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutPlan.
+func (in *RolloutPlan) DeepCopy() *RolloutPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRevision) DeepCopyInto(out *RolloutRevision) {
+	*out = *in
+	in.AppliedTime.DeepCopyInto(&out.AppliedTime)
+	in.Definition.DeepCopyInto(&out.Definition)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRevision.
+func (in *RolloutRevision) DeepCopy() *RolloutRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Status) DeepCopyInto(out *Status) {
 	*out = *in
@@ -539,6 +1047,10 @@ func (in *Status) DeepCopyInto(out *Status) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ProgressingSince != nil {
+		in, out := &in.ProgressingSince, &out.ProgressingSince
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Status.
@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"regexp"
 	"runtime"
 
 	k8sclientgo "k8s.io/client-go/kubernetes"
@@ -22,8 +21,54 @@ import (
 )
 
 func PrepareK8SEnvironment() (restConfig *rest.Config, numaflowClientSet *numaflowversioned.Clientset, numaplaneClient client.Client, k8sClientSet *k8sclientgo.Clientset, err error) {
+	testEnv, err := newTestEnvironment()
+	if err != nil {
+		return
+	}
+
+	// create REST Config
+	restConfig, err = testEnv.Start()
+	if err != nil {
+		return
+	}
+
+	// create clients for Numaflow, Numaplane, Kubernetes
+	numaplaneClient, err = newNumaplaneClient(restConfig)
+	if err != nil {
+		return
+	}
+	numaflowClientSet = numaflowversioned.NewForConfigOrDie(restConfig)
+	//numaplaneClientSet := numaplaneversioned.NewForConfigOrDie(restConfig)
+	k8sClientSet, err = k8sclientgo.NewForConfig(restConfig)
+	return
+}
+
+// PrepareK8SEnvironmentWithWebhooks is like PrepareK8SEnvironment, but also installs Numaplane's webhook configurations:
+// the returned options give the host, port and certificate directory of the webhook server which they call, which the
+// test needs to start
+func PrepareK8SEnvironmentWithWebhooks() (restConfig *rest.Config, webhookOptions envtest.WebhookInstallOptions, numaplaneClient client.Client, err error) {
+	testEnv, err := newTestEnvironment()
+	if err != nil {
+		return
+	}
+	rootDirectory, err := findRootDirectory()
+	if err != nil {
+		return
+	}
+	testEnv.WebhookInstallOptions = envtest.WebhookInstallOptions{
+		Paths: []string{rootDirectory + "/config/webhook/manifests.yaml"},
+	}
+
+	restConfig, err = testEnv.Start()
+	if err != nil {
+		return
+	}
+	numaplaneClient, err = newNumaplaneClient(restConfig)
+	return restConfig, testEnv.WebhookInstallOptions, numaplaneClient, err
+}
 
-	// Set up a test Kubernetes environment which includes both our Numaplane and Numaflow CRDs
+// set up a test Kubernetes environment which includes both our Numaplane and Numaflow CRDs
+func newTestEnvironment() (*envtest.Environment, error) {
 
 	// Numaplane CRDs can be found in our repository
 	// Numaflow CRDs must be downloaded
@@ -31,7 +76,7 @@ func PrepareK8SEnvironment() (restConfig *rest.Config, numaflowClientSet *numafl
 	// find Numaplane CRD directory
 	rootDirectory, err := findRootDirectory()
 	if err != nil {
-		return
+		return nil, err
 	}
 	crdDirectory := rootDirectory + "/config/crd"
 
@@ -43,9 +88,8 @@ func PrepareK8SEnvironment() (restConfig *rest.Config, numaflowClientSet *numafl
 	}
 	externalCRDsDir := crdDirectory + "/external"
 	for _, crdURL := range crdsURLs {
-		err = downloadCRDToPath(crdURL, externalCRDsDir)
-		if err != nil {
-			return
+		if err := downloadCRDToPath(crdURL, externalCRDsDir); err != nil {
+			return nil, err
 		}
 	}
 
@@ -67,52 +111,35 @@ func PrepareK8SEnvironment() (restConfig *rest.Config, numaflowClientSet *numafl
 		UseExistingCluster: &useExistingCluster,
 	}
 
-	// create REST Config
-	restConfig, err = testEnv.Start()
-	if err != nil {
-		return
-	}
-
-	// create clients for Numaflow, Numaplane, Kubernetes
+	return testEnv, nil
+}
 
-	err = numaflowv1.AddToScheme(scheme.Scheme)
-	if err != nil {
-		return
-	}
-	err = apiv1.AddToScheme(scheme.Scheme)
-	if err != nil {
-		return
+// create a client for the Numaplane and Numaflow types
+func newNumaplaneClient(restConfig *rest.Config) (client.Client, error) {
+	if err := numaflowv1.AddToScheme(scheme.Scheme); err != nil {
+		return nil, err
 	}
-
-	numaflowClientSet = numaflowversioned.NewForConfigOrDie(restConfig)
-	//numaplaneClientSet := numaplaneversioned.NewForConfigOrDie(restConfig)
-	numaplaneClient, err = client.New(restConfig, client.Options{})
-	if err != nil {
-		return
+	if err := apiv1.AddToScheme(scheme.Scheme); err != nil {
+		return nil, err
 	}
-	k8sClientSet, err = k8sclientgo.NewForConfig(restConfig)
-	return
+	return client.New(restConfig, client.Options{})
 }
 
+// find the root directory of the repository, which has the go.mod file, by looking up from the working directory (which is
+// the directory of the package under test)
 func findRootDirectory() (string, error) {
-	// we know we should see "/numaplane/config/crd" - look for that in order to find the "numaplane" root
-	crdSubdirectory := "/config/crd"
-	path, _ := os.Getwd()
-
-	r := regexp.MustCompile(`/numaplane`)
-	matches := r.FindAllStringIndex(path, -1) // this returns a set of slices, where each slice represents the first and last index of the "/numaplane/" string
-	if matches == nil {
-		return "", fmt.Errorf("no occurrences of '/numaplane' found in path %q", path)
-	}
-	for _, occurrence := range matches {
-		endIndex := occurrence[1]
-		possibleCRDDirectory := path[0:endIndex] + crdSubdirectory
-		_, err := os.Stat(possibleCRDDirectory)
-		if err == nil {
-			return path[0:endIndex], nil
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for path := workingDirectory; ; path = filepath.Dir(path) {
+		if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+			return path, nil
+		}
+		if filepath.Dir(path) == path {
+			return "", fmt.Errorf("no go.mod found in any directory above the working directory %q", workingDirectory)
 		}
 	}
-	return "", fmt.Errorf("no occurrence of %q found in any higher level directory from current working directory %q", crdSubdirectory, path)
 }
 
 func downloadCRDToPath(url string, downloadDir string) error {